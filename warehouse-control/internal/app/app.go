@@ -6,30 +6,100 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"warehouse-control/internal/config"
+	"warehouse-control/internal/domain"
 	"warehouse-control/internal/grpc/sso"
+	alertsH "warehouse-control/internal/http-server/handler/alerts"
+	attachmentsH "warehouse-control/internal/http-server/handler/attachments"
 	authH "warehouse-control/internal/http-server/handler/auth"
+	barcodesH "warehouse-control/internal/http-server/handler/barcodes"
+	categoriesH "warehouse-control/internal/http-server/handler/categories"
+	exchangeRatesH "warehouse-control/internal/http-server/handler/exchange_rates"
 	historyH "warehouse-control/internal/http-server/handler/history"
+	importsH "warehouse-control/internal/http-server/handler/imports"
 	itemsH "warehouse-control/internal/http-server/handler/items"
+	kitsH "warehouse-control/internal/http-server/handler/kits"
+	labelsH "warehouse-control/internal/http-server/handler/labels"
+	locationsH "warehouse-control/internal/http-server/handler/locations"
+	lotsH "warehouse-control/internal/http-server/handler/lots"
+	movementsH "warehouse-control/internal/http-server/handler/movements"
+	ordersH "warehouse-control/internal/http-server/handler/orders"
+	productsH "warehouse-control/internal/http-server/handler/products"
+	purchaseOrdersH "warehouse-control/internal/http-server/handler/purchase_orders"
+	reservationsH "warehouse-control/internal/http-server/handler/reservations"
+	returnsH "warehouse-control/internal/http-server/handler/returns"
+	serialsH "warehouse-control/internal/http-server/handler/serials"
+	stocktakesH "warehouse-control/internal/http-server/handler/stocktakes"
+	suppliersH "warehouse-control/internal/http-server/handler/suppliers"
+	unitsH "warehouse-control/internal/http-server/handler/units"
+	valuationH "warehouse-control/internal/http-server/handler/valuation"
 	"warehouse-control/internal/http-server/middleware"
 	"warehouse-control/internal/http-server/router"
+	alertsRepo "warehouse-control/internal/repository/alerts/postgres"
+	attachmentsRepo "warehouse-control/internal/repository/attachments/postgres"
+	barcodesRepo "warehouse-control/internal/repository/barcodes/postgres"
+	categoriesRepo "warehouse-control/internal/repository/categories/postgres"
+	exchangeRatesRepo "warehouse-control/internal/repository/exchange_rates/postgres"
 	historyRepo "warehouse-control/internal/repository/history/postgres"
+	importsRepo "warehouse-control/internal/repository/imports/postgres"
 	itemsRepo "warehouse-control/internal/repository/items/postgres"
+	kitsRepo "warehouse-control/internal/repository/kits/postgres"
+	locationsRepo "warehouse-control/internal/repository/locations/postgres"
+	lotsRepo "warehouse-control/internal/repository/lots/postgres"
+	movementsRepo "warehouse-control/internal/repository/movements/postgres"
+	ordersRepo "warehouse-control/internal/repository/orders/postgres"
+	productsRepo "warehouse-control/internal/repository/products/postgres"
+	purchaseOrdersRepo "warehouse-control/internal/repository/purchase_orders/postgres"
+	reservationsRepo "warehouse-control/internal/repository/reservations/postgres"
+	returnsRepo "warehouse-control/internal/repository/returns/postgres"
+	serialsRepo "warehouse-control/internal/repository/serials/postgres"
+	stocktakesRepo "warehouse-control/internal/repository/stocktakes/postgres"
+	suppliersRepo "warehouse-control/internal/repository/suppliers/postgres"
+	unitsRepo "warehouse-control/internal/repository/units/postgres"
+	valuationRepo "warehouse-control/internal/repository/valuation/postgres"
+	"warehouse-control/internal/storage/filesystem"
+	alertsUc "warehouse-control/internal/usecase/alerts"
+	attachmentsUc "warehouse-control/internal/usecase/attachments"
+	barcodesUc "warehouse-control/internal/usecase/barcodes"
+	categoriesUc "warehouse-control/internal/usecase/categories"
+	exchangeRatesUc "warehouse-control/internal/usecase/exchange_rates"
 	historyUc "warehouse-control/internal/usecase/history"
+	importsUc "warehouse-control/internal/usecase/imports"
 	itemsUc "warehouse-control/internal/usecase/items"
+	kitsUc "warehouse-control/internal/usecase/kits"
+	labelsUc "warehouse-control/internal/usecase/labels"
+	locationsUc "warehouse-control/internal/usecase/locations"
+	lotsUc "warehouse-control/internal/usecase/lots"
+	movementsUc "warehouse-control/internal/usecase/movements"
+	ordersUc "warehouse-control/internal/usecase/orders"
+	productsUc "warehouse-control/internal/usecase/products"
+	purchaseOrdersUc "warehouse-control/internal/usecase/purchase_orders"
+	reservationsUc "warehouse-control/internal/usecase/reservations"
+	returnsUc "warehouse-control/internal/usecase/returns"
+	serialsUc "warehouse-control/internal/usecase/serials"
+	stocktakesUc "warehouse-control/internal/usecase/stocktakes"
+	suppliersUc "warehouse-control/internal/usecase/suppliers"
+	unitsUc "warehouse-control/internal/usecase/units"
+	valuationUc "warehouse-control/internal/usecase/valuation"
 
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/zlog"
 )
 
 type App struct {
-	cfg       *config.Config
-	logger    *zlog.Zerolog
-	server    *http.Server
-	db        *dbpg.DB
-	ssoClient *sso.Client
+	cfg          *config.Config
+	logger       *zlog.Zerolog
+	server       *http.Server
+	db           *dbpg.DB
+	ssoClient    *sso.Client
+	reservations *reservationsUc.ReservationsUsecase
+	alerts       *alertsUc.AlertsUsecase
+	imports      *importsUc.ImportsUsecase
+	stopWorkers  context.CancelFunc
+	workers      sync.WaitGroup
 }
 
 func NewApp(cfg *config.Config, logger *zlog.Zerolog) (*App, error) {
@@ -44,6 +114,14 @@ func NewApp(cfg *config.Config, logger *zlog.Zerolog) (*App, error) {
 		return nil, fmt.Errorf("db: %w", err)
 	}
 
+	attachmentStorage, err := filesystem.NewStorage(cfg.Attachments.Dir)
+	if err != nil {
+		if closeErr := db.Master.Close(); closeErr != nil {
+			logger.Error().Err(closeErr).Msg("failed to close db after storage init error")
+		}
+		return nil, fmt.Errorf("attachments storage: %w", err)
+	}
+
 	ssoClient, err := sso.NewClient(cfg)
 	if err != nil {
 		if closeErr := db.Master.Close(); closeErr != nil {
@@ -52,16 +130,79 @@ func NewApp(cfg *config.Config, logger *zlog.Zerolog) (*App, error) {
 		return nil, fmt.Errorf("sso: %w", err)
 	}
 
-	itemsR := itemsRepo.NewPostgresRepository(db, retries)
+	valuationR := valuationRepo.NewPostgresRepository(db, retries, domain.CostMethod(cfg.Valuation.DefaultMethod))
+	itemsR := itemsRepo.NewPostgresRepository(db, retries, valuationR)
 	historyR := historyRepo.NewPostgresRepository(db, retries)
-	itemsU := itemsUc.NewService(itemsR, logger)
+	movementsR := movementsRepo.NewPostgresRepository(db, retries, valuationR)
+	locationsR := locationsRepo.NewPostgresRepository(db, retries)
+	reservationsR := reservationsRepo.NewPostgresRepository(db, retries)
+	lotsR := lotsRepo.NewPostgresRepository(db, retries)
+	serialsR := serialsRepo.NewPostgresRepository(db, retries)
+	alertsR := alertsRepo.NewPostgresRepository(db, retries)
+	stocktakesR := stocktakesRepo.NewPostgresRepository(db, retries, movementsR)
+	suppliersR := suppliersRepo.NewPostgresRepository(db, retries)
+	purchaseOrdersR := purchaseOrdersRepo.NewPostgresRepository(db, retries, movementsR)
+	ordersR := ordersRepo.NewPostgresRepository(db, retries, movementsR)
+	returnsR := returnsRepo.NewPostgresRepository(db, retries, movementsR)
+	exchangeRatesR := exchangeRatesRepo.NewPostgresRepository(db, retries)
+	unitsR := unitsRepo.NewPostgresRepository(db, retries)
+	kitsR := kitsRepo.NewPostgresRepository(db, retries, movementsR)
+	productsR := productsRepo.NewPostgresRepository(db, retries)
+	categoriesR := categoriesRepo.NewPostgresRepository(db, retries)
+	attachmentsR := attachmentsRepo.NewPostgresRepository(db, retries)
+	barcodesR := barcodesRepo.NewPostgresRepository(db, retries)
+	importsR := importsRepo.NewPostgresRepository(db, retries)
+	alertsU := alertsUc.NewService(alertsR, logger)
+	exchangeRatesU := exchangeRatesUc.NewService(exchangeRatesR, cfg.Money.BaseCurrency, logger)
+	unitsU := unitsUc.NewService(unitsR, logger)
+	kitsU := kitsUc.NewService(kitsR, alertsU, logger)
+	productsU := productsUc.NewService(productsR, cfg.Money.BaseCurrency, logger)
+	categoriesU := categoriesUc.NewService(categoriesR, logger)
+	attachmentsU := attachmentsUc.NewService(attachmentsR, attachmentStorage, cfg.Attachments.MaxSize, cfg.Attachments.ThumbnailSize, logger)
+	labelsU := labelsUc.NewService(itemsR, locationsR, logger)
+	barcodesU := barcodesUc.NewService(barcodesR, itemsR, locationsR, lotsR, logger)
+	itemsU := itemsUc.NewService(itemsR, alertsU, exchangeRatesU, kitsU, productsU, categoriesU, attachmentsU, cfg.Money.BaseCurrency, cfg.Items.BulkLimit, logger)
 	historyU := historyUc.NewService(historyR, logger)
+	importsU := importsUc.NewService(importsR, itemsU, itemsR, unitsR, categoriesR,
+		cfg.Imports.MaxSize, cfg.Imports.MaxRows, cfg.Imports.SyncRows, cfg.Imports.QueueSize, logger)
+	movementsU := movementsUc.NewService(movementsR, alertsU, unitsU, logger)
+	locationsU := locationsUc.NewService(locationsR, logger)
+	reservationsU := reservationsUc.NewService(reservationsR, alertsU, cfg.Reservations.DefaultTTL, logger)
+	lotsU := lotsUc.NewService(lotsR, logger)
+	serialsU := serialsUc.NewService(serialsR, itemsR, logger)
+	stocktakesU := stocktakesUc.NewService(stocktakesR, alertsU, cfg.Stocktakes.BlindByDefault, logger)
+	suppliersU := suppliersUc.NewService(suppliersR, logger)
+	purchaseOrdersU := purchaseOrdersUc.NewService(purchaseOrdersR, alertsU, unitsU, logger)
+	ordersU := ordersUc.NewService(ordersR, alertsU, unitsU, cfg.Orders.AllocationTTL, logger)
+	returnsU := returnsUc.NewService(returnsR, alertsU, logger)
+	valuationU := valuationUc.NewService(valuationR, logger)
 	iH := itemsH.NewHandler(itemsU, logger)
 	hH := historyH.NewHandler(historyU, logger)
+	mH := movementsH.NewHandler(movementsU, logger)
+	lH := locationsH.NewHandler(locationsU, logger)
+	rH := reservationsH.NewHandler(reservationsU, logger)
+	ltH := lotsH.NewHandler(lotsU, logger)
+	sH := serialsH.NewHandler(serialsU, logger)
+	alH := alertsH.NewHandler(alertsU, logger)
+	stH := stocktakesH.NewHandler(stocktakesU, logger)
+	spH := suppliersH.NewHandler(suppliersU, logger)
+	poH := purchaseOrdersH.NewHandler(purchaseOrdersU, logger)
+	oH := ordersH.NewHandler(ordersU, logger)
+	rtH := returnsH.NewHandler(returnsU, logger)
+	vH := valuationH.NewHandler(valuationU, logger)
+	erH := exchangeRatesH.NewHandler(exchangeRatesU, logger)
+	unH := unitsH.NewHandler(unitsU, logger)
+	kH := kitsH.NewHandler(kitsU, logger)
+	pH := productsH.NewHandler(productsU, logger)
+	cH := categoriesH.NewHandler(categoriesU, logger)
+	atH := attachmentsH.NewHandler(attachmentsU, cfg.Attachments.MaxSize, logger)
+	lbH := labelsH.NewHandler(labelsU, logger)
+	bcH := barcodesH.NewHandler(barcodesU, logger)
+	imH := importsH.NewHandler(importsU, cfg.Imports.MaxSize, logger)
 	aH := authH.NewHandler(ssoClient, cfg, logger)
 
 	authMW := middleware.NewAuthMiddleware(cfg.JWT.Secret, logger)
-	r := router.New(iH, hH, aH, authMW, cfg, logger)
+	r := router.New(iH, hH, mH, lH, rH, ltH, sH, alH, stH, spH, poH, oH, rtH, vH, erH, unH, kH, pH, cH, atH, lbH, bcH, imH, aH, authMW, cfg, logger)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.Server.Addr,
@@ -72,15 +213,20 @@ func NewApp(cfg *config.Config, logger *zlog.Zerolog) (*App, error) {
 	}
 
 	return &App{
-		cfg:       cfg,
-		logger:    logger,
-		server:    srv,
-		db:        db,
-		ssoClient: ssoClient,
+		cfg:          cfg,
+		logger:       logger,
+		server:       srv,
+		db:           db,
+		ssoClient:    ssoClient,
+		reservations: reservationsU,
+		alerts:       alertsU,
+		imports:      importsU,
 	}, nil
 }
 
 func (a *App) Run() error {
+	a.startWorkers()
+
 	serverErrors := make(chan error, 1)
 
 	go func() {
@@ -101,6 +247,33 @@ func (a *App) Run() error {
 	return nil
 }
 
+// startWorkers launches the background jobs; Stop cancels them and waits
+// for them to return before closing the database.
+func (a *App) startWorkers() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopWorkers = cancel
+
+	a.workers.Add(3)
+	go func() {
+		defer a.workers.Done()
+		a.logger.Info().Dur("interval", a.cfg.Reservations.SweepInterval).Msg("reservation sweeper started")
+		a.reservations.RunSweeper(ctx, a.cfg.Reservations.SweepInterval)
+		a.logger.Info().Msg("reservation sweeper stopped")
+	}()
+	go func() {
+		defer a.workers.Done()
+		a.logger.Info().Dur("interval", a.cfg.Alerts.EvaluateInterval).Msg("alert evaluator started")
+		a.alerts.RunEvaluator(ctx, a.cfg.Alerts.EvaluateInterval)
+		a.logger.Info().Msg("alert evaluator stopped")
+	}()
+	go func() {
+		defer a.workers.Done()
+		a.logger.Info().Msg("import worker started")
+		a.imports.RunWorker(ctx)
+		a.logger.Info().Msg("import worker stopped")
+	}()
+}
+
 func (a *App) handleSignals() <-chan os.Signal {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -121,6 +294,11 @@ func (a *App) Stop() error {
 		hasError = true
 	}
 
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+		a.workers.Wait()
+	}
+
 	if a.ssoClient != nil {
 		if err := a.ssoClient.Close(); err != nil {
 			a.logger.Error().Err(err).Msg("failed to close SSO gRPC client")
@@ -41,6 +41,39 @@ type Config struct {
 		DelayMs  int     `env:"RETRIES_DELAY_MS" validate:"required"`
 		Backoff  float64 `env:"RETRIES_BACKOFF" validate:"required"`
 	}
+	Reservations struct {
+		DefaultTTL    time.Duration `env:"RESERVATIONS_DEFAULT_TTL" env-default:"24h"`
+		SweepInterval time.Duration `env:"RESERVATIONS_SWEEP_INTERVAL" env-default:"1m" validate:"gt=0"`
+	}
+	Alerts struct {
+		EvaluateInterval time.Duration `env:"ALERTS_EVALUATE_INTERVAL" env-default:"5m" validate:"gt=0"`
+	}
+	Orders struct {
+		AllocationTTL time.Duration `env:"ORDERS_ALLOCATION_TTL" env-default:"168h" validate:"gt=0"`
+	}
+	Money struct {
+		BaseCurrency string `env:"MONEY_BASE_CURRENCY" env-default:"RUB" validate:"len=3,uppercase"`
+	}
+	Valuation struct {
+		DefaultMethod string `env:"VALUATION_DEFAULT_METHOD" env-default:"fifo" validate:"oneof=fifo average"`
+	}
+	Stocktakes struct {
+		BlindByDefault bool `env:"STOCKTAKES_BLIND_BY_DEFAULT" env-default:"true"`
+	}
+	Attachments struct {
+		Dir           string `env:"ATTACHMENTS_DIR" env-default:"data/attachments"`
+		MaxSize       int64  `env:"ATTACHMENTS_MAX_SIZE" env-default:"10485760" validate:"gt=0"`
+		ThumbnailSize int    `env:"ATTACHMENTS_THUMBNAIL_SIZE" env-default:"256" validate:"gt=0"`
+	}
+	Items struct {
+		BulkLimit int `env:"ITEMS_BULK_LIMIT" env-default:"500" validate:"gt=0"`
+	}
+	Imports struct {
+		MaxSize   int64 `env:"IMPORTS_MAX_SIZE" env-default:"20971520" validate:"gt=0"`
+		MaxRows   int   `env:"IMPORTS_MAX_ROWS" env-default:"100000" validate:"gt=0"`
+		SyncRows  int   `env:"IMPORTS_SYNC_ROWS" env-default:"500" validate:"gte=0"`
+		QueueSize int   `env:"IMPORTS_QUEUE_SIZE" env-default:"10" validate:"gt=0"`
+	}
 	RateLimit struct {
 		Enabled  bool `env:"RATE_LIMIT_ENABLED" env-default:"true"`
 		Rate     int  `env:"RATE_LIMIT_RATE" env-default:"5"`
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+type AlertStatus string
+
+const (
+	AlertOpen         AlertStatus = "open"
+	AlertAcknowledged AlertStatus = "acknowledged"
+	AlertResolved     AlertStatus = "resolved"
+)
+
+func (s AlertStatus) IsValid() bool {
+	switch s {
+	case AlertOpen, AlertAcknowledged, AlertResolved:
+		return true
+	}
+	return false
+}
+
+// Alert is raised when an item's on-hand quantity falls below its reorder
+// level. Quantity and the reorder settings are captured at that moment.
+type Alert struct {
+	ID              int64
+	ItemID          int64
+	ItemName        string
+	ItemSKU         string
+	Quantity        int
+	ReorderLevel    int
+	ReorderQuantity int
+	Status          AlertStatus
+	AcknowledgedBy  string
+	AcknowledgedAt  *time.Time
+	ResolvedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+type AlertFilter struct {
+	Status *AlertStatus
+	ItemID *int64
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"mime"
+	"time"
+)
+
+// History actions logged when an attachment is added to or removed from an
+// item.
+const (
+	HistoryAttachmentAdded   = "ATTACHMENT_ADDED"
+	HistoryAttachmentRemoved = "ATTACHMENT_REMOVED"
+)
+
+// Attachment is a file kept with an item: a photo, a datasheet or a
+// certificate. The content lives in file storage under StorageKey; images
+// also get a thumbnail under ThumbnailKey.
+type Attachment struct {
+	ID           int64
+	ItemID       int64
+	FileName     string
+	ContentType  string
+	Size         int64
+	StorageKey   string
+	ThumbnailKey string
+	UploadedBy   string
+	CreatedAt    time.Time
+}
+
+// attachmentTypes are the sniffed content types files may have. Office
+// documents are zip archives to the sniffer.
+var attachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/bmp":       true,
+	"application/pdf": true,
+	"application/zip": true,
+	"text/plain":      true,
+}
+
+// MediaType strips the parameters off a content type, e.g. the charset of
+// "text/plain; charset=utf-8".
+func MediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mt
+}
+
+// IsAllowedAttachmentType reports whether a file of the sniffed content type
+// may be attached to an item.
+func IsAllowedAttachmentType(contentType string) bool {
+	return attachmentTypes[MediaType(contentType)]
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BarcodeType tells where a code on an item's packaging comes from.
+type BarcodeType string
+
+const (
+	// BarcodeGTIN is a manufacturer's trade item number: EAN-8, UPC-A,
+	// EAN-13 or GTIN-14.
+	BarcodeGTIN BarcodeType = "gtin"
+	// BarcodeSupplier is a code a supplier labels the item with.
+	BarcodeSupplier BarcodeType = "supplier"
+	// BarcodeInternal is a code printed in the warehouse.
+	BarcodeInternal BarcodeType = "internal"
+)
+
+func (t BarcodeType) IsValid() bool {
+	switch t {
+	case BarcodeGTIN, BarcodeSupplier, BarcodeInternal:
+		return true
+	}
+	return false
+}
+
+// ItemBarcode is one of the codes an item can be scanned by besides its SKU.
+// Codes are unique across all items.
+type ItemBarcode struct {
+	ID         int64
+	ItemID     int64
+	Code       string
+	Type       BarcodeType
+	SupplierID *int64
+	CreatedAt  time.Time
+}
+
+// Validate trims the code and checks it against its type: a GTIN must be 8,
+// 12, 13 or 14 digits with the right check digit.
+func (b *ItemBarcode) Validate() error {
+	b.Code = strings.TrimSpace(b.Code)
+	if b.Code == "" {
+		return fmt.Errorf("barcode is required")
+	}
+	if !b.Type.IsValid() {
+		return fmt.Errorf("unknown barcode type %q", b.Type)
+	}
+	if b.SupplierID != nil && b.Type != BarcodeSupplier {
+		return fmt.Errorf("only supplier barcodes name a supplier")
+	}
+	if b.Type == BarcodeGTIN && GTINKey(b.Code) == "" {
+		return fmt.Errorf("%q is not a valid GTIN", b.Code)
+	}
+	return nil
+}
+
+// GTINKey returns the code as a 14-digit GTIN, or "" when it is not a valid
+// GTIN. A UPC-A and the EAN-13 with a leading zero have the same key, so a
+// product is found whichever of them a scanner reports.
+func GTINKey(code string) string {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return ""
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	if GTINCheckDigit(code[:len(code)-1]) != code[len(code)-1] {
+		return ""
+	}
+	return strings.Repeat("0", 14-len(code)) + code
+}
+
+// GTINCheckDigit computes the check digit for the digits of a GTIN without
+// it: counting from the right, digits in odd positions weigh three.
+func GTINCheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// ScanTarget is what a scanned code resolved to.
+type ScanTarget string
+
+const (
+	ScanItem     ScanTarget = "item"
+	ScanLocation ScanTarget = "location"
+	ScanLot      ScanTarget = "lot"
+)
+
+// ScanResult is the object a scanned code stands for. Item is set for items
+// and lots; Location and Contents for locations; Lot for lots.
+type ScanResult struct {
+	Code     string
+	Target   ScanTarget
+	Item     *Item
+	Location *Location
+	Lot      *Lot
+	// Contents lists the items stocked in the location and its sublocations,
+	// up to a limit; ContentsTotal counts all of them.
+	Contents      []*LocationContent
+	ContentsTotal int
+}
+
+// LocationContent is the quantity of an item held in a location.
+type LocationContent struct {
+	ItemID   int64
+	SKU      string
+	Name     string
+	Quantity int
+}
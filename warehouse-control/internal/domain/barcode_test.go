@@ -0,0 +1,34 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGTINKey(t *testing.T) {
+	assert.Equal(t, "04006381333931", domain.GTINKey("4006381333931"))
+	assert.Equal(t, "00036000291452", domain.GTINKey("036000291452"), "UPC-A")
+	assert.Equal(t, domain.GTINKey("036000291452"), domain.GTINKey("0036000291452"))
+	assert.Equal(t, "00000096385074", domain.GTINKey("96385074"), "EAN-8")
+	assert.Equal(t, "10012345678902", domain.GTINKey("10012345678902"), "GTIN-14")
+
+	for _, bad := range []string{"4006381333932", "400638133393X", "12345", ""} {
+		assert.Empty(t, domain.GTINKey(bad), bad)
+	}
+}
+
+func TestItemBarcodeValidate(t *testing.T) {
+	b := &domain.ItemBarcode{Code: " 4006381333931 ", Type: domain.BarcodeGTIN}
+	assert.NoError(t, b.Validate())
+	assert.Equal(t, "4006381333931", b.Code)
+
+	supplierID := int64(3)
+	assert.NoError(t, (&domain.ItemBarcode{Code: "ACME-77", Type: domain.BarcodeSupplier, SupplierID: &supplierID}).Validate())
+	assert.Error(t, (&domain.ItemBarcode{Code: "ACME-77", Type: domain.BarcodeGTIN}).Validate())
+	assert.Error(t, (&domain.ItemBarcode{Code: "ACME-77", Type: domain.BarcodeInternal, SupplierID: &supplierID}).Validate())
+	assert.Error(t, (&domain.ItemBarcode{Code: "  ", Type: domain.BarcodeInternal}).Validate())
+	assert.Error(t, (&domain.ItemBarcode{Code: "X", Type: "ean"}).Validate())
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// CategorySeparator separates the levels of a category path.
+const CategorySeparator = "/"
+
+// Category is a node of the category tree. Items and products name their
+// category by Path, the names of the category and its ancestors joined by
+// CategorySeparator.
+type Category struct {
+	ID        int64
+	Name      string
+	ParentID  *int64
+	Path      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Children is filled in when the tree is built.
+	Children []*Category
+}
+
+// NormalizeCategoryPath trims every level of a category path and drops the
+// blank ones, so " Clothing / Shirts/" names Clothing/Shirts.
+func NormalizeCategoryPath(path string) string {
+	var parts []string
+	for _, part := range strings.Split(path, CategorySeparator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, CategorySeparator)
+}
+
+// BuildCategoryTree links the categories to their parents and returns the
+// roots. Categories keep their order among their siblings; a category whose
+// parent is not in the list is returned as a root.
+func BuildCategoryTree(categories []*Category) []*Category {
+	byID := make(map[int64]*Category, len(categories))
+	for _, c := range categories {
+		c.Children = nil
+		byID[c.ID] = c
+	}
+	roots := make([]*Category, 0)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCategoryPath(t *testing.T) {
+	assert.Equal(t, "Clothing/Shirts", domain.NormalizeCategoryPath(" Clothing / Shirts/"))
+	assert.Equal(t, "Tools", domain.NormalizeCategoryPath("//Tools"))
+	assert.Equal(t, "", domain.NormalizeCategoryPath(" / "))
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	flat := []*domain.Category{
+		{ID: 1, Name: "Clothing", Path: "Clothing"},
+		{ID: 2, Name: "Shirts", ParentID: id(1), Path: "Clothing/Shirts"},
+		{ID: 3, Name: "Polo", ParentID: id(2), Path: "Clothing/Shirts/Polo"},
+		{ID: 4, Name: "Shoes", ParentID: id(1), Path: "Clothing/Shoes"},
+		{ID: 5, Name: "Tools", Path: "Tools"},
+		{ID: 6, Name: "Orphan", ParentID: id(42), Path: "Gone/Orphan"},
+	}
+
+	roots := domain.BuildCategoryTree(flat)
+	require.Len(t, roots, 3)
+	assert.Equal(t, []int64{1, 5, 6}, []int64{roots[0].ID, roots[1].ID, roots[2].ID})
+
+	clothing := roots[0]
+	require.Len(t, clothing.Children, 2)
+	assert.Equal(t, "Shirts", clothing.Children[0].Name)
+	assert.Equal(t, "Shoes", clothing.Children[1].Name)
+	require.Len(t, clothing.Children[0].Children, 1)
+	assert.Equal(t, int64(3), clothing.Children[0].Children[0].ID)
+	assert.Empty(t, roots[1].Children)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+// CostPlaces is the number of fractional digits costs are kept to.
+const CostPlaces = 4
+
+type CostMethod string
+
+const (
+	// CostFIFO costs issues from the oldest receipts still in stock.
+	CostFIFO CostMethod = "fifo"
+	// CostAverage costs issues at the moving average cost of the stock on
+	// hand, recomputed on every receipt.
+	CostAverage CostMethod = "average"
+)
+
+func (m CostMethod) IsValid() bool {
+	switch m {
+	case CostFIFO, CostAverage:
+		return true
+	}
+	return false
+}
+
+// CostLayer is the part of a receipt that is still in stock. Layers are
+// consumed oldest first whatever the item's method, so they always add up to
+// the on-hand quantity.
+type CostLayer struct {
+	ID         int64
+	ItemID     int64
+	MovementID *int64
+	Quantity   int
+	Remaining  int
+	UnitCost   decimal.Decimal
+	CreatedAt  time.Time
+}
+
+// Valuation is the value of the inventory at a point in time, reconstructed
+// from the costs booked on stock movements up to then.
+type Valuation struct {
+	AsOf       time.Time
+	Quantity   int
+	Value      decimal.Decimal
+	ByCategory []*CategoryValuation
+	ByLocation []*LocationValuation
+}
+
+type CategoryValuation struct {
+	Category string
+	Quantity int
+	Value    decimal.Decimal
+}
+
+// LocationValuation values the stock held in a bin; a nil LocationID stands
+// for stock that is not assigned to any bin.
+type LocationValuation struct {
+	LocationID   *int64
+	LocationPath string
+	Quantity     int
+	Value        decimal.Decimal
+}
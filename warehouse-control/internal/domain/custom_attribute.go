@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of date attribute values.
+const DateLayout = "2006-01-02"
+
+type AttributeType string
+
+const (
+	AttributeString AttributeType = "string"
+	AttributeNumber AttributeType = "number"
+	AttributeEnum   AttributeType = "enum"
+	AttributeDate   AttributeType = "date"
+	AttributeBool   AttributeType = "bool"
+)
+
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeString, AttributeNumber, AttributeEnum, AttributeDate, AttributeBool:
+		return true
+	}
+	return false
+}
+
+// AttributeDefinition is a custom field of the items of a category and of
+// its subcategories. Options lists the allowed values of an enum.
+type AttributeDefinition struct {
+	ID         int64
+	CategoryID int64
+	Name       string
+	Type       AttributeType
+	Required   bool
+	Options    []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AttributeSchema is the set of custom attributes an item of a category may
+// have: those defined on the category and on its ancestors.
+type AttributeSchema []*AttributeDefinition
+
+// Validate checks custom attribute values against the schema and returns
+// them normalized: numbers as float64, booleans as bool, dates as
+// DateLayout strings. Numbers and booleans are also accepted as strings.
+// Null and blank values count as missing.
+func (s AttributeSchema) Validate(values map[string]any) (map[string]any, error) {
+	defs := make(map[string]*AttributeDefinition, len(s))
+	for _, d := range s {
+		defs[d.Name] = d
+	}
+	given := make(map[string]any, len(values))
+	for name, v := range values {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := defs[name]; !ok {
+			return nil, fmt.Errorf("unknown attribute %q", name)
+		}
+		given[name] = v
+	}
+
+	result := make(map[string]any, len(given))
+	for _, d := range s {
+		v, err := d.normalize(given[d.Name])
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", d.Name, err)
+		}
+		if v == nil {
+			if d.Required {
+				return nil, fmt.Errorf("attribute %s is required", d.Name)
+			}
+			continue
+		}
+		result[d.Name] = v
+	}
+	return result, nil
+}
+
+// normalize returns the value in its stored form, or nil when it is missing.
+func (d *AttributeDefinition) normalize(v any) (any, error) {
+	if s, ok := v.(string); ok {
+		if s = strings.TrimSpace(s); s == "" {
+			return nil, nil
+		}
+		v = s
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	switch d.Type {
+	case AttributeString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case AttributeEnum:
+		if s, ok := v.(string); ok {
+			if !slices.Contains(d.Options, s) {
+				return nil, fmt.Errorf("%q is not one of %s", s, strings.Join(d.Options, ", "))
+			}
+			return s, nil
+		}
+	case AttributeNumber:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case string:
+			if f, err := strconv.ParseFloat(n, 64); err == nil {
+				return f, nil
+			}
+		}
+	case AttributeBool:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			if parsed, err := strconv.ParseBool(b); err == nil {
+				return parsed, nil
+			}
+		}
+	case AttributeDate:
+		if s, ok := v.(string); ok {
+			t, err := time.Parse(DateLayout, s)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a YYYY-MM-DD date", s)
+			}
+			return t.Format(DateLayout), nil
+		}
+	}
+	return nil, fmt.Errorf("expected a %s, got %v", d.Type, v)
+}
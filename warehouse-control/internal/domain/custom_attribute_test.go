@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func electronicsSchema() domain.AttributeSchema {
+	return domain.AttributeSchema{
+		{Name: "voltage", Type: domain.AttributeNumber, Required: true},
+		{Name: "plug", Type: domain.AttributeEnum, Options: []string{"EU", "UK", "US"}},
+		{Name: "released", Type: domain.AttributeDate},
+		{Name: "wireless", Type: domain.AttributeBool},
+		{Name: "model", Type: domain.AttributeString},
+	}
+}
+
+func TestAttributeSchemaValidate(t *testing.T) {
+	got, err := electronicsSchema().Validate(map[string]any{
+		"Voltage":  "220",
+		"plug":     "EU",
+		"released": "2024-03-01",
+		"wireless": "true",
+		"model":    "  ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"voltage":  220.0,
+		"plug":     "EU",
+		"released": "2024-03-01",
+		"wireless": true,
+	}, got)
+
+	got, err = electronicsSchema().Validate(map[string]any{"voltage": 110.5, "wireless": false})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"voltage": 110.5, "wireless": false}, got)
+}
+
+func TestAttributeSchemaValidateRejects(t *testing.T) {
+	cases := map[string]map[string]any{
+		"missing required": {"plug": "EU"},
+		"null required":    {"voltage": nil},
+		"unknown":          {"voltage": 220.0, "colour": "red"},
+		"not a number":     {"voltage": "high"},
+		"not an option":    {"voltage": 220.0, "plug": "JP"},
+		"bad date":         {"voltage": 220.0, "released": "01.03.2024"},
+		"not a bool":       {"voltage": 220.0, "wireless": 1.0},
+		"not a string":     {"voltage": 220.0, "model": 42.0},
+	}
+	for name, values := range cases {
+		_, err := electronicsSchema().Validate(values)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestEmptySchema(t *testing.T) {
+	got, err := domain.AttributeSchema(nil).Validate(nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = domain.AttributeSchema(nil).Validate(map[string]any{"voltage": 220.0})
+	assert.Error(t, err)
+}
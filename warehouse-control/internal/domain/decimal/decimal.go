@@ -0,0 +1,235 @@
+// Package decimal implements the exact decimal numbers used for money and
+// costs. A Decimal is an arbitrary-precision integer with a number of
+// fractional digits, so sums and products are exact and only division
+// rounds, to a number of places the caller chooses.
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrSyntax = errors.New("invalid decimal")
+
+// Decimal is the value unscaled / 10^scale. The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+var Zero = Decimal{}
+
+var ten = big.NewInt(10)
+
+// New returns unscaled / 10^scale.
+func New(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+func FromInt(n int64) Decimal {
+	return New(n, 0)
+}
+
+// Parse reads a plain decimal such as "-12.50"; exponents are not accepted.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || len(s)-len(digits) > 1 {
+		return Zero, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" && fracPart == "" {
+		return Zero, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Zero, fmt.Errorf("%w: %q", ErrSyntax, s)
+		}
+	}
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Zero, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{unscaled: unscaled, scale: int32(len(fracPart))}, nil
+}
+
+// MustParse is Parse for constants; it panics on malformed input.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale returns the unscaled value of d at a scale not below its own.
+func (d Decimal) rescale(scale int32) *big.Int {
+	n := new(big.Int).Set(d.int())
+	if scale > d.scale {
+		n.Mul(n, new(big.Int).Exp(ten, big.NewInt(int64(scale-d.scale)), nil))
+	}
+	return n
+}
+
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Sub(o Decimal) Decimal {
+	return d.Add(o.Neg())
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// MulInt multiplies by a whole number, e.g. a unit cost by a quantity.
+func (d Decimal) MulInt(n int64) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), big.NewInt(n)), scale: d.scale}
+}
+
+// Div returns d / o rounded half away from zero to the given number of
+// fractional digits. It panics on division by zero, like integer division.
+func (d Decimal) Div(o Decimal, places int32) Decimal {
+	if o.Sign() == 0 {
+		panic("decimal: division by zero")
+	}
+	// d/o = (dU * 10^(places+oS)) / (oU * 10^dS), computed one digit past
+	// places for rounding.
+	num := new(big.Int).Mul(d.int(), new(big.Int).Exp(ten, big.NewInt(int64(places+1+o.scale)), nil))
+	den := new(big.Int).Mul(o.int(), new(big.Int).Exp(ten, big.NewInt(int64(d.scale)), nil))
+	q := new(big.Int).Quo(num, den)
+	return roundLastDigit(q, places)
+}
+
+// Round returns d rounded half away from zero to the given number of
+// fractional digits.
+func (d Decimal) Round(places int32) Decimal {
+	if places >= d.scale {
+		return Decimal{unscaled: d.rescale(places), scale: places}
+	}
+	q := new(big.Int).Quo(d.int(), new(big.Int).Exp(ten, big.NewInt(int64(d.scale-places-1)), nil))
+	return roundLastDigit(q, places)
+}
+
+// roundLastDigit drops the last digit of q, rounding half away from zero.
+func roundLastDigit(q *big.Int, places int32) Decimal {
+	r := new(big.Int)
+	q.QuoRem(q, ten, r)
+	if r.CmpAbs(big.NewInt(5)) >= 0 {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Decimal{unscaled: q, scale: places}
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than o.
+func (d Decimal) Cmp(o Decimal) int {
+	scale := max(d.scale, o.scale)
+	return d.rescale(scale).Cmp(o.rescale(scale))
+}
+
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Int64 returns d as a whole number. ok is false when d has a fractional
+// part or does not fit into an int64.
+func (d Decimal) Int64() (n int64, ok bool) {
+	q, r := new(big.Int).QuoRem(d.int(), new(big.Int).Exp(ten, big.NewInt(int64(d.scale)), nil), new(big.Int))
+	if r.Sign() != 0 || !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
+
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Scan reads NUMERIC columns, which the driver hands over as text.
+func (d *Decimal) Scan(src interface{}) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+	case []byte:
+		*d, err = Parse(string(v))
+	case string:
+		*d, err = Parse(v)
+	case int64:
+		*d = FromInt(v)
+	case float64:
+		*d, err = Parse(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		err = fmt.Errorf("decimal: cannot scan %T", src)
+	}
+	return err
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON writes the decimal as a string so clients never parse it into
+// a binary float by accident.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON accepts both "12.50" and 12.50.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
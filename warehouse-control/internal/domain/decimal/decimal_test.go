@@ -0,0 +1,90 @@
+package decimal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"warehouse-control/internal/domain/decimal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAndString(t *testing.T) {
+	for in, want := range map[string]string{
+		"12.50":  "12.50",
+		"-0.05":  "-0.05",
+		"7":      "7",
+		".5":     "0.5",
+		"+3.000": "3.000",
+	} {
+		d, err := decimal.Parse(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, d.String(), in)
+	}
+
+	for _, in := range []string{"", "-", "1e3", "1.2.3", "--1", "abc"} {
+		_, err := decimal.Parse(in)
+		assert.ErrorIs(t, err, decimal.ErrSyntax, in)
+	}
+}
+
+func TestArithmeticIsExact(t *testing.T) {
+	sum := decimal.Zero
+	for i := 0; i < 10; i++ {
+		sum = sum.Add(decimal.MustParse("0.1"))
+	}
+	assert.True(t, sum.Equal(decimal.FromInt(1)))
+
+	assert.Equal(t, "37.5000", decimal.MustParse("12.5000").MulInt(3).String())
+	assert.Equal(t, "-2.25", decimal.MustParse("1.5").Mul(decimal.MustParse("-1.5")).String())
+	assert.Equal(t, "0.75", decimal.MustParse("1").Sub(decimal.MustParse("0.25")).String())
+}
+
+func TestInt64(t *testing.T) {
+	n, ok := decimal.MustParse("24.000").Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(24), n)
+
+	n, ok = decimal.MustParse("-3").Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(-3), n)
+
+	_, ok = decimal.MustParse("2.5").Int64()
+	assert.False(t, ok)
+	_, ok = decimal.MustParse("99999999999999999999").Int64()
+	assert.False(t, ok)
+}
+
+func TestDivAndRoundHalfAwayFromZero(t *testing.T) {
+	assert.Equal(t, "3.3333", decimal.FromInt(10).Div(decimal.FromInt(3), 4).String())
+	assert.Equal(t, "0.6667", decimal.FromInt(2).Div(decimal.FromInt(3), 4).String())
+	assert.Equal(t, "-0.6667", decimal.FromInt(-2).Div(decimal.FromInt(3), 4).String())
+	assert.Equal(t, "2.50", decimal.MustParse("7.5").Div(decimal.MustParse("3"), 2).String())
+
+	assert.Equal(t, "1.24", decimal.MustParse("1.235").Round(2).String())
+	assert.Equal(t, "-1.24", decimal.MustParse("-1.235").Round(2).String())
+	assert.Equal(t, "1.2300", decimal.MustParse("1.23").Round(4).String())
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		A decimal.Decimal `json:"a"`
+		B decimal.Decimal `json:"b"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"a": 19.99, "b": "0.10"}`), &v))
+	assert.Equal(t, "19.99", v.A.String())
+	assert.Equal(t, "0.10", v.B.String())
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": "19.99", "b": "0.10"}`, string(out))
+}
+
+func TestScan(t *testing.T) {
+	var d decimal.Decimal
+	require.NoError(t, d.Scan([]byte("1024.5000")))
+	assert.Equal(t, "1024.5000", d.String())
+	require.NoError(t, d.Scan(nil))
+	assert.True(t, d.IsZero())
+}
@@ -3,13 +3,60 @@ package errors
 import "errors"
 
 var (
-	ErrItemNotFound       = errors.New("item not found")
-	ErrInvalidInput       = errors.New("invalid input")
-	ErrUnauthorized       = errors.New("unauthorized")
-	ErrForbidden          = errors.New("forbidden")
-	ErrDatabase           = errors.New("database error")
-	ErrInternal           = errors.New("internal error")
-	ErrTokenInvalid       = errors.New("token invalid")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrRateLimit          = errors.New("rate limit exceeded")
+	ErrItemNotFound          = errors.New("item not found")
+	ErrInvalidInput          = errors.New("invalid input")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrForbidden             = errors.New("forbidden")
+	ErrDatabase              = errors.New("database error")
+	ErrInternal              = errors.New("internal error")
+	ErrTokenInvalid          = errors.New("token invalid")
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrRateLimit             = errors.New("rate limit exceeded")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrLocationNotFound      = errors.New("location not found")
+	ErrLocationExists        = errors.New("location already exists")
+	ErrLocationInUse         = errors.New("location in use")
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationClosed     = errors.New("reservation is not active")
+	ErrLotNotFound           = errors.New("lot not found")
+	ErrSerialNotFound        = errors.New("serial number not found")
+	ErrSerialConflict        = errors.New("serial number already in stock")
+	ErrAlertNotFound         = errors.New("alert not found")
+	ErrAlertNotOpen          = errors.New("alert is not open")
+	ErrStocktakeNotFound     = errors.New("stocktake not found")
+	ErrStocktakeClosed       = errors.New("stocktake is not open for this operation")
+	ErrSupplierNotFound      = errors.New("supplier not found")
+	ErrSupplierExists        = errors.New("supplier already exists")
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+	ErrPurchaseOrderExists   = errors.New("purchase order number already exists")
+	ErrPurchaseOrderClosed   = errors.New("purchase order is closed")
+	ErrOverReceipt           = errors.New("receipt exceeds the ordered quantity")
+	ErrOrderNotFound         = errors.New("order not found")
+	ErrOrderExists           = errors.New("order number already exists")
+	ErrInvalidTransition     = errors.New("invalid order status transition")
+	ErrReturnNotFound        = errors.New("return not found")
+	ErrReturnExists          = errors.New("return number already exists")
+	ErrReturnClosed          = errors.New("return is not open for this operation")
+	ErrExchangeRateNotFound  = errors.New("exchange rate not found")
+	ErrUnitNotFound          = errors.New("unit not found")
+	ErrUnitExists            = errors.New("unit already exists")
+	ErrKitNotFound           = errors.New("item is not a kit")
+	ErrProductNotFound       = errors.New("product not found")
+	ErrProductExists         = errors.New("product SKU already exists")
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryExists        = errors.New("category already exists")
+	ErrCategoryInUse         = errors.New("category has subcategories, items or products")
+	ErrAttributeNotFound     = errors.New("attribute not found")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment exceeds the size limit")
+	ErrUnsupportedMediaType  = errors.New("unsupported attachment type")
+	ErrBarcodeNotFound       = errors.New("barcode not found")
+	ErrBarcodeExists         = errors.New("barcode already assigned")
+	ErrCodeNotRecognized     = errors.New("code not recognized")
+	ErrAmbiguousCode         = errors.New("code matches several lots")
+	ErrImportNotFound        = errors.New("import job not found")
+	ErrImportQueueFull       = errors.New("too many imports in progress")
+	ErrImportTooLarge        = errors.New("import file exceeds the size limit")
+	ErrItemExists            = errors.New("item SKU already exists")
+	ErrBulkAborted           = errors.New("not written: another item of the batch failed")
 )
@@ -0,0 +1,153 @@
+package domain
+
+// ExportFormat is the file format records are exported in.
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSONL ExportFormat = "jsonl"
+	ExportXLSX  ExportFormat = "xlsx"
+)
+
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case ExportCSV, ExportJSONL, ExportXLSX:
+		return true
+	}
+	return false
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportJSONL:
+		return "application/x-ndjson"
+	case ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// itemColumn is a column of an item export. Snapshot columns are the fields
+// kept in the item snapshots of history records.
+type itemColumn struct {
+	name     string
+	snapshot bool
+	value    func(*Item) any
+}
+
+// itemColumns are the columns of an item export. The columns an import reads
+// are named after the import fields, so an exported file can be imported
+// back. Values an item does not have are nil.
+var itemColumns = []itemColumn{
+	{"id", false, func(i *Item) any { return i.ID }},
+	{ImportSKU, true, func(i *Item) any { return i.SKU }},
+	{ImportName, true, func(i *Item) any { return i.Name }},
+	{ImportQuantity, true, func(i *Item) any { return i.Quantity }},
+	{"reserved", false, func(i *Item) any { return i.Reserved }},
+	{"available", false, func(i *Item) any { return i.Available() }},
+	{"quarantined", true, func(i *Item) any { return i.Quarantined }},
+	{"damaged", true, func(i *Item) any { return i.Damaged }},
+	{"blocked", true, func(i *Item) any { return i.Blocked }},
+	{ImportPrice, true, func(i *Item) any { return i.Price.Amount }},
+	{ImportCurrency, true, func(i *Item) any { return i.Price.Currency }},
+	{ImportCategory, true, func(i *Item) any { return i.Category }},
+	{ImportLocation, true, func(i *Item) any { return i.Location }},
+	{ImportSerialized, true, func(i *Item) any { return i.Serialized }},
+	{ImportBaseUnit, true, func(i *Item) any { return i.BaseUnit }},
+	{ImportReorderLevel, true, func(i *Item) any { return i.ReorderLevel }},
+	{ImportReorderQuantity, true, func(i *Item) any { return i.ReorderQuantity }},
+	{ImportCostMethod, true, func(i *Item) any {
+		if i.CostMethod == nil {
+			return nil
+		}
+		return string(*i.CostMethod)
+	}},
+	{ImportUnitCost, false, func(i *Item) any { return i.UnitCost }},
+	{"product_id", true, func(i *Item) any {
+		if i.ProductID == nil {
+			return nil
+		}
+		return *i.ProductID
+	}},
+	{"variant", true, func(i *Item) any {
+		if len(i.Variant) == 0 {
+			return nil
+		}
+		return i.Variant
+	}},
+	{"attributes", true, func(i *Item) any {
+		if len(i.Attributes) == 0 {
+			return nil
+		}
+		return i.Attributes
+	}},
+	{"warehouse_quantity", false, func(i *Item) any {
+		if i.WarehouseQuantity == nil {
+			return nil
+		}
+		return *i.WarehouseQuantity
+	}},
+	{"created_at", true, func(i *Item) any { return i.CreatedAt }},
+	{"updated_at", true, func(i *Item) any { return i.UpdatedAt }},
+}
+
+// ItemExportHeader names the columns of an item export.
+var ItemExportHeader = func() []string {
+	header := make([]string, len(itemColumns))
+	for i, c := range itemColumns {
+		header[i] = c.name
+	}
+	return header
+}()
+
+// ItemExportRow returns the values of an item in the columns of
+// ItemExportHeader.
+func ItemExportRow(item *Item) []any {
+	row := make([]any, len(itemColumns))
+	for i, c := range itemColumns {
+		row[i] = c.value(item)
+	}
+	return row
+}
+
+// HistoryExportHeader names the columns of a history export: the record
+// itself, then every snapshot field of the item before the change prefixed
+// with old_ and after it prefixed with new_.
+var HistoryExportHeader = func() []string {
+	header := []string{"id", "item_id", "action", "changed_by", "changed_at", "stocktake_id", "attachment"}
+	for _, prefix := range []string{"old_", "new_"} {
+		for _, c := range itemColumns {
+			if c.snapshot {
+				header = append(header, prefix+c.name)
+			}
+		}
+	}
+	return header
+}()
+
+// HistoryExportRow returns the values of a history record in the columns of
+// HistoryExportHeader. The columns of a missing snapshot are nil.
+func HistoryExportRow(rec *HistoryRecord) []any {
+	var stocktakeID, attachment any
+	if rec.StocktakeID != nil {
+		stocktakeID = *rec.StocktakeID
+	}
+	if rec.Attachment != nil {
+		attachment = rec.Attachment.FileName
+	}
+	row := make([]any, 0, len(HistoryExportHeader))
+	row = append(row, rec.ID, rec.ItemID, rec.Action, rec.ChangedBy, rec.ChangedAt, stocktakeID, attachment)
+	for _, item := range []*Item{rec.OldData, rec.NewData} {
+		for _, c := range itemColumns {
+			if !c.snapshot {
+				continue
+			}
+			if item == nil {
+				row = append(row, nil)
+			} else {
+				row = append(row, c.value(item))
+			}
+		}
+	}
+	return row
+}
@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemExportRow(t *testing.T) {
+	method := domain.CostFIFO
+	item := &domain.Item{ID: 3, SKU: "A-1", Quantity: 10, Reserved: 4, Damaged: 1, CostMethod: &method}
+
+	row := domain.ItemExportRow(item)
+	assert.Len(t, row, len(domain.ItemExportHeader))
+
+	values := make(map[string]any, len(row))
+	for i, h := range domain.ItemExportHeader {
+		values[h] = row[i]
+	}
+	assert.Equal(t, 5, values["available"])
+	assert.Equal(t, "fifo", values[domain.ImportCostMethod])
+	assert.Nil(t, values["product_id"])
+	assert.Nil(t, values["attributes"])
+}
+
+func TestHistoryExportRow(t *testing.T) {
+	rec := &domain.HistoryRecord{
+		ID: 1, ItemID: 3, Action: "UPDATE", ChangedBy: "admin",
+		NewData: &domain.Item{SKU: "A-1", Category: "Крепеж", Location: "A-01"},
+	}
+
+	row := domain.HistoryExportRow(rec)
+	assert.Len(t, row, len(domain.HistoryExportHeader))
+
+	values := make(map[string]any, len(row))
+	for i, h := range domain.HistoryExportHeader {
+		values[h] = row[i]
+	}
+	assert.Equal(t, "Крепеж", values["new_category"])
+	assert.Equal(t, "A-01", values["new_location"])
+	assert.Nil(t, values["old_category"], "a missing snapshot leaves its columns empty")
+	assert.Nil(t, values["stocktake_id"])
+	assert.NotContains(t, domain.HistoryExportHeader, "new_reserved", "reserved stock is not kept in snapshots")
+}
@@ -10,6 +10,11 @@ type HistoryRecord struct {
 	NewData   *Item
 	ChangedBy string
 	ChangedAt time.Time
+	// StocktakeID is set for changes posted by a stocktake session.
+	StocktakeID *int64
+	// Attachment is set, instead of the item snapshots, when an attachment
+	// was added to or removed from the item.
+	Attachment *Attachment
 }
 
 type HistoryFilter struct {
@@ -18,6 +23,8 @@ type HistoryFilter struct {
 	Username *string
 	DateFrom *time.Time
 	DateTo   *time.Time
-	Limit    int
-	Offset   int
+	// StocktakeID limits the history to changes posted by that session.
+	StocktakeID *int64
+	Limit       int
+	Offset      int
 }
@@ -1,15 +1,102 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
 
 type Item struct {
 	ID        int64
 	Name      string
 	SKU       string
 	Quantity  int
-	Price     float64
+	Price     Money
 	Category  string
 	Location  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Serialized items are tracked per unit: every movement has to name the
+	// serial numbers it moves.
+	Serialized bool
+
+	// BaseUnit is the unit Quantity and all stock of the item are kept in.
+	BaseUnit string
+
+	// ProductID is set for a variant of a product; Variant holds its
+	// attribute values.
+	ProductID *int64
+	Variant   map[string]string
+
+	// Attributes holds the custom attribute values defined by the schema of
+	// the item's category.
+	Attributes map[string]any
+
+	// CostMethod overrides the configured default costing method when set.
+	CostMethod *CostMethod
+	// UnitCost is the average cost of the stock on hand. On create it is the
+	// cost the initial stock is received at.
+	UnitCost decimal.Decimal
+
+	// ReorderLevel is the minimum on-hand quantity; falling below it raises a
+	// low-stock alert suggesting ReorderQuantity. Zero disables alerting.
+	ReorderLevel    int
+	ReorderQuantity int
+
+	// Reserved is the quantity held by active reservations.
+	Reserved int
+	// Quarantined, Damaged and Blocked are the parts of Quantity held out of
+	// availability by stock status; they cannot be reserved or issued.
+	Quarantined int
+	Damaged     int
+	Blocked     int
+	// Stock is the per-bin breakdown of Quantity. Stock that has not been put
+	// away into a bin yet is counted in Quantity only.
+	Stock []*ItemStock
+	// WarehouseQuantity is set when items are listed for a single warehouse.
+	WarehouseQuantity *int
+	// Kit is the bill of materials of a kit item, with the availability of
+	// its components; set when a single item is read.
+	Kit *Kit
+	// DisplayPrice is Price converted into the currency the items were
+	// requested in.
+	DisplayPrice *Money
+}
+
+// Available returns the on-hand quantity that is neither reserved nor held
+// in another stock status.
+func (i *Item) Available() int {
+	return i.StatusQuantity(StockAvailable) - i.Reserved
+}
+
+// StatusQuantity returns the part of Quantity in the given stock status,
+// reserved stock included for StockAvailable.
+func (i *Item) StatusQuantity(s StockStatus) int {
+	switch s {
+	case StockQuarantine:
+		return i.Quarantined
+	case StockDamaged:
+		return i.Damaged
+	case StockBlocked:
+		return i.Blocked
+	}
+	return i.Quantity - i.Quarantined - i.Damaged - i.Blocked
+}
+
+type ItemFilter struct {
+	Search      string
+	WarehouseID *int64
+	// Status keeps the items with stock in the status; for StockAvailable
+	// the stock must also be unreserved.
+	Status *StockStatus
+	// ProductID keeps the variants of one product.
+	ProductID *int64
+	// CategoryID keeps the items of a category and of all its descendants.
+	CategoryID *int64
+	// Attributes keeps the items whose custom attributes have the given
+	// values, compared as text.
+	Attributes map[string]string
+	Limit      int
+	Offset     int
 }
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+// BulkMode tells what a bulk create or update does when some items fail:
+// BulkAtomic writes nothing unless every item succeeds, BulkBestEffort writes
+// the items that succeed.
+type BulkMode string
+
+const (
+	BulkAtomic     BulkMode = "atomic"
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+func (m BulkMode) IsValid() bool {
+	return m == BulkAtomic || m == BulkBestEffort
+}
+
+// BulkItemResult is the outcome for one item of a bulk create or update:
+// the ID of the item written, or why it was not.
+type BulkItemResult struct {
+	ID  int64
+	Err error
+}
+
+// BulkItemError is the failure of the item at Index of a batch written in a
+// single transaction.
+type BulkItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BulkItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BulkItemError) Unwrap() error {
+	return e.Err
+}
+
+// ItemPatch is a partial update of an item. Nil and empty fields leave the
+// item's value alone.
+type ItemPatch struct {
+	Name            string
+	SKU             string
+	Quantity        *int
+	Price           *decimal.Decimal
+	Currency        string
+	Category        string
+	Location        string
+	Serialized      *bool
+	BaseUnit        string
+	ReorderLevel    *int
+	ReorderQuantity *int
+	// CostMethod set to "" drops the override in favour of the default.
+	CostMethod *string
+	// Attributes replaces all custom attribute values when set.
+	Attributes map[string]any
+}
+
+// Apply sets the fields of the patch on the item.
+func (p *ItemPatch) Apply(item *Item) {
+	if p.Name != "" {
+		item.Name = p.Name
+	}
+	if p.SKU != "" {
+		item.SKU = p.SKU
+	}
+	if p.Quantity != nil {
+		item.Quantity = *p.Quantity
+	}
+	if p.Price != nil {
+		item.Price.Amount = *p.Price
+	}
+	if p.Currency != "" {
+		item.Price.Currency = p.Currency
+	}
+	if p.Category != "" {
+		item.Category = p.Category
+	}
+	if p.Location != "" {
+		item.Location = p.Location
+	}
+	if p.Serialized != nil {
+		item.Serialized = *p.Serialized
+	}
+	if p.BaseUnit != "" {
+		item.BaseUnit = p.BaseUnit
+	}
+	if p.Attributes != nil {
+		item.Attributes = p.Attributes
+	}
+	if p.ReorderLevel != nil {
+		item.ReorderLevel = *p.ReorderLevel
+	}
+	if p.ReorderQuantity != nil {
+		item.ReorderQuantity = *p.ReorderQuantity
+	}
+	if p.CostMethod != nil {
+		item.CostMethod = nil
+		if *p.CostMethod != "" {
+			method := CostMethod(*p.CostMethod)
+			item.CostMethod = &method
+		}
+	}
+}
+
+// ItemUpdate is an item of a bulk update.
+type ItemUpdate struct {
+	ID    int64
+	Patch *ItemPatch
+}
@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+// ImportFormat is the file format items are imported from.
+type ImportFormat string
+
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportXLSX ImportFormat = "xlsx"
+)
+
+// ImportMode tells what an import does with a SKU that already exists:
+// ImportCreate reports the row as failed, ImportUpsert updates the item with
+// the columns of the row.
+type ImportMode string
+
+const (
+	ImportCreate ImportMode = "create"
+	ImportUpsert ImportMode = "upsert"
+)
+
+func (m ImportMode) IsValid() bool {
+	return m == ImportCreate || m == ImportUpsert
+}
+
+type ImportStatus string
+
+const (
+	ImportQueued  ImportStatus = "queued"
+	ImportRunning ImportStatus = "running"
+	ImportDone    ImportStatus = "done"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportJob is an import of an item file. A dry run checks every row and
+// counts what would be created and updated without writing.
+type ImportJob struct {
+	ID            int64
+	FileName      string
+	Format        ImportFormat
+	Mode          ImportMode
+	DryRun        bool
+	Status        ImportStatus
+	TotalRows     int
+	ProcessedRows int
+	Created       int
+	Updated       int
+	Failed        int
+	// Errors holds the first MaxImportErrors row errors; Failed counts all.
+	Errors     []*ImportRowError
+	Message    string
+	CreatedBy  string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// ImportRequest is how a file is to be imported. Mapping names the file
+// column for an item field, see MapImportColumns.
+type ImportRequest struct {
+	FileName string
+	Mode     ImportMode
+	DryRun   bool
+	Mapping  map[string]string
+}
+
+// MaxImportErrors bounds the row errors kept with a job.
+const MaxImportErrors = 1000
+
+// AddError records a failed row.
+func (j *ImportJob) AddError(row int, sku string, err error) {
+	j.Failed++
+	if len(j.Errors) < MaxImportErrors {
+		j.Errors = append(j.Errors, &ImportRowError{Row: row, SKU: sku, Error: err.Error()})
+	}
+}
+
+// ImportRowError is why a row was not imported. Row is the line of the file,
+// the header being line 1.
+type ImportRowError struct {
+	Row   int
+	SKU   string
+	Error string
+}
+
+// Item fields a file column can be mapped to. A column mapped to
+// ImportAttributePrefix+name holds the custom attribute name.
+const (
+	ImportName            = "name"
+	ImportSKU             = "sku"
+	ImportQuantity        = "quantity"
+	ImportPrice           = "price"
+	ImportCurrency        = "currency"
+	ImportCategory        = "category"
+	ImportLocation        = "location"
+	ImportSerialized      = "serialized"
+	ImportBaseUnit        = "base_unit"
+	ImportReorderLevel    = "reorder_level"
+	ImportReorderQuantity = "reorder_quantity"
+	ImportCostMethod      = "cost_method"
+	ImportUnitCost        = "unit_cost"
+
+	ImportAttributePrefix = "attr."
+)
+
+var importFields = map[string]bool{
+	ImportName: true, ImportSKU: true, ImportQuantity: true, ImportPrice: true,
+	ImportCurrency: true, ImportCategory: true, ImportLocation: true,
+	ImportSerialized: true, ImportBaseUnit: true, ImportReorderLevel: true,
+	ImportReorderQuantity: true, ImportCostMethod: true, ImportUnitCost: true,
+}
+
+// ImportColumns maps item fields to the indexes of the file columns holding
+// them.
+type ImportColumns map[string]int
+
+// MapImportColumns resolves the columns of a file from its header. mapping
+// names the header of the column for a field; fields it leaves out are
+// looked up by their own name, unless the column is mapped elsewhere.
+// Headers are compared case-insensitively and columns that map to no field
+// are ignored. The sku column is required.
+func MapImportColumns(header []string, mapping map[string]string) (ImportColumns, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if _, ok := index[h]; !ok && h != "" {
+			index[h] = i
+		}
+	}
+
+	columns := make(ImportColumns)
+	used := make(map[int]bool, len(mapping))
+	for field, h := range mapping {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !isImportField(field) {
+			return nil, fmt.Errorf("cannot map a column to unknown field %q", field)
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(h))]
+		if !ok {
+			return nil, fmt.Errorf("the file has no column %q for %s", h, field)
+		}
+		columns[field] = i
+		used[i] = true
+	}
+	for h, i := range index {
+		if _, mapped := columns[h]; !mapped && !used[i] && isImportField(h) {
+			columns[h] = i
+		}
+	}
+	if _, ok := columns[ImportSKU]; !ok {
+		return nil, fmt.Errorf("the file has no %s column", ImportSKU)
+	}
+	return columns, nil
+}
+
+func isImportField(field string) bool {
+	if name, ok := strings.CutPrefix(field, ImportAttributePrefix); ok {
+		return name != ""
+	}
+	return importFields[field]
+}
+
+// ImportRow is a parsed line of an import file. Fields lists the fields its
+// non-blank cells set; blank cells leave a field alone.
+type ImportRow struct {
+	Line   int
+	SKU    string
+	Fields map[string]string
+}
+
+// ParseRow reads the mapped cells of a record. It returns nil for a blank
+// record.
+func (c ImportColumns) ParseRow(line int, record []string) *ImportRow {
+	row := &ImportRow{Line: line, Fields: make(map[string]string, len(c))}
+	for field, i := range c {
+		if i >= len(record) {
+			continue
+		}
+		if v := strings.TrimSpace(record[i]); v != "" {
+			row.Fields[field] = v
+		}
+	}
+	if len(row.Fields) == 0 {
+		return nil
+	}
+	row.SKU = row.Fields[ImportSKU]
+	return row
+}
+
+// Apply sets the fields of the row on the item: on a new item to create it,
+// on an existing one to update it. Attribute cells are merged into the
+// item's attributes. Errors name the field whose cell could not be read.
+func (r *ImportRow) Apply(item *Item) error {
+	fields := make([]string, 0, len(r.Fields))
+	for f := range r.Fields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		v := r.Fields[field]
+		if name, ok := strings.CutPrefix(field, ImportAttributePrefix); ok {
+			if item.Attributes == nil {
+				item.Attributes = make(map[string]any)
+			}
+			item.Attributes[strings.ToLower(name)] = v
+			continue
+		}
+		var err error
+		switch field {
+		case ImportName:
+			item.Name = v
+		case ImportSKU:
+			item.SKU = v
+		case ImportQuantity:
+			item.Quantity, err = strconv.Atoi(v)
+		case ImportPrice:
+			item.Price.Amount, err = parseImportDecimal(v)
+		case ImportCurrency:
+			item.Price.Currency = v
+		case ImportCategory:
+			item.Category = v
+		case ImportLocation:
+			item.Location = v
+		case ImportSerialized:
+			item.Serialized, err = strconv.ParseBool(strings.ToLower(v))
+		case ImportBaseUnit:
+			item.BaseUnit = v
+		case ImportReorderLevel:
+			item.ReorderLevel, err = strconv.Atoi(v)
+		case ImportReorderQuantity:
+			item.ReorderQuantity, err = strconv.Atoi(v)
+		case ImportCostMethod:
+			method := CostMethod(strings.ToLower(v))
+			item.CostMethod = &method
+		case ImportUnitCost:
+			item.UnitCost, err = parseImportDecimal(v)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: cannot read %q", field, v)
+		}
+	}
+	return nil
+}
+
+// parseImportDecimal accepts a decimal comma, as spreadsheets in many
+// locales write it.
+func parseImportDecimal(s string) (decimal.Decimal, error) {
+	return decimal.Parse(strings.Replace(s, ",", ".", 1))
+}
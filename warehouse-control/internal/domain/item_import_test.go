@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapImportColumns(t *testing.T) {
+	header := []string{"Артикул", "Name", "Цена", "price", "attr.Voltage", "comment"}
+
+	columns, err := domain.MapImportColumns(header, map[string]string{"sku": "артикул", "price": "Цена"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ImportColumns{"sku": 0, "name": 1, "price": 2, "attr.voltage": 4}, columns)
+
+	_, err = domain.MapImportColumns(header, nil)
+	assert.Error(t, err, "no sku column")
+	_, err = domain.MapImportColumns(header, map[string]string{"sku": "Артикул", "colour": "comment"})
+	assert.Error(t, err, "unknown field")
+	_, err = domain.MapImportColumns(header, map[string]string{"sku": "code"})
+	assert.Error(t, err, "missing column")
+}
+
+func TestImportRowApply(t *testing.T) {
+	columns := domain.ImportColumns{"sku": 0, "name": 1, "quantity": 2, "price": 3, "serialized": 4, "attr.voltage": 5}
+
+	assert.Nil(t, columns.ParseRow(2, []string{" ", "", ""}))
+
+	row := columns.ParseRow(3, []string{"SKU-1", " Болт ", "10", "12,50", "", "220"})
+	require.NotNil(t, row)
+	assert.Equal(t, "SKU-1", row.SKU)
+
+	item := &domain.Item{Name: "old", Serialized: true, Attributes: map[string]any{"colour": "red"}}
+	require.NoError(t, row.Apply(item))
+	assert.Equal(t, "Болт", item.Name)
+	assert.Equal(t, 10, item.Quantity)
+	assert.True(t, item.Price.Amount.Equal(decimal.MustParse("12.50")))
+	assert.True(t, item.Serialized, "a blank cell leaves the field alone")
+	assert.Equal(t, map[string]any{"colour": "red", "voltage": "220"}, item.Attributes)
+
+	row = columns.ParseRow(4, []string{"SKU-2", "", "ten"})
+	err := row.Apply(&domain.Item{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quantity")
+}
+
+func TestImportJobAddError(t *testing.T) {
+	job := &domain.ImportJob{}
+	for i := 0; i < domain.MaxImportErrors+5; i++ {
+		job.AddError(i+2, "SKU", errors.New("bad"))
+	}
+	assert.Equal(t, domain.MaxImportErrors+5, job.Failed)
+	assert.Len(t, job.Errors, domain.MaxImportErrors)
+}
@@ -0,0 +1,34 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemStatusQuantity(t *testing.T) {
+	item := &domain.Item{Quantity: 20, Reserved: 4, Quarantined: 3, Damaged: 2, Blocked: 1}
+
+	assert.Equal(t, 14, item.StatusQuantity(domain.StockAvailable))
+	assert.Equal(t, 3, item.StatusQuantity(domain.StockQuarantine))
+	assert.Equal(t, 2, item.StatusQuantity(domain.StockDamaged))
+	assert.Equal(t, 1, item.StatusQuantity(domain.StockBlocked))
+	assert.Equal(t, 10, item.Available())
+}
+
+func TestItemPatchApply(t *testing.T) {
+	method := domain.CostAverage
+	item := &domain.Item{Name: "Болт", SKU: "A-1", Quantity: 5, Category: "Крепеж", CostMethod: &method}
+
+	quantity, none := 0, ""
+	patch := &domain.ItemPatch{Name: "Болт М8", Quantity: &quantity, CostMethod: &none}
+	patch.Apply(item)
+
+	assert.Equal(t, "Болт М8", item.Name)
+	assert.Equal(t, "A-1", item.SKU, "empty fields are left alone")
+	assert.Equal(t, "Крепеж", item.Category)
+	assert.Equal(t, 0, item.Quantity, "a set pointer applies its zero value")
+	assert.Nil(t, item.CostMethod, "an empty cost method drops the override")
+}
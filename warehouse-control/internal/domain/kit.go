@@ -0,0 +1,48 @@
+package domain
+
+// KitComponent is Quantity units of a component item that go into one unit
+// of a kit.
+type KitComponent struct {
+	KitID    int64
+	ItemID   int64
+	SKU      string
+	ItemName string
+	Quantity int
+	// Available is the available stock of the component when the kit is read.
+	Available int
+}
+
+// Kit is the bill of materials of an item assembled from other items.
+type Kit struct {
+	ItemID     int64
+	Components []*KitComponent
+}
+
+// Available returns how many kits the available stock of the components is
+// enough to assemble.
+func (k *Kit) Available() int {
+	if len(k.Components) == 0 {
+		return 0
+	}
+	available := -1
+	for _, c := range k.Components {
+		n := max(c.Available, 0) / c.Quantity
+		if available < 0 || n < available {
+			available = n
+		}
+	}
+	return available
+}
+
+// KitAssembly assembles Quantity kits from their components or, with
+// Disassemble, breaks kits back down into them. Components and kits are
+// taken from and put into the bin LocationID, or the unassigned pool.
+type KitAssembly struct {
+	KitID       int64
+	Quantity    int
+	Disassemble bool
+	LocationID  *int64
+	Reference   string
+	// Movements are the stock movements the operation booked.
+	Movements []*StockMovement
+}
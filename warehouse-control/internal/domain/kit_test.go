@@ -0,0 +1,23 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKitAvailable(t *testing.T) {
+	kit := &domain.Kit{Components: []*domain.KitComponent{
+		{ItemID: 1, Quantity: 2, Available: 9},
+		{ItemID: 2, Quantity: 1, Available: 7},
+		{ItemID: 3, Quantity: 3, Available: 12},
+	}}
+	assert.Equal(t, 4, kit.Available())
+
+	kit.Components[1].Available = -2
+	assert.Equal(t, 0, kit.Available())
+
+	assert.Equal(t, 0, (&domain.Kit{}).Available())
+}
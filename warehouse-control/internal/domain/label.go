@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Symbology is the barcode a label encodes its code in.
+type Symbology string
+
+const (
+	SymbologyCode128 Symbology = "code128"
+	SymbologyEAN13   Symbology = "ean13"
+	SymbologyQR      Symbology = "qr"
+)
+
+func (s Symbology) IsValid() bool {
+	switch s {
+	case SymbologyCode128, SymbologyEAN13, SymbologyQR:
+		return true
+	}
+	return false
+}
+
+// LabelFormat is the output a single label is rendered to.
+type LabelFormat string
+
+const (
+	LabelPNG LabelFormat = "png"
+	LabelSVG LabelFormat = "svg"
+	LabelZPL LabelFormat = "zpl"
+)
+
+func (f LabelFormat) IsValid() bool {
+	switch f {
+	case LabelPNG, LabelSVG, LabelZPL:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the media type of a label in the format.
+func (f LabelFormat) ContentType() string {
+	switch f {
+	case LabelSVG:
+		return "image/svg+xml"
+	case LabelZPL:
+		return "application/zpl"
+	}
+	return "image/png"
+}
+
+// Label is what gets printed for an item or a bin: Code in a barcode, with
+// Title and the code in plain text around it.
+type Label struct {
+	Code  string
+	Title string
+}
+
+// LabelSheetRequest selects the items and bins to print on a sheet.
+type LabelSheetRequest struct {
+	ItemIDs     []int64
+	LocationIDs []int64
+	Symbology   Symbology
+}
+
+// NormalizeEAN13 checks that the code can be printed as EAN-13 and returns
+// it with its check digit: twelve digits get one computed, thirteen must
+// carry the right one.
+func NormalizeEAN13(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if len(code) != 12 && len(code) != 13 {
+		return "", fmt.Errorf("EAN-13 needs 12 or 13 digits, got %q", code)
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("EAN-13 needs digits only, got %q", code)
+		}
+	}
+	check := EAN13CheckDigit(code[:12])
+	if len(code) == 13 && code[12] != check {
+		return "", fmt.Errorf("%q has a wrong check digit, expected %c", code, check)
+	}
+	return code[:12] + string(check), nil
+}
+
+// EAN13CheckDigit computes the check digit of the first twelve digits of an
+// EAN-13.
+func EAN13CheckDigit(digits string) byte {
+	return GTINCheckDigit(digits[:12])
+}
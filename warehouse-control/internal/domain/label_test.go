@@ -0,0 +1,25 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEAN13(t *testing.T) {
+	code, err := domain.NormalizeEAN13("400638133393")
+	require.NoError(t, err)
+	assert.Equal(t, "4006381333931", code)
+
+	code, err = domain.NormalizeEAN13(" 5901234123457 ")
+	require.NoError(t, err)
+	assert.Equal(t, "5901234123457", code)
+
+	for _, bad := range []string{"5901234123458", "TSHIRT-M-RED", "12345", "59012341234X"} {
+		_, err := domain.NormalizeEAN13(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+type LocationType string
+
+const (
+	LocationWarehouse LocationType = "warehouse"
+	LocationZone      LocationType = "zone"
+	LocationAisle     LocationType = "aisle"
+	LocationBin       LocationType = "bin"
+)
+
+func (t LocationType) IsValid() bool {
+	switch t {
+	case LocationWarehouse, LocationZone, LocationAisle, LocationBin:
+		return true
+	}
+	return false
+}
+
+// ParentType returns the level a location of this type must be placed under.
+// Warehouses are roots and return an empty type.
+func (t LocationType) ParentType() LocationType {
+	switch t {
+	case LocationZone:
+		return LocationWarehouse
+	case LocationAisle:
+		return LocationZone
+	case LocationBin:
+		return LocationAisle
+	}
+	return ""
+}
+
+type Location struct {
+	ID          int64
+	ParentID    *int64
+	WarehouseID int64
+	Type        LocationType
+	Code        string
+	Name        string
+	Path        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type LocationFilter struct {
+	Type        *LocationType
+	ParentID    *int64
+	WarehouseID *int64
+	Limit       int
+	Offset      int
+}
+
+// ItemStock is the quantity of an item held in a single bin.
+type ItemStock struct {
+	ItemID       int64
+	LocationID   int64
+	LocationPath string
+	WarehouseID  int64
+	Quantity     int
+}
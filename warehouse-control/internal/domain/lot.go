@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// Lot is a batch of an item sharing a lot number and expiry date. Stock that
+// was received without a lot number is tracked on the item only.
+type Lot struct {
+	ID             int64
+	ItemID         int64
+	LotNumber      string
+	ManufacturedAt *time.Time
+	ExpiresAt      *time.Time
+	Quantity       int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsExpired reports whether the lot's expiry date is before the day of now.
+func (l *Lot) IsExpired(now time.Time) bool {
+	if l.ExpiresAt == nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return l.ExpiresAt.Before(today)
+}
+
+type LotFilter struct {
+	ItemID *int64
+	// ExpiringWithinDays limits the result to lots with stock that expire
+	// within the given number of days, including already expired ones.
+	ExpiringWithinDays *int
+	IncludeEmpty       bool
+	Limit              int
+	Offset             int
+}
+
+// LotAllocation is the part of a movement booked against a single lot.
+type LotAllocation struct {
+	LotID     int64
+	LotNumber string
+	Quantity  int
+}
+
+// AllocateFEFO takes quantity from lots in the given order, which callers
+// sort by expiry (first-expiring-first-out). It returns the allocations and
+// the quantity that the lots could not cover.
+func AllocateFEFO(lots []*Lot, quantity int) ([]*LotAllocation, int) {
+	allocations := make([]*LotAllocation, 0)
+	for _, lot := range lots {
+		if quantity == 0 {
+			break
+		}
+		if lot.Quantity <= 0 {
+			continue
+		}
+		take := min(lot.Quantity, quantity)
+		allocations = append(allocations, &LotAllocation{
+			LotID:     lot.ID,
+			LotNumber: lot.LotNumber,
+			Quantity:  take,
+		})
+		quantity -= take
+	}
+	return allocations, quantity
+}
+
+type LotHistoryRecord struct {
+	ID        int64
+	LotID     int64
+	ItemID    int64
+	Action    string
+	OldData   *Lot
+	NewData   *Lot
+	ChangedBy string
+	ChangedAt time.Time
+}
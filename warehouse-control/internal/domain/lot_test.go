@@ -0,0 +1,48 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateFEFO(t *testing.T) {
+	lots := []*domain.Lot{
+		{ID: 1, LotNumber: "A", Quantity: 5},
+		{ID: 2, LotNumber: "B", Quantity: 0},
+		{ID: 3, LotNumber: "C", Quantity: 10},
+	}
+
+	allocations, remaining := domain.AllocateFEFO(lots, 8)
+
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, []*domain.LotAllocation{
+		{LotID: 1, LotNumber: "A", Quantity: 5},
+		{LotID: 3, LotNumber: "C", Quantity: 3},
+	}, allocations)
+}
+
+func TestAllocateFEFO_NotEnoughStock(t *testing.T) {
+	lots := []*domain.Lot{
+		{ID: 1, LotNumber: "A", Quantity: 2},
+	}
+
+	allocations, remaining := domain.AllocateFEFO(lots, 5)
+
+	assert.Equal(t, 3, remaining)
+	assert.Len(t, allocations, 1)
+	assert.Equal(t, 2, allocations[0].Quantity)
+}
+
+func TestLot_IsExpired(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, (&domain.Lot{ExpiresAt: &yesterday}).IsExpired(now))
+	assert.False(t, (&domain.Lot{ExpiresAt: &today}).IsExpired(now))
+	assert.False(t, (&domain.Lot{}).IsExpired(now))
+}
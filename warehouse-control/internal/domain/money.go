@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+// MoneyPlaces is the number of fractional digits prices are kept to;
+// DisplayPlaces the number amounts converted for display are rounded to.
+const (
+	MoneyPlaces   = 4
+	DisplayPlaces = 2
+	// RatePlaces is the number of fractional digits exchange rates are kept to.
+	RatePlaces = 10
+)
+
+// Money is an exact amount in an ISO 4217 currency.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 code: three
+// upper-case latin letters.
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ExchangeRate is the value of one unit of Currency in the base currency.
+type ExchangeRate struct {
+	Currency  string
+	Rate      decimal.Decimal
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// ExchangeRates converts money between currencies through the base
+// currency, whose rate is always one.
+type ExchangeRates struct {
+	Base  string
+	Rates map[string]decimal.Decimal
+}
+
+func (r *ExchangeRates) rate(currency string) (decimal.Decimal, bool) {
+	if currency == r.Base {
+		return decimal.FromInt(1), true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok
+}
+
+// Convert returns m in the given currency, rounded to DisplayPlaces. It
+// reports false when either currency has no rate.
+func (r *ExchangeRates) Convert(m Money, currency string) (Money, bool) {
+	from, ok := r.rate(m.Currency)
+	if !ok {
+		return Money{}, false
+	}
+	to, ok := r.rate(currency)
+	if !ok {
+		return Money{}, false
+	}
+	if m.Currency == currency {
+		return Money{Amount: m.Amount.Round(DisplayPlaces), Currency: currency}, true
+	}
+	return Money{Amount: m.Amount.Mul(from).Div(to, DisplayPlaces), Currency: currency}, true
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain/decimal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCurrencyCode(t *testing.T) {
+	assert.True(t, IsCurrencyCode("EUR"))
+	assert.False(t, IsCurrencyCode("eur"))
+	assert.False(t, IsCurrencyCode("EU"))
+	assert.False(t, IsCurrencyCode("EURO"))
+	assert.False(t, IsCurrencyCode("E1R"))
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	rates := &ExchangeRates{
+		Base: "RUB",
+		Rates: map[string]decimal.Decimal{
+			"USD": decimal.MustParse("90.5"),
+			"EUR": decimal.MustParse("98.25"),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		amount   string
+		from, to string
+		want     string
+	}{
+		{"to base", "10.1000", "USD", "RUB", "914.05"},
+		{"from base", "181", "RUB", "USD", "2.00"},
+		{"cross rate", "100", "USD", "EUR", "92.11"},
+		{"same currency", "0.105", "EUR", "EUR", "0.11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rates.Convert(Money{Amount: decimal.MustParse(tt.amount), Currency: tt.from}, tt.to)
+			assert.True(t, ok)
+			assert.Equal(t, tt.to, got.Currency)
+			assert.Equal(t, tt.want, got.Amount.String())
+		})
+	}
+
+	_, ok := rates.Convert(Money{Amount: decimal.FromInt(1), Currency: "GBP"}, "RUB")
+	assert.False(t, ok)
+	_, ok = rates.Convert(Money{Amount: decimal.FromInt(1), Currency: "RUB"}, "GBP")
+	assert.False(t, ok)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+type MovementType string
+
+const (
+	MovementReceipt    MovementType = "receipt"
+	MovementIssue      MovementType = "issue"
+	MovementAdjustment MovementType = "adjustment"
+	MovementTransfer   MovementType = "transfer"
+)
+
+func (t MovementType) IsValid() bool {
+	switch t {
+	case MovementReceipt, MovementIssue, MovementAdjustment, MovementTransfer:
+		return true
+	}
+	return false
+}
+
+// StockMovement is a single ledger entry. Quantity is always positive for
+// receipts, issues and transfers; adjustments carry a signed quantity.
+// A nil bin on either side means stock that is not assigned to any bin.
+type StockMovement struct {
+	ID       int64
+	ItemID   int64
+	Type     MovementType
+	Quantity int
+	// Requested is the quantity as entered, possibly in another unit of the
+	// item; it is normalised into Quantity, which is in the base unit.
+	Requested      *UnitQuantity
+	ReasonCode     string
+	Reference      string
+	FromLocationID *int64
+	ToLocationID   *int64
+	// ReservationID marks an issue that fulfils (part of) a reservation.
+	ReservationID *int64
+	FromLocation  string
+	ToLocation    string
+	CreatedBy     string
+	CreatedAt     time.Time
+
+	// LotID names the lot an outgoing movement consumes; without it stock is
+	// taken from lots first-expiring-first-out. Incoming movements create or
+	// top up the lot identified by LotNumber.
+	LotID          *int64
+	LotNumber      string
+	ManufacturedAt *time.Time
+	ExpiresAt      *time.Time
+	Lots           []*LotAllocation
+
+	// Serials lists the serial numbers moved; required for serialized items.
+	Serials []string
+
+	// UnitCost is the cost of one unit moved. Receipts and positive
+	// adjustments may set it; otherwise it is the item's current cost. The
+	// cost of outgoing stock follows the item's costing method. TotalCost is
+	// the value of the whole movement.
+	UnitCost  *decimal.Decimal
+	TotalCost decimal.Decimal
+
+	// StockStatus is the status the movement books stock into or out of,
+	// e.g. quarantine for returned goods or damaged for a write-off. Empty
+	// means available.
+	StockStatus StockStatus
+
+	// PurchaseOrderLineID links a receipt to the order line it receives.
+	PurchaseOrderLineID *int64
+}
+
+// Delta returns the change the movement applies to the item's on-hand quantity.
+func (m *StockMovement) Delta() int {
+	switch m.Type {
+	case MovementReceipt:
+		return m.Quantity
+	case MovementIssue:
+		return -m.Quantity
+	case MovementAdjustment:
+		return m.Quantity
+	}
+	return 0
+}
+
+type MovementFilter struct {
+	ItemID int64
+	Type   *MovementType
+	Limit  int
+	Offset int
+}
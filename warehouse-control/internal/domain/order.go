@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderNew       OrderStatus = "new"
+	OrderAllocated OrderStatus = "allocated"
+	OrderPicked    OrderStatus = "picked"
+	OrderShipped   OrderStatus = "shipped"
+	OrderCancelled OrderStatus = "cancelled"
+)
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderNew, OrderAllocated, OrderPicked, OrderShipped, OrderCancelled:
+		return true
+	}
+	return false
+}
+
+// Order is an outbound (sales) order. Allocating it reserves stock for every
+// line; the stock only leaves the warehouse when the order is shipped.
+type Order struct {
+	ID        int64
+	Number    string
+	Customer  string
+	Status    OrderStatus
+	Notes     string
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ShippedAt *time.Time
+	Lines     []*OrderLine
+}
+
+type OrderLine struct {
+	ID             int64
+	OrderID        int64
+	ItemID         int64
+	SKU            string
+	ItemName       string
+	Quantity       int
+	PickedQuantity int
+	ReservationID  *int64
+	Picks          []*OrderPick
+	// Requested is the ordered quantity as entered; it is normalised into
+	// Quantity.
+	Requested *UnitQuantity
+}
+
+// Outstanding returns the quantity still to be picked.
+func (l *OrderLine) Outstanding() int {
+	return l.Quantity - l.PickedQuantity
+}
+
+// OrderPick confirms that Quantity units of a line were taken from a bin, or
+// from unassigned stock when LocationID is nil. Serialized items name the
+// picked serials.
+type OrderPick struct {
+	ID           int64
+	LineID       int64
+	LocationID   *int64
+	LocationPath string
+	Quantity     int
+	Serials      []string
+	PickedBy     string
+	PickedAt     time.Time
+}
+
+// OrderHistoryRecord is one status transition of an order. FromStatus is nil
+// for the record written when the order is created.
+type OrderHistoryRecord struct {
+	ID         int64
+	OrderID    int64
+	FromStatus *OrderStatus
+	ToStatus   OrderStatus
+	Note       string
+	ChangedBy  string
+	ChangedAt  time.Time
+}
+
+type OrderFilter struct {
+	Status   *OrderStatus
+	Customer string
+	Limit    int
+	Offset   int
+}
+
+// PickTask tells the picker to take Quantity units of a line's item from one
+// bin.
+type PickTask struct {
+	LineID   int64
+	ItemID   int64
+	SKU      string
+	ItemName string
+	Quantity int
+}
+
+// PickStop groups the pick tasks for a single bin. LocationID is nil for
+// stock that is not held in any bin.
+type PickStop struct {
+	LocationID   *int64
+	LocationPath string
+	Tasks        []*PickTask
+}
+
+// BuildPickList spreads the outstanding quantity of every line over the bins
+// holding its item, in the order the bins are given, and puts whatever the
+// bins cannot cover on an unassigned stop. Stops are sorted by bin path with
+// the unassigned stop last.
+func BuildPickList(lines []*OrderLine, stock []*ItemStock) []*PickStop {
+	taken := make(map[*ItemStock]int, len(stock))
+	byItem := make(map[int64][]*ItemStock)
+	for _, s := range stock {
+		byItem[s.ItemID] = append(byItem[s.ItemID], s)
+	}
+
+	stops := make(map[int64]*PickStop)
+	var unassigned *PickStop
+	for _, line := range lines {
+		quantity := line.Outstanding()
+		for _, s := range byItem[line.ItemID] {
+			if quantity <= 0 {
+				break
+			}
+			take := min(s.Quantity-taken[s], quantity)
+			if take <= 0 {
+				continue
+			}
+			taken[s] += take
+			quantity -= take
+
+			stop, ok := stops[s.LocationID]
+			if !ok {
+				locationID := s.LocationID
+				stop = &PickStop{LocationID: &locationID, LocationPath: s.LocationPath}
+				stops[s.LocationID] = stop
+			}
+			stop.Tasks = append(stop.Tasks, newPickTask(line, take))
+		}
+		if quantity > 0 {
+			if unassigned == nil {
+				unassigned = &PickStop{}
+			}
+			unassigned.Tasks = append(unassigned.Tasks, newPickTask(line, quantity))
+		}
+	}
+
+	list := make([]*PickStop, 0, len(stops)+1)
+	for _, stop := range stops {
+		list = append(list, stop)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LocationPath < list[j].LocationPath
+	})
+	if unassigned != nil {
+		list = append(list, unassigned)
+	}
+	return list
+}
+
+func newPickTask(line *OrderLine, quantity int) *PickTask {
+	return &PickTask{
+		LineID:   line.ID,
+		ItemID:   line.ItemID,
+		SKU:      line.SKU,
+		ItemName: line.ItemName,
+		Quantity: quantity,
+	}
+}
@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPickList(t *testing.T) {
+	lines := []*domain.OrderLine{
+		{ID: 1, ItemID: 10, SKU: "A", Quantity: 7},
+		{ID: 2, ItemID: 20, SKU: "B", Quantity: 4, PickedQuantity: 1},
+	}
+	stock := []*domain.ItemStock{
+		{ItemID: 10, LocationID: 100, LocationPath: "WH1/Z2/A1/B1", Quantity: 5},
+		{ItemID: 10, LocationID: 101, LocationPath: "WH1/Z1/A1/B1", Quantity: 1},
+		{ItemID: 20, LocationID: 100, LocationPath: "WH1/Z2/A1/B1", Quantity: 10},
+	}
+
+	list := domain.BuildPickList(lines, stock)
+
+	require.Len(t, list, 3)
+	assert.Equal(t, "WH1/Z1/A1/B1", list[0].LocationPath)
+	assert.Equal(t, []*domain.PickTask{{LineID: 1, ItemID: 10, SKU: "A", Quantity: 1}}, list[0].Tasks)
+	assert.Equal(t, "WH1/Z2/A1/B1", list[1].LocationPath)
+	assert.Equal(t, []*domain.PickTask{
+		{LineID: 1, ItemID: 10, SKU: "A", Quantity: 5},
+		{LineID: 2, ItemID: 20, SKU: "B", Quantity: 3},
+	}, list[1].Tasks)
+	assert.Nil(t, list[2].LocationID)
+	assert.Equal(t, []*domain.PickTask{{LineID: 1, ItemID: 10, SKU: "A", Quantity: 1}}, list[2].Tasks)
+}
+
+func TestBuildPickList_NothingOutstanding(t *testing.T) {
+	lines := []*domain.OrderLine{{ID: 1, ItemID: 10, Quantity: 2, PickedQuantity: 2}}
+
+	assert.Empty(t, domain.BuildPickList(lines, nil))
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxVariants caps the size of the variant matrix of a product.
+const MaxVariants = 1000
+
+// VariantAttribute is an attribute the variants of a product differ by, such
+// as size or colour, with the values it takes.
+type VariantAttribute struct {
+	Name   string
+	Values []string
+}
+
+// Product is the parent of variant items that differ only by their
+// attribute values. Variants inherit the product's name, category and
+// price: a variant whose field still equals the product's follows it when
+// the product changes, one that differs has been overridden.
+type Product struct {
+	ID         int64
+	Name       string
+	SKU        string
+	Category   string
+	Price      Money
+	Attributes []VariantAttribute
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// Variants are the child items of the product.
+	Variants []*Item
+}
+
+type ProductFilter struct {
+	Search string
+	Limit  int
+	Offset int
+}
+
+// MatrixSize returns the number of variants the attributes combine into.
+func (p *Product) MatrixSize() int {
+	if len(p.Attributes) == 0 {
+		return 0
+	}
+	size := 1
+	for _, a := range p.Attributes {
+		size *= len(a.Values)
+		if size > MaxVariants {
+			return size
+		}
+	}
+	return size
+}
+
+// Matrix returns every combination of attribute values, the last attribute
+// varying fastest.
+func (p *Product) Matrix() []map[string]string {
+	if len(p.Attributes) == 0 {
+		return nil
+	}
+	matrix := []map[string]string{{}}
+	for _, a := range p.Attributes {
+		next := make([]map[string]string, 0, len(matrix)*len(a.Values))
+		for _, combination := range matrix {
+			for _, v := range a.Values {
+				variant := make(map[string]string, len(combination)+1)
+				for k, cv := range combination {
+					variant[k] = cv
+				}
+				variant[a.Name] = v
+				next = append(next, variant)
+			}
+		}
+		matrix = next
+	}
+	return matrix
+}
+
+// VariantSKU derives the SKU of a variant from the product SKU and its
+// attribute values, e.g. TSHIRT-M-RED.
+func (p *Product) VariantSKU(variant map[string]string) string {
+	parts := []string{p.SKU}
+	for _, a := range p.Attributes {
+		parts = append(parts, strings.ToUpper(strings.Join(strings.Fields(variant[a.Name]), "")))
+	}
+	return strings.Join(parts, "-")
+}
+
+// GroupVariants splits items into standalone items and the variants of each
+// product, in the order the items and products first appear. The returned
+// products only carry their ID and Variants.
+func GroupVariants(items []*Item) ([]*Item, []*Product) {
+	standalone := make([]*Item, 0, len(items))
+	var products []*Product
+	byID := make(map[int64]*Product)
+	for _, item := range items {
+		if item.ProductID == nil {
+			standalone = append(standalone, item)
+			continue
+		}
+		p, ok := byID[*item.ProductID]
+		if !ok {
+			p = &Product{ID: *item.ProductID}
+			byID[p.ID] = p
+			products = append(products, p)
+		}
+		p.Variants = append(p.Variants, item)
+	}
+	return standalone, products
+}
@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductMatrix(t *testing.T) {
+	p := &domain.Product{
+		SKU: "TSHIRT",
+		Attributes: []domain.VariantAttribute{
+			{Name: "size", Values: []string{"S", "M"}},
+			{Name: "colour", Values: []string{"red", "navy blue", "white"}},
+		},
+	}
+	require.Equal(t, 6, p.MatrixSize())
+
+	matrix := p.Matrix()
+	require.Len(t, matrix, 6)
+	assert.Equal(t, map[string]string{"size": "S", "colour": "red"}, matrix[0])
+	assert.Equal(t, map[string]string{"size": "M", "colour": "white"}, matrix[5])
+	assert.Equal(t, "TSHIRT-S-NAVYBLUE", p.VariantSKU(matrix[1]))
+}
+
+func TestGroupVariants(t *testing.T) {
+	p1, p2 := int64(1), int64(2)
+	items := []*domain.Item{
+		{ID: 10, ProductID: &p2},
+		{ID: 11},
+		{ID: 12, ProductID: &p1},
+		{ID: 13, ProductID: &p2},
+	}
+	standalone, products := domain.GroupVariants(items)
+	require.Len(t, standalone, 1)
+	assert.Equal(t, int64(11), standalone[0].ID)
+	require.Len(t, products, 2)
+	assert.Equal(t, int64(2), products[0].ID)
+	assert.Len(t, products[0].Variants, 2)
+	assert.Equal(t, int64(1), products[1].ID)
+}
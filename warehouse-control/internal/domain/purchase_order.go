@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderOpen              PurchaseOrderStatus = "open"
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+func (s PurchaseOrderStatus) IsValid() bool {
+	switch s {
+	case PurchaseOrderOpen, PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether stock can still be received against the order.
+func (s PurchaseOrderStatus) IsOpen() bool {
+	return s == PurchaseOrderOpen || s == PurchaseOrderPartiallyReceived
+}
+
+type PurchaseOrder struct {
+	ID           int64
+	SupplierID   int64
+	SupplierName string
+	Number       string
+	Status       PurchaseOrderStatus
+	ExpectedAt   *time.Time
+	Notes        string
+	CreatedBy    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ClosedAt     *time.Time
+	Lines        []*PurchaseOrderLine
+}
+
+// PurchaseOrderLine orders Quantity units of the item identified by SKU.
+type PurchaseOrderLine struct {
+	ID               int64
+	OrderID          int64
+	ItemID           int64
+	SKU              string
+	ItemName         string
+	Quantity         int
+	ReceivedQuantity int
+	// UnitPrice is the price of one base unit of the item.
+	UnitPrice decimal.Decimal
+	// Requested is the ordered quantity as entered; it is normalised into
+	// Quantity.
+	Requested *UnitQuantity
+}
+
+// Remaining returns the quantity still to be received; it is negative after
+// an over-receipt.
+func (l *PurchaseOrderLine) Remaining() int {
+	return l.Quantity - l.ReceivedQuantity
+}
+
+// PurchaseOrderReceipt books Quantity units against an order line, into the
+// given bin or the unassigned pool.
+type PurchaseOrderReceipt struct {
+	LineID     int64
+	Quantity   int
+	LocationID *int64
+	// Requested is the received quantity as entered; it is normalised into
+	// Quantity.
+	Requested *UnitQuantity
+
+	// LotNumber, with its dates, names the lot the stock is received into.
+	LotNumber      string
+	ManufacturedAt *time.Time
+	ExpiresAt      *time.Time
+	// StockStatus is the status the stock is received in, e.g. blocked for
+	// goods awaiting inspection. Empty means available.
+	StockStatus StockStatus
+}
+
+type PurchaseOrderFilter struct {
+	SupplierID *int64
+	Status     *PurchaseOrderStatus
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+	ReservationFulfilled ReservationStatus = "fulfilled"
+)
+
+func (s ReservationStatus) IsValid() bool {
+	switch s {
+	case ReservationActive, ReservationReleased, ReservationExpired, ReservationFulfilled:
+		return true
+	}
+	return false
+}
+
+// Reservation holds stock for a pending order. Only active, unexpired
+// reservations reduce the available quantity of an item.
+type Reservation struct {
+	ID                int64
+	ItemID            int64
+	Quantity          int
+	FulfilledQuantity int
+	Reference         string
+	Status            ReservationStatus
+	ExpiresAt         time.Time
+	CreatedBy         string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Remaining returns the part of the reservation that is still held.
+func (r *Reservation) Remaining() int {
+	return r.Quantity - r.FulfilledQuantity
+}
+
+type ReservationFilter struct {
+	ItemID int64
+	Status *ReservationStatus
+	Limit  int
+	Offset int
+}
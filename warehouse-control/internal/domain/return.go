@@ -0,0 +1,111 @@
+package domain
+
+import "time"
+
+type ReturnStatus string
+
+const (
+	// ReturnOpen returns are authorised and wait for the goods to arrive.
+	ReturnOpen ReturnStatus = "open"
+	// ReturnReceived returns hold their goods in quarantine until every line
+	// has been dispositioned.
+	ReturnReceived  ReturnStatus = "received"
+	ReturnClosed    ReturnStatus = "closed"
+	ReturnCancelled ReturnStatus = "cancelled"
+)
+
+func (s ReturnStatus) IsValid() bool {
+	switch s {
+	case ReturnOpen, ReturnReceived, ReturnClosed, ReturnCancelled:
+		return true
+	}
+	return false
+}
+
+type Disposition string
+
+const (
+	// DispositionRestock releases the goods from quarantine into available
+	// stock.
+	DispositionRestock Disposition = "restock"
+	// DispositionScrap and DispositionReturnToVendor take the goods out of
+	// stock.
+	DispositionScrap          Disposition = "scrap"
+	DispositionReturnToVendor Disposition = "return_to_vendor"
+)
+
+func (d Disposition) IsValid() bool {
+	switch d {
+	case DispositionRestock, DispositionScrap, DispositionReturnToVendor:
+		return true
+	}
+	return false
+}
+
+// Return is a return merchandise authorisation (RMA), raised against a
+// shipped order or for loose SKUs.
+type Return struct {
+	ID         int64
+	Number     string
+	OrderID    *int64
+	Customer   string
+	Reason     string
+	Status     ReturnStatus
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ReceivedAt *time.Time
+	ClosedAt   *time.Time
+	Lines      []*ReturnLine
+}
+
+type ReturnLine struct {
+	ID               int64
+	ReturnID         int64
+	ItemID           int64
+	SKU              string
+	ItemName         string
+	Quantity         int
+	ReceivedQuantity int
+	LocationID       *int64
+	LocationPath     string
+	Serials          []string
+	Dispositions     []*ReturnDisposition
+}
+
+// Quarantined returns the received quantity that still awaits a
+// disposition.
+func (l *ReturnLine) Quarantined() int {
+	q := l.ReceivedQuantity
+	for _, d := range l.Dispositions {
+		q -= d.Quantity
+	}
+	return q
+}
+
+// ReturnReceipt books the goods that arrived for a line into quarantine, in
+// the given bin or as unassigned stock.
+type ReturnReceipt struct {
+	LineID     int64
+	Quantity   int
+	LocationID *int64
+	Serials    []string
+}
+
+type ReturnDisposition struct {
+	ID          int64
+	LineID      int64
+	Disposition Disposition
+	Quantity    int
+	Serials     []string
+	SupplierID  *int64
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+type ReturnFilter struct {
+	Status  *ReturnStatus
+	OrderID *int64
+	Limit   int
+	Offset  int
+}
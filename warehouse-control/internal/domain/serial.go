@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type SerialStatus string
+
+const (
+	SerialInStock SerialStatus = "in_stock"
+	SerialIssued  SerialStatus = "issued"
+)
+
+// Serial is a single tracked unit of a serialized item. LocationID is nil
+// while the unit is in stock but not put away into a bin, and after it has
+// been issued.
+type Serial struct {
+	ID           int64
+	ItemID       int64
+	SerialNumber string
+	Status       SerialStatus
+	LocationID   *int64
+	LocationPath string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// SerialTrail is a serial together with its item and every movement that
+// touched it, oldest first.
+type SerialTrail struct {
+	Serial    *Serial
+	Item      *Item
+	Movements []*StockMovement
+}
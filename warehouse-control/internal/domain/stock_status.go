@@ -0,0 +1,47 @@
+package domain
+
+// StockStatus segregates the on-hand quantity of an item. Only available
+// stock can be reserved, allocated or issued by ordinary movements; the other
+// statuses hold stock back until it is released or written off.
+type StockStatus string
+
+const (
+	StockAvailable StockStatus = "available"
+	// StockQuarantine holds goods received back from customers until their
+	// return is dispositioned.
+	StockQuarantine StockStatus = "quarantine"
+	StockDamaged    StockStatus = "damaged"
+	// StockBlocked holds goods on a QA hold.
+	StockBlocked StockStatus = "blocked"
+)
+
+// StockStatuses lists the statuses in the order they are reported.
+var StockStatuses = []StockStatus{StockAvailable, StockQuarantine, StockDamaged, StockBlocked}
+
+func (s StockStatus) IsValid() bool {
+	switch s {
+	case StockAvailable, StockQuarantine, StockDamaged, StockBlocked:
+		return true
+	}
+	return false
+}
+
+// StockStatusChange moves on-hand stock of an item from one status to
+// another without it leaving the warehouse.
+type StockStatusChange struct {
+	ItemID   int64
+	From     StockStatus
+	To       StockStatus
+	Quantity int
+}
+
+// StockTotals sums on-hand stock over a set of items. Reserved and Available
+// together make up the stock in available status.
+type StockTotals struct {
+	OnHand     int
+	Reserved   int
+	Available  int
+	Quarantine int
+	Damaged    int
+	Blocked    int
+}
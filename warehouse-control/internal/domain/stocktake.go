@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+type StocktakeStatus string
+
+const (
+	// StocktakeCounting accepts counts; blind sessions hide system quantities.
+	StocktakeCounting StocktakeStatus = "counting"
+	// StocktakeReview no longer accepts counts; variances are visible.
+	StocktakeReview    StocktakeStatus = "review"
+	StocktakePosted    StocktakeStatus = "posted"
+	StocktakeCancelled StocktakeStatus = "cancelled"
+)
+
+func (s StocktakeStatus) IsValid() bool {
+	switch s {
+	case StocktakeCounting, StocktakeReview, StocktakePosted, StocktakeCancelled:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether the session can still be posted or cancelled.
+func (s StocktakeStatus) IsOpen() bool {
+	return s == StocktakeCounting || s == StocktakeReview
+}
+
+// Stocktake is a physical count session scoped to a set of locations
+// (every bin below them) and/or item categories.
+type Stocktake struct {
+	ID          int64
+	Status      StocktakeStatus
+	Blind       bool
+	LocationIDs []int64
+	Categories  []string
+	CreatedBy   string
+	CreatedAt   time.Time
+	PostedBy    string
+	PostedAt    *time.Time
+	Lines       []*StocktakeLine
+}
+
+// VariancesHidden reports whether system quantities must not be shown yet.
+func (s *Stocktake) VariancesHidden() bool {
+	return s.Blind && s.Status == StocktakeCounting
+}
+
+// StocktakeLine is one item in one bin, or the item as a whole when
+// LocationID is nil. SystemQuantity is read live, not snapshotted, so stock
+// moved during the count is taken into account when posting.
+type StocktakeLine struct {
+	ID              int64
+	StocktakeID     int64
+	ItemID          int64
+	ItemName        string
+	ItemSKU         string
+	LocationID      *int64
+	LocationPath    string
+	SystemQuantity  int
+	CountedQuantity *int
+	CountedBy       string
+	CountedAt       *time.Time
+}
+
+// Variance returns counted minus system quantity; ok is false while the
+// line has not been counted.
+func (l *StocktakeLine) Variance() (variance int, ok bool) {
+	if l.CountedQuantity == nil {
+		return 0, false
+	}
+	return *l.CountedQuantity - l.SystemQuantity, true
+}
+
+type StocktakeCount struct {
+	ItemID     int64
+	LocationID *int64
+	Quantity   int
+}
+
+type StocktakeFilter struct {
+	Status *StocktakeStatus
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+type Supplier struct {
+	ID        int64
+	Code      string
+	Name      string
+	Email     string
+	Phone     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SupplierFilter struct {
+	Search string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"warehouse-control/internal/domain/decimal"
+)
+
+// FactorPlaces is the number of fractional digits conversion factors are
+// kept to.
+const FactorPlaces = 6
+
+// DefaultUnit is the base unit of items that do not name one.
+const DefaultUnit = "pcs"
+
+// Unit is a unit of measure. Quantities of a discrete unit, such as pieces
+// or cases, are whole numbers.
+type Unit struct {
+	Code      string
+	Name      string
+	Discrete  bool
+	CreatedAt time.Time
+}
+
+// ItemUnit converts a unit into the base unit an item's stock is kept in:
+// one Unit is Factor base units. The base unit itself has a factor of one.
+type ItemUnit struct {
+	ItemID   int64
+	Unit     string
+	Factor   decimal.Decimal
+	Discrete bool
+	BaseUnit string
+}
+
+// ItemUnits is the base unit of an item with the other units it is handled
+// in.
+type ItemUnits struct {
+	ItemID      int64
+	BaseUnit    string
+	Conversions []*ItemUnit
+}
+
+// UnitQuantity is a quantity as entered, in any unit configured for the
+// item. An empty Unit stands for the item's base unit.
+type UnitQuantity struct {
+	Quantity decimal.Decimal
+	Unit     string
+}
+
+// ToBase converts a quantity of the unit into base units. A discrete unit
+// only comes in whole numbers, and the result has to be a whole number of
+// base units since stock is kept in them.
+func (u *ItemUnit) ToBase(q decimal.Decimal) (int, error) {
+	if _, whole := q.Int64(); u.Discrete && !whole {
+		return 0, fmt.Errorf("%s is counted in whole units, got %s", u.Unit, q)
+	}
+	base := q.Mul(u.Factor)
+	n, whole := base.Int64()
+	if !whole {
+		return 0, fmt.Errorf("%s %s is %s %s, stock is kept in whole %s", q, u.Unit, base, u.BaseUnit, u.BaseUnit)
+	}
+	return int(n), nil
+}
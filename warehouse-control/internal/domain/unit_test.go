@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain/decimal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemUnitToBase(t *testing.T) {
+	caseUnit := &ItemUnit{Unit: "case", Factor: decimal.FromInt(24), Discrete: true, BaseUnit: "pcs"}
+	n, err := caseUnit.ToBase(decimal.FromInt(3))
+	require.NoError(t, err)
+	assert.Equal(t, 72, n)
+
+	_, err = caseUnit.ToBase(decimal.MustParse("1.5"))
+	assert.ErrorContains(t, err, "case is counted in whole units")
+
+	pack := &ItemUnit{Unit: "pack", Factor: decimal.MustParse("2.5"), Discrete: true, BaseUnit: "pcs"}
+	n, err = pack.ToBase(decimal.FromInt(4))
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+	_, err = pack.ToBase(decimal.FromInt(3))
+	assert.ErrorContains(t, err, "3 pack is 7.5 pcs, stock is kept in whole pcs")
+
+	kg := &ItemUnit{Unit: "kg", Factor: decimal.FromInt(1000), BaseUnit: "g"}
+	n, err = kg.ToBase(decimal.MustParse("1.25"))
+	require.NoError(t, err)
+	assert.Equal(t, 1250, n)
+	_, err = kg.ToBase(decimal.MustParse("0.0005"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+// Package export writes records to CSV, JSON Lines and XLSX files one row at
+// a time, so exports of any size are written without holding them in memory.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer writes the rows of an export. Values are written in the columns of
+// the header it was created with; nil leaves a cell empty.
+type Writer interface {
+	WriteRow(values []any) error
+	// Close writes out what the writer still holds. The file is incomplete
+	// until it returns without error.
+	Close() error
+	// Abort drops an export that cannot be finished, releasing what the
+	// writer holds without writing it out.
+	Abort()
+}
+
+// NewWriter starts an export file with the given header. Nothing reaches w
+// before the writer's buffer fills up, and an XLSX file is written out only
+// on Close.
+func NewWriter(w io.Writer, format domain.ExportFormat, header []string) (Writer, error) {
+	switch format {
+	case domain.ExportCSV:
+		return newCSVWriter(w, header)
+	case domain.ExportJSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w), header: header}, nil
+	case domain.ExportXLSX:
+		return newXLSXWriter(w, header)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// ResponseWriter is a response an export is served over, such as gin's,
+// that tells whether anything has been written to it.
+type ResponseWriter interface {
+	http.ResponseWriter
+	Written() bool
+}
+
+// AbortResponse gives up on an export served over w. Until the file has
+// started to go out it clears the file headers and returns, leaving the
+// error to be written as usual; after that it drops the connection by
+// panicking with http.ErrAbortHandler, so the client sees the download fail
+// instead of a truncated file.
+func AbortResponse(w ResponseWriter) {
+	if w.Written() {
+		panic(http.ErrAbortHandler)
+	}
+	w.Header().Del("Content-Type")
+	w.Header().Del("Content-Disposition")
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, header []string) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		s, err := text(v)
+		if err != nil {
+			return err
+		}
+		record[i] = s
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Abort() {}
+
+// jsonlWriter writes a row as an object keyed by the header, keeping the
+// column order.
+type jsonlWriter struct {
+	w      *bufio.Writer
+	header []string
+}
+
+func (jw *jsonlWriter) WriteRow(values []any) error {
+	line := []byte{'{'}
+	for i, v := range values {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		line = strconv.AppendQuote(line, jw.header[i])
+		line = append(line, ':')
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", jw.header[i], err)
+		}
+		line = append(line, value...)
+	}
+	line = append(line, '}', '\n')
+	_, err := jw.w.Write(line)
+	return err
+}
+
+func (jw *jsonlWriter) Close() error {
+	return jw.w.Flush()
+}
+
+func (jw *jsonlWriter) Abort() {}
+
+// xlsxWriter streams rows into a single sheet. The stream writer moves rows
+// to a temporary file as they grow, and the workbook is written out on Close.
+type xlsxWriter struct {
+	w    io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func newXLSXWriter(w io.Writer, header []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	sw, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	xw := &xlsxWriter{w: w, file: file, sw: sw}
+	values := make([]any, len(header))
+	for i, h := range header {
+		values[i] = h
+	}
+	if err := xw.WriteRow(values); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []any) error {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil, string, bool, int, int64:
+			cells[i] = v
+		case decimal.Decimal:
+			// Amounts are written as numbers for spreadsheets to compute with.
+			f, err := strconv.ParseFloat(v.String(), 64)
+			if err != nil {
+				return err
+			}
+			cells[i] = f
+		default:
+			s, err := text(v)
+			if err != nil {
+				return err
+			}
+			cells[i] = s
+		}
+	}
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	return xw.sw.SetRow(cell, cells)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer func() { _ = xw.file.Close() }()
+	if err := xw.sw.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.w)
+}
+
+// Abort removes the temporary files of the stream writer.
+func (xw *xlsxWriter) Abort() {
+	_ = xw.file.Close()
+}
+
+// text formats a value for a text cell. Maps and slices are written as JSON.
+func text(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case int, int64, bool:
+		return fmt.Sprint(v), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
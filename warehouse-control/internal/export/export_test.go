@@ -0,0 +1,56 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+	"warehouse-control/internal/export"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	header = []string{"id", "sku", "price", "serialized", "attributes", "created_at", "product_id"}
+	row    = []any{int64(7), "A-1", decimal.MustParse("12.50"), true, map[string]any{"voltage": 220},
+		time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), nil}
+)
+
+func write(t *testing.T, format domain.ExportFormat) []byte {
+	var buf bytes.Buffer
+	w, err := export.NewWriter(&buf, format, header)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow(row))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestCSV(t *testing.T) {
+	assert.Equal(t,
+		"id,sku,price,serialized,attributes,created_at,product_id\n"+
+			`7,A-1,12.50,true,"{""voltage"":220}",2026-03-01T10:00:00Z,`+"\n",
+		string(write(t, domain.ExportCSV)))
+}
+
+func TestJSONL(t *testing.T) {
+	assert.Equal(t,
+		`{"id":7,"sku":"A-1","price":"12.50","serialized":true,"attributes":{"voltage":220},"created_at":"2026-03-01T10:00:00Z","product_id":null}`+"\n",
+		string(write(t, domain.ExportJSONL)))
+}
+
+func TestXLSX(t *testing.T) {
+	f, err := excelize.OpenReader(bytes.NewReader(write(t, domain.ExportXLSX)))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		header,
+		{"7", "A-1", "12.5", "TRUE", `{"voltage":220}`, "2026-03-01T10:00:00Z"},
+	}, rows, "amounts are written as numbers")
+}
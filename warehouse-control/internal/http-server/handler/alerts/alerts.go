@@ -0,0 +1,110 @@
+package alerts_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/alerts/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type AlertsHandler struct {
+	alertsUsecase alertsUsecase
+	logger        *zlog.Zerolog
+}
+
+func NewHandler(alertsUsecase alertsUsecase, logger *zlog.Zerolog) *AlertsHandler {
+	return &AlertsHandler{
+		alertsUsecase: alertsUsecase,
+		logger:        logger,
+	}
+}
+
+func (h *AlertsHandler) GetAlerts(c *gin.Context) {
+	filter := domain.AlertFilter{
+		Limit: 100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if st := c.Query("status"); st != "" {
+		status := domain.AlertStatus(st)
+		filter.Status = &status
+	}
+	if itemID := c.Query("item_id"); itemID != "" {
+		id, err := strconv.ParseInt(itemID, 10, 64)
+		if err != nil || id <= 0 {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		filter.ItemID = &id
+	}
+	alerts, total, err := h.alertsUsecase.GetAlerts(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetAlerts failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.AlertsResponse{
+		Alerts: make([]*dto.AlertResponse, len(alerts)),
+		Total:  total,
+	}
+	for i, a := range alerts {
+		resp.Alerts[i] = dto.ToAlertResponse(a)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AlertsHandler) AcknowledgeAlert(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.alertsUsecase.AcknowledgeAlert(c.Request.Context(), id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Alert acknowledged")
+}
+
+func (h *AlertsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrAlertNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrAlertNotOpen):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
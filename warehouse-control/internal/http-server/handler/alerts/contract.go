@@ -0,0 +1,12 @@
+package alerts_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type alertsUsecase interface {
+	GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, int, error)
+	AcknowledgeAlert(ctx context.Context, id int64, username string) error
+}
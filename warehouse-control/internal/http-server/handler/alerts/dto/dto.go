@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type AlertResponse struct {
+	ID              int64      `json:"id"`
+	ItemID          int64      `json:"item_id"`
+	ItemName        string     `json:"item_name"`
+	ItemSKU         string     `json:"item_sku"`
+	Quantity        int        `json:"quantity"`
+	ReorderLevel    int        `json:"reorder_level"`
+	ReorderQuantity int        `json:"reorder_quantity"`
+	Status          string     `json:"status"`
+	AcknowledgedBy  string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type AlertsResponse struct {
+	Alerts []*AlertResponse `json:"alerts"`
+	Total  int              `json:"total"`
+}
+
+func ToAlertResponse(a *domain.Alert) *AlertResponse {
+	return &AlertResponse{
+		ID:              a.ID,
+		ItemID:          a.ItemID,
+		ItemName:        a.ItemName,
+		ItemSKU:         a.ItemSKU,
+		Quantity:        a.Quantity,
+		ReorderLevel:    a.ReorderLevel,
+		ReorderQuantity: a.ReorderQuantity,
+		Status:          string(a.Status),
+		AcknowledgedBy:  a.AcknowledgedBy,
+		AcknowledgedAt:  a.AcknowledgedAt,
+		ResolvedAt:      a.ResolvedAt,
+		CreatedAt:       a.CreatedAt,
+	}
+}
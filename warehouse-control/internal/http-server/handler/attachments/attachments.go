@@ -0,0 +1,177 @@
+package attachments_handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/attachments/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// multipartOverhead is the room left in an upload request for the multipart
+// framing around the file.
+const multipartOverhead = 1 << 20
+
+type AttachmentsHandler struct {
+	attachmentsUsecase attachmentsUsecase
+	maxSize            int64
+	logger             *zlog.Zerolog
+}
+
+// NewHandler cuts off upload requests well beyond maxSize before they are
+// read; the exact limit is enforced by the usecase.
+func NewHandler(attachmentsUsecase attachmentsUsecase, maxSize int64, logger *zlog.Zerolog) *AttachmentsHandler {
+	return &AttachmentsHandler{
+		attachmentsUsecase: attachmentsUsecase,
+		maxSize:            maxSize,
+		logger:             logger,
+	}
+}
+
+func (h *AttachmentsHandler) UploadAttachment(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(c, customErr.ErrAttachmentTooLarge)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to read uploaded file")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to open uploaded file")
+		h.writeError(c, customErr.ErrInternal)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	a, err := h.attachmentsUsecase.UploadAttachment(c.Request.Context(), itemID, header.Filename, file, claims.Username)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToAttachmentResponse(a))
+	h.logger.Info().Int64("id", a.ID).Int64("item_id", itemID).Str("user", claims.Username).Msg("Attachment uploaded")
+}
+
+func (h *AttachmentsHandler) GetAttachments(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	attachments, err := h.attachmentsUsecase.GetAttachments(c.Request.Context(), itemID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.AttachmentsResponse{Attachments: make([]*dto.AttachmentResponse, len(attachments))}
+	for i, a := range attachments {
+		resp.Attachments[i] = dto.ToAttachmentResponse(a)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AttachmentsHandler) DownloadAttachment(c *gin.Context) {
+	h.serve(c, false)
+}
+
+func (h *AttachmentsHandler) DownloadThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+func (h *AttachmentsHandler) serve(c *gin.Context, thumbnail bool) {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("attachment_id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	a, content, err := h.attachmentsUsecase.OpenAttachment(c.Request.Context(), itemID, id, thumbnail)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	contentType, size := a.ContentType, a.Size
+	if thumbnail {
+		contentType, size = "image/jpeg", -1
+	}
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName})
+	c.DataFromReader(http.StatusOK, size, contentType, content, map[string]string{
+		"Content-Disposition":    disposition,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *AttachmentsHandler) DeleteAttachment(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("attachment_id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.attachmentsUsecase.DeleteAttachment(c.Request.Context(), itemID, id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("id", id).Int64("item_id", itemID).Str("user", claims.Username).Msg("Attachment deleted")
+}
+
+func (h *AttachmentsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrAttachmentNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrAttachmentTooLarge):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, customErr.ErrUnsupportedMediaType):
+		code = http.StatusUnsupportedMediaType
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,15 @@
+package attachments_handler
+
+import (
+	"context"
+	"io"
+
+	"warehouse-control/internal/domain"
+)
+
+type attachmentsUsecase interface {
+	UploadAttachment(ctx context.Context, itemID int64, fileName string, r io.Reader, username string) (*domain.Attachment, error)
+	GetAttachments(ctx context.Context, itemID int64) ([]*domain.Attachment, error)
+	OpenAttachment(ctx context.Context, itemID, id int64, thumbnail bool) (*domain.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, itemID, id int64, username string) error
+}
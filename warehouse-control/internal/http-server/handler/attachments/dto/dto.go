@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type AttachmentResponse struct {
+	ID           int64     `json:"id"`
+	ItemID       int64     `json:"item_id"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	UploadedBy   string    `json:"uploaded_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type AttachmentsResponse struct {
+	Attachments []*AttachmentResponse `json:"attachments"`
+}
+
+func ToAttachmentResponse(a *domain.Attachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:           a.ID,
+		ItemID:       a.ItemID,
+		FileName:     a.FileName,
+		ContentType:  a.ContentType,
+		Size:         a.Size,
+		HasThumbnail: a.ThumbnailKey != "",
+		UploadedBy:   a.UploadedBy,
+		CreatedAt:    a.CreatedAt,
+	}
+}
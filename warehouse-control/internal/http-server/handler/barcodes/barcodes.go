@@ -0,0 +1,135 @@
+package barcodes_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/barcodes/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type BarcodesHandler struct {
+	barcodesUsecase barcodesUsecase
+	logger          *zlog.Zerolog
+}
+
+func NewHandler(barcodesUsecase barcodesUsecase, logger *zlog.Zerolog) *BarcodesHandler {
+	return &BarcodesHandler{
+		barcodesUsecase: barcodesUsecase,
+		logger:          logger,
+	}
+}
+
+func (h *BarcodesHandler) AddBarcode(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.AddBarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	b := &domain.ItemBarcode{
+		ItemID:     itemID,
+		Code:       req.Code,
+		Type:       domain.BarcodeType(req.Type),
+		SupplierID: req.SupplierID,
+	}
+	if err := h.barcodesUsecase.AddBarcode(c.Request.Context(), b); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToBarcodeResponse(b))
+	h.logger.Info().Int64("item_id", itemID).Str("code", b.Code).Str("user", claims.Username).Msg("Barcode added")
+}
+
+func (h *BarcodesHandler) GetBarcodes(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	barcodes, err := h.barcodesUsecase.GetBarcodes(c.Request.Context(), itemID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.BarcodesResponse{Barcodes: make([]*dto.BarcodeResponse, len(barcodes))}
+	for i, b := range barcodes {
+		resp.Barcodes[i] = dto.ToBarcodeResponse(b)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *BarcodesHandler) DeleteBarcode(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("barcode_id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.barcodesUsecase.DeleteBarcode(c.Request.Context(), itemID, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("item_id", itemID).Int64("id", id).Str("user", claims.Username).Msg("Barcode deleted")
+}
+
+// Scan resolves a raw scanned code. The route takes the rest of the path,
+// since location paths contain slashes.
+func (h *BarcodesHandler) Scan(c *gin.Context) {
+	code := strings.TrimPrefix(c.Param("code"), "/")
+	result, err := h.barcodesUsecase.Scan(c.Request.Context(), code)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToScanResponse(result))
+}
+
+func (h *BarcodesHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrBarcodeNotFound),
+		errors.Is(err, customErr.ErrSupplierNotFound), errors.Is(err, customErr.ErrCodeNotRecognized),
+		errors.Is(err, customErr.ErrLocationNotFound), errors.Is(err, customErr.ErrLotNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrBarcodeExists), errors.Is(err, customErr.ErrAmbiguousCode):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
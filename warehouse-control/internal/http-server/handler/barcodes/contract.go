@@ -0,0 +1,14 @@
+package barcodes_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type barcodesUsecase interface {
+	AddBarcode(ctx context.Context, b *domain.ItemBarcode) error
+	GetBarcodes(ctx context.Context, itemID int64) ([]*domain.ItemBarcode, error)
+	DeleteBarcode(ctx context.Context, itemID, id int64) error
+	Scan(ctx context.Context, code string) (*domain.ScanResult, error)
+}
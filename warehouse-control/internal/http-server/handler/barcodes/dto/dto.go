@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type AddBarcodeRequest struct {
+	Code       string `json:"code" binding:"required"`
+	Type       string `json:"type" binding:"required"`
+	SupplierID *int64 `json:"supplier_id,omitempty"`
+}
+
+type BarcodeResponse struct {
+	ID         int64     `json:"id"`
+	ItemID     int64     `json:"item_id"`
+	Code       string    `json:"code"`
+	Type       string    `json:"type"`
+	SupplierID *int64    `json:"supplier_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type BarcodesResponse struct {
+	Barcodes []*BarcodeResponse `json:"barcodes"`
+}
+
+// ScanResponse is kept small for handheld terminals: only what is shown on
+// their screens.
+type ScanResponse struct {
+	Code     string        `json:"code"`
+	Type     string        `json:"type"`
+	Item     *ScanItem     `json:"item,omitempty"`
+	Location *ScanLocation `json:"location,omitempty"`
+	Lot      *ScanLot      `json:"lot,omitempty"`
+}
+
+type ScanItem struct {
+	ID         int64        `json:"id"`
+	SKU        string       `json:"sku"`
+	Name       string       `json:"name"`
+	Unit       string       `json:"unit"`
+	OnHand     int          `json:"on_hand"`
+	Available  int          `json:"available"`
+	Serialized bool         `json:"serialized,omitempty"`
+	Bins       []*ScanStock `json:"bins,omitempty"`
+}
+
+type ScanStock struct {
+	LocationID int64  `json:"location_id"`
+	Path       string `json:"path"`
+	Quantity   int    `json:"quantity"`
+}
+
+type ScanLocation struct {
+	ID         int64              `json:"id"`
+	Path       string             `json:"path"`
+	Name       string             `json:"name"`
+	Type       string             `json:"location_type"`
+	Items      []*ScanLocationRow `json:"items"`
+	ItemsTotal int                `json:"items_total"`
+}
+
+type ScanLocationRow struct {
+	ItemID   int64  `json:"item_id"`
+	SKU      string `json:"sku"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+type ScanLot struct {
+	ID        int64  `json:"id"`
+	LotNumber string `json:"lot_number"`
+	Quantity  int    `json:"quantity"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Expired   bool   `json:"expired,omitempty"`
+}
+
+func ToBarcodeResponse(b *domain.ItemBarcode) *BarcodeResponse {
+	return &BarcodeResponse{
+		ID:         b.ID,
+		ItemID:     b.ItemID,
+		Code:       b.Code,
+		Type:       string(b.Type),
+		SupplierID: b.SupplierID,
+		CreatedAt:  b.CreatedAt,
+	}
+}
+
+func ToScanResponse(r *domain.ScanResult) *ScanResponse {
+	resp := &ScanResponse{Code: r.Code, Type: string(r.Target)}
+	if r.Item != nil {
+		resp.Item = &ScanItem{
+			ID:         r.Item.ID,
+			SKU:        r.Item.SKU,
+			Name:       r.Item.Name,
+			Unit:       r.Item.BaseUnit,
+			OnHand:     r.Item.Quantity,
+			Available:  r.Item.Available(),
+			Serialized: r.Item.Serialized,
+		}
+		for _, st := range r.Item.Stock {
+			resp.Item.Bins = append(resp.Item.Bins, &ScanStock{
+				LocationID: st.LocationID,
+				Path:       st.LocationPath,
+				Quantity:   st.Quantity,
+			})
+		}
+	}
+	if r.Location != nil {
+		resp.Location = &ScanLocation{
+			ID:         r.Location.ID,
+			Path:       r.Location.Path,
+			Name:       r.Location.Name,
+			Type:       string(r.Location.Type),
+			Items:      make([]*ScanLocationRow, len(r.Contents)),
+			ItemsTotal: r.ContentsTotal,
+		}
+		for i, c := range r.Contents {
+			resp.Location.Items[i] = &ScanLocationRow{ItemID: c.ItemID, SKU: c.SKU, Name: c.Name, Quantity: c.Quantity}
+		}
+	}
+	if r.Lot != nil {
+		resp.Lot = &ScanLot{
+			ID:        r.Lot.ID,
+			LotNumber: r.Lot.LotNumber,
+			Quantity:  r.Lot.Quantity,
+			Expired:   r.Lot.IsExpired(time.Now()),
+		}
+		if r.Lot.ExpiresAt != nil {
+			resp.Lot.ExpiresAt = r.Lot.ExpiresAt.Format(time.DateOnly)
+		}
+	}
+	return resp
+}
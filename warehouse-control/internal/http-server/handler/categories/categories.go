@@ -0,0 +1,228 @@
+package categories_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/categories/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type CategoriesHandler struct {
+	categoriesUsecase categoriesUsecase
+	logger            *zlog.Zerolog
+}
+
+func NewHandler(categoriesUsecase categoriesUsecase, logger *zlog.Zerolog) *CategoriesHandler {
+	return &CategoriesHandler{
+		categoriesUsecase: categoriesUsecase,
+		logger:            logger,
+	}
+}
+
+func (h *CategoriesHandler) CreateCategory(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	category := &domain.Category{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+	id, err := h.categoriesUsecase.CreateCategory(c.Request.Context(), category)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateCategory failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id, "path": category.Path})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Category created")
+}
+
+func (h *CategoriesHandler) GetCategories(c *gin.Context) {
+	roots, err := h.categoriesUsecase.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetCategories failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.CategoriesResponse{Categories: make([]*dto.CategoryResponse, len(roots))}
+	for i, root := range roots {
+		resp.Categories[i] = dto.ToCategoryResponse(root)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CategoriesHandler) GetCategoryByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	category, err := h.categoriesUsecase.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToCategoryResponse(category))
+}
+
+func (h *CategoriesHandler) UpdateCategory(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	category := &domain.Category{
+		ID:       id,
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+	if err := h.categoriesUsecase.UpdateCategory(c.Request.Context(), category, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id, "path": category.Path})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Category updated")
+}
+
+func (h *CategoriesHandler) DeleteCategory(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.categoriesUsecase.DeleteCategory(c.Request.Context(), id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Category deleted")
+}
+
+func (h *CategoriesHandler) GetAttributes(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	schema, err := h.categoriesUsecase.GetCategoryAttributes(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.AttributesResponse{
+		CategoryID: id,
+		Attributes: make([]*dto.AttributeResponse, len(schema)),
+	}
+	for i, d := range schema {
+		resp.Attributes[i] = dto.ToAttributeResponse(d)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CategoriesHandler) SetAttribute(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.SetAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	def := &domain.AttributeDefinition{
+		CategoryID: id,
+		Name:       c.Param("name"),
+		Type:       domain.AttributeType(req.Type),
+		Required:   req.Required,
+		Options:    req.Options,
+	}
+	if err := h.categoriesUsecase.SetAttribute(c.Request.Context(), def, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToAttributeResponse(def))
+	h.logger.Info().Int64("category_id", id).Str("attribute", def.Name).Str("user", claims.Username).Msg("Attribute set")
+}
+
+func (h *CategoriesHandler) DeleteAttribute(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	name := c.Param("name")
+	if err := h.categoriesUsecase.DeleteAttribute(c.Request.Context(), id, name, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("category_id", id).Str("attribute", name).Str("user", claims.Username).Msg("Attribute deleted")
+}
+
+func (h *CategoriesHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrCategoryNotFound), errors.Is(err, customErr.ErrAttributeNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrCategoryExists), errors.Is(err, customErr.ErrCategoryInUse):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,18 @@
+package categories_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type categoriesUsecase interface {
+	CreateCategory(ctx context.Context, c *domain.Category) (int64, error)
+	GetCategoryTree(ctx context.Context) ([]*domain.Category, error)
+	GetCategoryByID(ctx context.Context, id int64) (*domain.Category, error)
+	UpdateCategory(ctx context.Context, c *domain.Category, username string) error
+	DeleteCategory(ctx context.Context, id int64, username string) error
+	GetCategoryAttributes(ctx context.Context, id int64) (domain.AttributeSchema, error)
+	SetAttribute(ctx context.Context, d *domain.AttributeDefinition, username string) error
+	DeleteAttribute(ctx context.Context, categoryID int64, name, username string) error
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type CreateCategoryRequest struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+}
+
+// UpdateCategoryRequest replaces the name and the parent of a category; a
+// missing parent_id moves it to the root.
+type UpdateCategoryRequest struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+}
+
+type CategoryResponse struct {
+	ID        int64               `json:"id"`
+	ParentID  *int64              `json:"parent_id,omitempty"`
+	Name      string              `json:"name"`
+	Path      string              `json:"path"`
+	Children  []*CategoryResponse `json:"children"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+type CategoriesResponse struct {
+	Categories []*CategoryResponse `json:"categories"`
+}
+
+type SetAttributeRequest struct {
+	Type     string   `json:"type" binding:"required"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// AttributeResponse is a custom attribute; CategoryID is the category it is
+// defined on, an ancestor for inherited attributes.
+type AttributeResponse struct {
+	CategoryID int64     `json:"category_id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Required   bool      `json:"required"`
+	Options    []string  `json:"options,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type AttributesResponse struct {
+	CategoryID int64                `json:"category_id"`
+	Attributes []*AttributeResponse `json:"attributes"`
+}
+
+func ToAttributeResponse(d *domain.AttributeDefinition) *AttributeResponse {
+	return &AttributeResponse{
+		CategoryID: d.CategoryID,
+		Name:       d.Name,
+		Type:       string(d.Type),
+		Required:   d.Required,
+		Options:    d.Options,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+func ToCategoryResponse(c *domain.Category) *CategoryResponse {
+	resp := &CategoryResponse{
+		ID:        c.ID,
+		ParentID:  c.ParentID,
+		Name:      c.Name,
+		Path:      c.Path,
+		Children:  make([]*CategoryResponse, len(c.Children)),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	for i, child := range c.Children {
+		resp.Children[i] = ToCategoryResponse(child)
+	}
+	return resp
+}
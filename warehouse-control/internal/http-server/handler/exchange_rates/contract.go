@@ -0,0 +1,14 @@
+package exchange_rates_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type exchangeRatesUsecase interface {
+	BaseCurrency() string
+	GetRates(ctx context.Context) ([]*domain.ExchangeRate, error)
+	SetRate(ctx context.Context, rate *domain.ExchangeRate) error
+	DeleteRate(ctx context.Context, currency string) error
+}
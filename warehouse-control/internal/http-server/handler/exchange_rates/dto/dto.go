@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type SetRateRequest struct {
+	Rate decimal.Decimal `json:"rate"`
+}
+
+type ExchangeRateResponse struct {
+	Currency  string          `json:"currency"`
+	Rate      decimal.Decimal `json:"rate"`
+	UpdatedBy string          `json:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type ExchangeRatesResponse struct {
+	Base  string                  `json:"base"`
+	Rates []*ExchangeRateResponse `json:"rates"`
+}
+
+func ToExchangeRateResponse(r *domain.ExchangeRate) *ExchangeRateResponse {
+	return &ExchangeRateResponse{
+		Currency:  r.Currency,
+		Rate:      r.Rate,
+		UpdatedBy: r.UpdatedBy,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
@@ -0,0 +1,100 @@
+package exchange_rates_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/exchange_rates/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ExchangeRatesHandler struct {
+	exchangeRatesUsecase exchangeRatesUsecase
+	logger               *zlog.Zerolog
+}
+
+func NewHandler(exchangeRatesUsecase exchangeRatesUsecase, logger *zlog.Zerolog) *ExchangeRatesHandler {
+	return &ExchangeRatesHandler{
+		exchangeRatesUsecase: exchangeRatesUsecase,
+		logger:               logger,
+	}
+}
+
+func (h *ExchangeRatesHandler) GetRates(c *gin.Context) {
+	rates, err := h.exchangeRatesUsecase.GetRates(c.Request.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetRates failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.ExchangeRatesResponse{
+		Base:  h.exchangeRatesUsecase.BaseCurrency(),
+		Rates: make([]*dto.ExchangeRateResponse, len(rates)),
+	}
+	for i, r := range rates {
+		resp.Rates[i] = dto.ToExchangeRateResponse(r)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ExchangeRatesHandler) SetRate(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.SetRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	rate := &domain.ExchangeRate{
+		Currency:  c.Param("currency"),
+		Rate:      req.Rate,
+		UpdatedBy: claims.Username,
+	}
+	if err := h.exchangeRatesUsecase.SetRate(c.Request.Context(), rate); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToExchangeRateResponse(rate))
+	h.logger.Info().Str("currency", rate.Currency).Str("user", claims.Username).Msg("Exchange rate set")
+}
+
+func (h *ExchangeRatesHandler) DeleteRate(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	if err := h.exchangeRatesUsecase.DeleteRate(c.Request.Context(), c.Param("currency")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Str("currency", c.Param("currency")).Str("user", claims.Username).Msg("Exchange rate deleted")
+}
+
+func (h *ExchangeRatesHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrExchangeRateNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
@@ -9,4 +9,5 @@ import (
 type historyUsecase interface {
 	GetHistory(ctx context.Context, filter domain.HistoryFilter) ([]*domain.HistoryRecord, error)
 	GetHistoryByItemID(ctx context.Context, itemID int64) ([]*domain.HistoryRecord, error)
+	ExportHistory(ctx context.Context, filter domain.HistoryFilter, fn func(*domain.HistoryRecord) error) error
 }
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
 )
 
 type HistoryResponse struct {
@@ -11,30 +12,49 @@ type HistoryResponse struct {
 }
 
 type HistoryRecordResponse struct {
-	ID        int64     `json:"id"`
-	ItemID    int64     `json:"item_id"`
-	Action    string    `json:"action"`
-	OldData   *ItemData `json:"old_data,omitempty"`
-	NewData   *ItemData `json:"new_data,omitempty"`
-	ChangedBy string    `json:"changed_by"`
-	ChangedAt time.Time `json:"changed_at"`
+	ID          int64           `json:"id"`
+	ItemID      int64           `json:"item_id"`
+	Action      string          `json:"action"`
+	OldData     *ItemData       `json:"old_data,omitempty"`
+	NewData     *ItemData       `json:"new_data,omitempty"`
+	ChangedBy   string          `json:"changed_by"`
+	ChangedAt   time.Time       `json:"changed_at"`
+	StocktakeID *int64          `json:"stocktake_id,omitempty"`
+	Attachment  *AttachmentData `json:"attachment,omitempty"`
 }
 
 type ItemData struct {
-	ID       int64   `json:"id"`
-	Name     string  `json:"name"`
-	SKU      string  `json:"sku"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
+	ID       int64           `json:"id"`
+	Name     string          `json:"name"`
+	SKU      string          `json:"sku"`
+	Quantity int             `json:"quantity"`
+	Price    decimal.Decimal `json:"price"`
+	Currency string          `json:"currency"`
+}
+
+type AttachmentData struct {
+	ID          int64  `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
 }
 
 func ToHistoryRecordResponse(rec *domain.HistoryRecord) *HistoryRecordResponse {
 	resp := &HistoryRecordResponse{
-		ID:        rec.ID,
-		ItemID:    rec.ItemID,
-		Action:    rec.Action,
-		ChangedBy: rec.ChangedBy,
-		ChangedAt: rec.ChangedAt,
+		ID:          rec.ID,
+		ItemID:      rec.ItemID,
+		Action:      rec.Action,
+		ChangedBy:   rec.ChangedBy,
+		ChangedAt:   rec.ChangedAt,
+		StocktakeID: rec.StocktakeID,
+	}
+	if a := rec.Attachment; a != nil {
+		resp.Attachment = &AttachmentData{
+			ID:          a.ID,
+			FileName:    a.FileName,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+		}
 	}
 	if rec.OldData != nil {
 		resp.OldData = &ItemData{
@@ -42,7 +62,8 @@ func ToHistoryRecordResponse(rec *domain.HistoryRecord) *HistoryRecordResponse {
 			Name:     rec.OldData.Name,
 			SKU:      rec.OldData.SKU,
 			Quantity: rec.OldData.Quantity,
-			Price:    rec.OldData.Price,
+			Price:    rec.OldData.Price.Amount,
+			Currency: rec.OldData.Price.Currency,
 		}
 	}
 	if rec.NewData != nil {
@@ -51,7 +72,8 @@ func ToHistoryRecordResponse(rec *domain.HistoryRecord) *HistoryRecordResponse {
 			Name:     rec.NewData.Name,
 			SKU:      rec.NewData.SKU,
 			Quantity: rec.NewData.Quantity,
-			Price:    rec.NewData.Price,
+			Price:    rec.NewData.Price.Amount,
+			Currency: rec.NewData.Price.Currency,
 		}
 	}
 	return resp
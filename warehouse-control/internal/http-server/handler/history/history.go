@@ -1,14 +1,15 @@
 package history_handler
 
 import (
-	"encoding/csv"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"warehouse-control/internal/domain"
 	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/export"
 	"warehouse-control/internal/http-server/handler/history/dto"
 
 	"github.com/gin-gonic/gin"
@@ -28,41 +29,7 @@ func NewHandler(historyUsecase historyUsecase, logger *zlog.Zerolog) *HistoryHan
 }
 
 func (h *HistoryHandler) GetHistory(c *gin.Context) {
-	filter := domain.HistoryFilter{
-		Limit:  100,
-		Offset: 0,
-	}
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			filter.Limit = l
-		}
-	}
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			filter.Offset = o
-		}
-	}
-	if itemID := c.Query("item_id"); itemID != "" {
-		if id, err := strconv.ParseInt(itemID, 10, 64); err == nil && id > 0 {
-			filter.ItemID = &id
-		}
-	}
-	if action := c.Query("action"); action != "" {
-		filter.Action = &action
-	}
-	if username := c.Query("username"); username != "" {
-		filter.Username = &username
-	}
-	if dateFrom := c.Query("date_from"); dateFrom != "" {
-		if t, err := time.Parse(time.RFC3339, dateFrom); err == nil {
-			filter.DateFrom = &t
-		}
-	}
-	if dateTo := c.Query("date_to"); dateTo != "" {
-		if t, err := time.Parse(time.RFC3339, dateTo); err == nil {
-			filter.DateTo = &t
-		}
-	}
+	filter := historyFilter(c, 100)
 	records, err := h.historyUsecase.GetHistory(c.Request.Context(), filter)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("GetHistory failed")
@@ -101,12 +68,46 @@ func (h *HistoryHandler) GetItemHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-func (h *HistoryHandler) ExportHistoryCSV(c *gin.Context) {
-	filter := domain.HistoryFilter{
-		Limit:  1000,
-		Offset: 0,
+// ExportHistory streams the records matching the filters of GetHistory as a
+// CSV, JSON Lines or XLSX file with the item before and after each change.
+// Without a limit every matching record is exported.
+func (h *HistoryHandler) ExportHistory(c *gin.Context) {
+	format := domain.ExportFormat(c.DefaultQuery("format", string(domain.ExportCSV)))
+	if !format.IsValid() {
+		h.writeError(c, fmt.Errorf("%w: unknown export format %q", customErr.ErrInvalidInput, format))
+		return
+	}
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=history_export.%s", format))
+
+	w, err := export.NewWriter(c.Writer, format, domain.HistoryExportHeader)
+	if err != nil {
+		h.abortExport(c, err)
+		return
+	}
+	err = h.historyUsecase.ExportHistory(c.Request.Context(), historyFilter(c, 0), func(rec *domain.HistoryRecord) error {
+		return w.WriteRow(domain.HistoryExportRow(rec))
+	})
+	if err != nil {
+		w.Abort()
+		h.abortExport(c, err)
+		return
 	}
+	if err := w.Close(); err != nil {
+		h.abortExport(c, err)
+	}
+}
+
+func (h *HistoryHandler) abortExport(c *gin.Context, err error) {
+	h.logger.Error().Err(err).Msg("ExportHistory failed")
+	export.AbortResponse(c.Writer)
+	h.writeError(c, err)
+}
 
+// historyFilter reads the filters of a history listing from the query, with
+// the given limit unless the query sets one.
+func historyFilter(c *gin.Context, limit int) domain.HistoryFilter {
+	filter := domain.HistoryFilter{Limit: limit}
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil {
 			filter.Limit = l
@@ -138,54 +139,12 @@ func (h *HistoryHandler) ExportHistoryCSV(c *gin.Context) {
 			filter.DateTo = &t
 		}
 	}
-	records, err := h.historyUsecase.GetHistory(c.Request.Context(), filter)
-	if err != nil {
-		h.logger.Error().Err(err).Msg("ExportHistoryCSV failed")
-		h.writeError(c, err)
-		return
-	}
-
-	c.Header("Content-Type", "text/csv")
-	c.Header("Content-Disposition", "attachment; filename=history_export.csv")
-
-	writer := csv.NewWriter(c.Writer)
-	defer writer.Flush()
-
-	if err := writer.Write([]string{"ID", "Item ID", "Action", "Changed By", "Changed At", "Old Name", "Old SKU", "Old Quantity", "Old Price", "New Name", "New SKU", "New Quantity", "New Price"}); err != nil {
-		h.logger.Error().Err(err).Msg("Failed to write CSV header")
-		h.writeError(c, customErr.ErrInternal)
-		return
-	}
-
-	for _, rec := range records {
-		oldName, oldSKU, oldQty, oldPrice := "", "", "0", "0"
-		newName, newSKU, newQty, newPrice := "", "", "0", "0"
-		if rec.OldData != nil {
-			oldName = rec.OldData.Name
-			oldSKU = rec.OldData.SKU
-			oldQty = strconv.Itoa(rec.OldData.Quantity)
-			oldPrice = strconv.FormatFloat(rec.OldData.Price, 'f', 2, 64)
-		}
-		if rec.NewData != nil {
-			newName = rec.NewData.Name
-			newSKU = rec.NewData.SKU
-			newQty = strconv.Itoa(rec.NewData.Quantity)
-			newPrice = strconv.FormatFloat(rec.NewData.Price, 'f', 2, 64)
-		}
-		if err := writer.Write([]string{
-			strconv.FormatInt(rec.ID, 10),
-			strconv.FormatInt(rec.ItemID, 10),
-			rec.Action,
-			rec.ChangedBy,
-			rec.ChangedAt.Format(time.RFC3339),
-			oldName, oldSKU, oldQty, oldPrice,
-			newName, newSKU, newQty, newPrice,
-		}); err != nil {
-			h.logger.Error().Err(err).Msg("Failed to write CSV row")
-			h.writeError(c, customErr.ErrInternal)
-			return
+	if stocktakeID := c.Query("stocktake_id"); stocktakeID != "" {
+		if id, err := strconv.ParseInt(stocktakeID, 10, 64); err == nil && id > 0 {
+			filter.StocktakeID = &id
 		}
 	}
+	return filter
 }
 
 func (h *HistoryHandler) writeError(c *gin.Context, err error) {
@@ -0,0 +1,13 @@
+package imports_handler
+
+import (
+	"context"
+	"io"
+
+	"warehouse-control/internal/domain"
+)
+
+type importsUsecase interface {
+	StartImport(ctx context.Context, req domain.ImportRequest, r io.Reader, username string) (*domain.ImportJob, error)
+	GetImportJob(ctx context.Context, id int64) (*domain.ImportJob, error)
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type ImportJobResponse struct {
+	ID            int64                     `json:"id"`
+	FileName      string                    `json:"file_name"`
+	Format        string                    `json:"format"`
+	Mode          string                    `json:"mode"`
+	DryRun        bool                      `json:"dry_run"`
+	Status        string                    `json:"status"`
+	TotalRows     int                       `json:"total_rows"`
+	ProcessedRows int                       `json:"processed_rows"`
+	Created       int                       `json:"created"`
+	Updated       int                       `json:"updated"`
+	Failed        int                       `json:"failed"`
+	Errors        []*ImportRowErrorResponse `json:"errors"`
+	Message       string                    `json:"message,omitempty"`
+	CreatedBy     string                    `json:"created_by"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	StartedAt     *time.Time                `json:"started_at,omitempty"`
+	FinishedAt    *time.Time                `json:"finished_at,omitempty"`
+}
+
+type ImportRowErrorResponse struct {
+	Row   int    `json:"row"`
+	SKU   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+func ToImportJobResponse(j *domain.ImportJob) *ImportJobResponse {
+	resp := &ImportJobResponse{
+		ID:            j.ID,
+		FileName:      j.FileName,
+		Format:        string(j.Format),
+		Mode:          string(j.Mode),
+		DryRun:        j.DryRun,
+		Status:        string(j.Status),
+		TotalRows:     j.TotalRows,
+		ProcessedRows: j.ProcessedRows,
+		Created:       j.Created,
+		Updated:       j.Updated,
+		Failed:        j.Failed,
+		Errors:        make([]*ImportRowErrorResponse, len(j.Errors)),
+		Message:       j.Message,
+		CreatedBy:     j.CreatedBy,
+		CreatedAt:     j.CreatedAt,
+		StartedAt:     j.StartedAt,
+		FinishedAt:    j.FinishedAt,
+	}
+	for i, e := range j.Errors {
+		resp.Errors[i] = &ImportRowErrorResponse{Row: e.Row, SKU: e.SKU, Error: e.Error}
+	}
+	return resp
+}
@@ -0,0 +1,132 @@
+package imports_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/imports/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+// multipartOverhead is the room left in an upload request for the multipart
+// framing and the form fields around the file.
+const multipartOverhead = 1 << 20
+
+type ImportsHandler struct {
+	importsUsecase importsUsecase
+	maxSize        int64
+	logger         *zlog.Zerolog
+}
+
+// NewHandler cuts off upload requests well beyond maxSize before they are
+// read; the exact limit is enforced by the usecase.
+func NewHandler(importsUsecase importsUsecase, maxSize int64, logger *zlog.Zerolog) *ImportsHandler {
+	return &ImportsHandler{
+		importsUsecase: importsUsecase,
+		maxSize:        maxSize,
+		logger:         logger,
+	}
+}
+
+// ImportItems takes a CSV or XLSX file in the file field of a multipart
+// form, with the optional fields mode, dry_run and mapping (a JSON object
+// of item field to column header). A finished job is returned with 200, a
+// queued one with 202.
+func (h *ImportsHandler) ImportItems(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(c, customErr.ErrImportTooLarge)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to read uploaded file")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	req := domain.ImportRequest{
+		FileName: header.Filename,
+		Mode:     domain.ImportMode(c.PostForm("mode")),
+	}
+	if dryRun := c.PostForm("dry_run"); dryRun != "" {
+		if req.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+	}
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to open uploaded file")
+		h.writeError(c, customErr.ErrInternal)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	job, err := h.importsUsecase.StartImport(c.Request.Context(), req, file, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Str("file", header.Filename).Msg("StartImport failed")
+		h.writeError(c, err)
+		return
+	}
+	code := http.StatusOK
+	if job.Status == domain.ImportQueued {
+		code = http.StatusAccepted
+	}
+	c.JSON(code, dto.ToImportJobResponse(job))
+}
+
+func (h *ImportsHandler) GetImportJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	job, err := h.importsUsecase.GetImportJob(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToImportJobResponse(job))
+}
+
+func (h *ImportsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrImportNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrImportTooLarge):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, customErr.ErrImportQueueFull):
+		code = http.StatusServiceUnavailable
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
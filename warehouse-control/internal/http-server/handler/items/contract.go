@@ -8,9 +8,16 @@ import (
 
 type itemsUsecase interface {
 	CreateItem(ctx context.Context, item *domain.Item, username string) (int64, error)
-	GetItems(ctx context.Context, limit, offset int, search string) ([]*domain.Item, int, error)
+	CreateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode, username string) ([]*domain.BulkItemResult, error)
+	GetItems(ctx context.Context, filter domain.ItemFilter) ([]*domain.Item, int, error)
+	GetStockTotals(ctx context.Context, filter domain.ItemFilter) (*domain.StockTotals, error)
+	ExportItems(ctx context.Context, filter domain.ItemFilter, fn func(*domain.Item) error) error
 	GetItemByID(ctx context.Context, id int64) (*domain.Item, error)
-	UpdateItem(ctx context.Context, id int64, item *domain.Item, username string) error
+	ConvertPrices(ctx context.Context, items []*domain.Item, currency string) error
+	GroupByProduct(ctx context.Context, items []*domain.Item) ([]*domain.Item, []*domain.Product, error)
+	UpdateItem(ctx context.Context, id int64, patch *domain.ItemPatch, username string) error
+	UpdateItems(ctx context.Context, updates []*domain.ItemUpdate, mode domain.BulkMode, username string) ([]*domain.BulkItemResult, error)
+	ChangeStockStatus(ctx context.Context, change *domain.StockStatusChange, username string) error
 	DeleteItem(ctx context.Context, id int64, username string) error
 	BulkDeleteItems(ctx context.Context, ids []int64, username string) error
 }
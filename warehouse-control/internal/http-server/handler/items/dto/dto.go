@@ -3,53 +3,245 @@ package dto
 import (
 	"time"
 	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
 )
 
 type CreateItemRequest struct {
-	Name     string  `json:"name"`
-	SKU      string  `json:"sku"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
-	Category string  `json:"category"`
-	Location string  `json:"location"`
+	Name            string          `json:"name"`
+	SKU             string          `json:"sku"`
+	Quantity        int             `json:"quantity"`
+	Price           decimal.Decimal `json:"price"`
+	Currency        string          `json:"currency"`
+	Category        string          `json:"category"`
+	Location        string          `json:"location"`
+	Serialized      bool            `json:"serialized"`
+	ReorderLevel    int             `json:"reorder_level"`
+	ReorderQuantity int             `json:"reorder_quantity"`
+	CostMethod      string          `json:"cost_method,omitempty"`
+	UnitCost        decimal.Decimal `json:"unit_cost"`
+	BaseUnit        string          `json:"base_unit"`
+	Attributes      map[string]any  `json:"attributes,omitempty"`
 }
 
 type UpdateItemRequest struct {
-	Name     string   `json:"name,omitempty"`
-	SKU      string   `json:"sku,omitempty"`
-	Quantity *int     `json:"quantity,omitempty"`
-	Price    *float64 `json:"price,omitempty"`
-	Category string   `json:"category,omitempty"`
-	Location string   `json:"location,omitempty"`
+	Name            string           `json:"name,omitempty"`
+	SKU             string           `json:"sku,omitempty"`
+	Quantity        *int             `json:"quantity,omitempty"`
+	Price           *decimal.Decimal `json:"price,omitempty"`
+	Currency        string           `json:"currency,omitempty"`
+	Category        string           `json:"category,omitempty"`
+	Location        string           `json:"location,omitempty"`
+	Serialized      *bool            `json:"serialized,omitempty"`
+	ReorderLevel    *int             `json:"reorder_level,omitempty"`
+	ReorderQuantity *int             `json:"reorder_quantity,omitempty"`
+	CostMethod      *string          `json:"cost_method,omitempty"`
+	BaseUnit        string           `json:"base_unit,omitempty"`
+	// Attributes replaces all custom attribute values when present.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// BulkCreateItemsRequest is a batch of items to create. Mode is atomic, the
+// default, or best_effort.
+type BulkCreateItemsRequest struct {
+	Mode  string              `json:"mode,omitempty"`
+	Items []CreateItemRequest `json:"items"`
+}
+
+type BulkUpdateItemsRequest struct {
+	Mode  string                  `json:"mode,omitempty"`
+	Items []BulkUpdateItemRequest `json:"items"`
+}
+
+type BulkUpdateItemRequest struct {
+	ID int64 `json:"id"`
+	UpdateItemRequest
+}
+
+type ChangeStockStatusRequest struct {
+	From     string `json:"from" binding:"required"`
+	To       string `json:"to" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required"`
 }
 
 type ItemResponse struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	SKU       string    `json:"sku"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	Category  string    `json:"category"`
-	Location  string    `json:"location"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                int64                   `json:"id"`
+	Name              string                  `json:"name"`
+	SKU               string                  `json:"sku"`
+	Quantity          int                     `json:"quantity"`
+	OnHand            int                     `json:"on_hand"`
+	Reserved          int                     `json:"reserved"`
+	Quarantined       int                     `json:"quarantined"`
+	Damaged           int                     `json:"damaged"`
+	Blocked           int                     `json:"blocked"`
+	Available         int                     `json:"available"`
+	Price             decimal.Decimal         `json:"price"`
+	Currency          string                  `json:"currency"`
+	DisplayPrice      *decimal.Decimal        `json:"display_price,omitempty"`
+	DisplayCurrency   string                  `json:"display_currency,omitempty"`
+	Category          string                  `json:"category"`
+	Location          string                  `json:"location"`
+	Serialized        bool                    `json:"serialized"`
+	BaseUnit          string                  `json:"base_unit"`
+	ProductID         *int64                  `json:"product_id,omitempty"`
+	Variant           map[string]string       `json:"variant,omitempty"`
+	Attributes        map[string]any          `json:"attributes,omitempty"`
+	ReorderLevel      int                     `json:"reorder_level"`
+	ReorderQuantity   int                     `json:"reorder_quantity"`
+	CostMethod        string                  `json:"cost_method,omitempty"`
+	UnitCost          decimal.Decimal         `json:"unit_cost"`
+	WarehouseQuantity *int                    `json:"warehouse_quantity,omitempty"`
+	Stock             []*StockResponse        `json:"stock,omitempty"`
+	KitAvailable      *int                    `json:"kit_available,omitempty"`
+	KitComponents     []*KitComponentResponse `json:"kit_components,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+type StockResponse struct {
+	LocationID   int64  `json:"location_id"`
+	LocationPath string `json:"location_path"`
+	WarehouseID  int64  `json:"warehouse_id"`
+	Quantity     int    `json:"quantity"`
+}
+
+type KitComponentResponse struct {
+	ItemID    int64  `json:"item_id"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"`
+}
+
+type StockTotalsResponse struct {
+	OnHand     int `json:"on_hand"`
+	Reserved   int `json:"reserved"`
+	Available  int `json:"available"`
+	Quarantine int `json:"quarantine"`
+	Damaged    int `json:"damaged"`
+	Blocked    int `json:"blocked"`
+}
+
+type ProductGroupResponse struct {
+	ID       int64           `json:"id"`
+	Name     string          `json:"name"`
+	SKU      string          `json:"sku"`
+	Category string          `json:"category"`
+	Variants []*ItemResponse `json:"variants"`
 }
 
 type ItemsResponse struct {
-	Items []*ItemResponse `json:"items"`
-	Total int             `json:"total"`
+	Items    []*ItemResponse         `json:"items"`
+	Products []*ProductGroupResponse `json:"products,omitempty"`
+	Total    int                     `json:"total"`
+	Totals   *StockTotalsResponse    `json:"totals"`
+}
+
+// BulkItemResult is the outcome for the item at Index of a batch: its ID, or
+// the error it failed with.
+type BulkItemResult struct {
+	Index int    `json:"index"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type BulkItemsResponse struct {
+	Results   []*BulkItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+func ToBulkItemsResponse(results []*domain.BulkItemResult) *BulkItemsResponse {
+	resp := &BulkItemsResponse{Results: make([]*BulkItemResult, len(results))}
+	for i, r := range results {
+		resp.Results[i] = &BulkItemResult{Index: i, ID: r.ID}
+		if r.Err != nil {
+			resp.Results[i].Error = r.Err.Error()
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+	}
+	return resp
 }
 
 func ToItemResponse(item *domain.Item) *ItemResponse {
-	return &ItemResponse{
-		ID:        item.ID,
-		Name:      item.Name,
-		SKU:       item.SKU,
-		Quantity:  item.Quantity,
-		Price:     item.Price,
-		Category:  item.Category,
-		Location:  item.Location,
-		CreatedAt: item.CreatedAt,
-		UpdatedAt: item.UpdatedAt,
+	resp := &ItemResponse{
+		ID:                item.ID,
+		Name:              item.Name,
+		SKU:               item.SKU,
+		Quantity:          item.Quantity,
+		OnHand:            item.Quantity,
+		Reserved:          item.Reserved,
+		Quarantined:       item.Quarantined,
+		Damaged:           item.Damaged,
+		Blocked:           item.Blocked,
+		Available:         item.Available(),
+		Price:             item.Price.Amount,
+		Currency:          item.Price.Currency,
+		Category:          item.Category,
+		Location:          item.Location,
+		Serialized:        item.Serialized,
+		BaseUnit:          item.BaseUnit,
+		ProductID:         item.ProductID,
+		Variant:           item.Variant,
+		Attributes:        item.Attributes,
+		ReorderLevel:      item.ReorderLevel,
+		ReorderQuantity:   item.ReorderQuantity,
+		UnitCost:          item.UnitCost,
+		WarehouseQuantity: item.WarehouseQuantity,
+		CreatedAt:         item.CreatedAt,
+		UpdatedAt:         item.UpdatedAt,
+	}
+	if item.DisplayPrice != nil {
+		resp.DisplayPrice = &item.DisplayPrice.Amount
+		resp.DisplayCurrency = item.DisplayPrice.Currency
+	}
+	if item.CostMethod != nil {
+		resp.CostMethod = string(*item.CostMethod)
+	}
+	if item.Kit != nil {
+		available := item.Kit.Available()
+		resp.KitAvailable = &available
+		for _, c := range item.Kit.Components {
+			resp.KitComponents = append(resp.KitComponents, &KitComponentResponse{
+				ItemID:    c.ItemID,
+				SKU:       c.SKU,
+				Quantity:  c.Quantity,
+				Available: c.Available,
+			})
+		}
+	}
+	for _, st := range item.Stock {
+		resp.Stock = append(resp.Stock, &StockResponse{
+			LocationID:   st.LocationID,
+			LocationPath: st.LocationPath,
+			WarehouseID:  st.WarehouseID,
+			Quantity:     st.Quantity,
+		})
+	}
+	return resp
+}
+
+func ToProductGroupResponse(p *domain.Product) *ProductGroupResponse {
+	resp := &ProductGroupResponse{
+		ID:       p.ID,
+		Name:     p.Name,
+		SKU:      p.SKU,
+		Category: p.Category,
+		Variants: make([]*ItemResponse, len(p.Variants)),
+	}
+	for i, v := range p.Variants {
+		resp.Variants[i] = ToItemResponse(v)
+	}
+	return resp
+}
+
+func ToStockTotalsResponse(t *domain.StockTotals) *StockTotalsResponse {
+	return &StockTotalsResponse{
+		OnHand:     t.OnHand,
+		Reserved:   t.Reserved,
+		Available:  t.Available,
+		Quarantine: t.Quarantine,
+		Damaged:    t.Damaged,
+		Blocked:    t.Blocked,
 	}
 }
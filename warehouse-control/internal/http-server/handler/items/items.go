@@ -2,11 +2,14 @@ package items_handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"warehouse-control/internal/domain"
 	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/export"
 	"warehouse-control/internal/http-server/handler/items/dto"
 	"warehouse-control/internal/http-server/middleware"
 
@@ -38,15 +41,7 @@ func (h *ItemsHandler) CreateItem(c *gin.Context) {
 		h.writeError(c, customErr.ErrInvalidInput)
 		return
 	}
-	item := &domain.Item{
-		Name:     req.Name,
-		SKU:      req.SKU,
-		Quantity: req.Quantity,
-		Price:    req.Price,
-		Category: req.Category,
-		Location: req.Location,
-	}
-	id, err := h.itemsUsecase.CreateItem(c.Request.Context(), item, claims.Username)
+	id, err := h.itemsUsecase.CreateItem(c.Request.Context(), newItem(&req), claims.Username)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("CreateItem failed")
 		h.writeError(c, err)
@@ -64,23 +59,117 @@ func (h *ItemsHandler) GetItems(c *gin.Context) {
 	}
 	offsetStr := c.Query("offset")
 	offset, _ := strconv.Atoi(offsetStr)
-	search := c.Query("search")
-	items, total, err := h.itemsUsecase.GetItems(c.Request.Context(), limit, offset, search)
+	filter := itemFilter(c)
+	filter.Limit = limit
+	filter.Offset = offset
+	items, total, err := h.itemsUsecase.GetItems(c.Request.Context(), filter)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("GetItems failed")
 		h.writeError(c, err)
 		return
 	}
+	totals, err := h.itemsUsecase.GetStockTotals(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetStockTotals failed")
+		h.writeError(c, err)
+		return
+	}
+	if currency := c.Query("currency"); currency != "" {
+		if err := h.itemsUsecase.ConvertPrices(c.Request.Context(), items, currency); err != nil {
+			h.writeError(c, err)
+			return
+		}
+	}
+	// Grouping applies within the page: variants of a product are listed
+	// under it instead of among the items.
+	var products []*domain.Product
+	if c.Query("group") == "product" {
+		if items, products, err = h.itemsUsecase.GroupByProduct(c.Request.Context(), items); err != nil {
+			h.writeError(c, err)
+			return
+		}
+	}
 	resp := dto.ItemsResponse{
-		Items: make([]*dto.ItemResponse, len(items)),
-		Total: total,
+		Items:  make([]*dto.ItemResponse, len(items)),
+		Total:  total,
+		Totals: dto.ToStockTotalsResponse(totals),
 	}
 	for i, item := range items {
 		resp.Items[i] = dto.ToItemResponse(item)
 	}
+	for _, p := range products {
+		resp.Products = append(resp.Products, dto.ToProductGroupResponse(p))
+	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// itemFilter reads the search and filters of an item listing from the query.
+func itemFilter(c *gin.Context) domain.ItemFilter {
+	filter := domain.ItemFilter{Search: c.Query("search")}
+	if warehouseID := c.Query("warehouse_id"); warehouseID != "" {
+		if id, err := strconv.ParseInt(warehouseID, 10, 64); err == nil && id > 0 {
+			filter.WarehouseID = &id
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		s := domain.StockStatus(status)
+		filter.Status = &s
+	}
+	if productID := c.Query("product_id"); productID != "" {
+		if id, err := strconv.ParseInt(productID, 10, 64); err == nil && id > 0 {
+			filter.ProductID = &id
+		}
+	}
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		if id, err := strconv.ParseInt(categoryID, 10, 64); err == nil && id > 0 {
+			filter.CategoryID = &id
+		}
+	}
+	// attr[voltage]=220 keeps the items whose custom attribute has the value.
+	for name, value := range c.QueryMap("attr") {
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[strings.ToLower(strings.TrimSpace(name))] = value
+	}
+	return filter
+}
+
+// ExportItems streams every item matching the filters of GetItems as a CSV,
+// JSON Lines or XLSX file.
+func (h *ItemsHandler) ExportItems(c *gin.Context) {
+	format := domain.ExportFormat(c.DefaultQuery("format", string(domain.ExportCSV)))
+	if !format.IsValid() {
+		h.writeError(c, fmt.Errorf("%w: unknown export format %q", customErr.ErrInvalidInput, format))
+		return
+	}
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=items_export.%s", format))
+
+	w, err := export.NewWriter(c.Writer, format, domain.ItemExportHeader)
+	if err != nil {
+		h.abortExport(c, err)
+		return
+	}
+	err = h.itemsUsecase.ExportItems(c.Request.Context(), itemFilter(c), func(item *domain.Item) error {
+		return w.WriteRow(domain.ItemExportRow(item))
+	})
+	if err != nil {
+		w.Abort()
+		h.abortExport(c, err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		h.abortExport(c, err)
+	}
+}
+
+func (h *ItemsHandler) abortExport(c *gin.Context, err error) {
+	h.logger.Error().Err(err).Msg("ExportItems failed")
+	export.AbortResponse(c.Writer)
+	h.writeError(c, err)
+}
+
 func (h *ItemsHandler) GetItemByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -93,6 +182,12 @@ func (h *ItemsHandler) GetItemByID(c *gin.Context) {
 		h.writeError(c, err)
 		return
 	}
+	if currency := c.Query("currency"); currency != "" {
+		if err := h.itemsUsecase.ConvertPrices(c.Request.Context(), []*domain.Item{item}, currency); err != nil {
+			h.writeError(c, err)
+			return
+		}
+	}
 	c.JSON(http.StatusOK, dto.ToItemResponse(item))
 }
 
@@ -113,36 +208,166 @@ func (h *ItemsHandler) UpdateItem(c *gin.Context) {
 		h.writeError(c, customErr.ErrInvalidInput)
 		return
 	}
-	item, err := h.itemsUsecase.GetItemByID(c.Request.Context(), id)
+	err = h.itemsUsecase.UpdateItem(c.Request.Context(), id, itemPatch(&req), claims.Username)
 	if err != nil {
 		h.writeError(c, err)
 		return
 	}
-	if req.Name != "" {
-		item.Name = req.Name
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Item updated")
+}
+
+// BulkCreateItems creates a batch of items, all or nothing in atomic mode
+// (the default) or each on its own in best_effort mode.
+func (h *ItemsHandler) BulkCreateItems(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
 	}
-	if req.SKU != "" {
-		item.SKU = req.SKU
+	var req dto.BulkCreateItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
 	}
-	if req.Quantity != nil {
-		item.Quantity = *req.Quantity
+	items := make([]*domain.Item, len(req.Items))
+	for i := range req.Items {
+		items[i] = newItem(&req.Items[i])
 	}
-	if req.Price != nil {
-		item.Price = *req.Price
+	mode := bulkMode(req.Mode)
+	results, err := h.itemsUsecase.CreateItems(c.Request.Context(), items, mode, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("BulkCreateItems failed")
+		h.writeError(c, err)
+		return
 	}
-	if req.Category != "" {
-		item.Category = req.Category
+	h.writeBulkResults(c, http.StatusCreated, mode, results)
+	h.logger.Info().Int("count", len(items)).Str("user", claims.Username).Msg("Items bulk created")
+}
+
+// BulkUpdateItems applies partial updates to a batch of items, in the modes
+// of BulkCreateItems.
+func (h *ItemsHandler) BulkUpdateItems(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
 	}
-	if req.Location != "" {
-		item.Location = req.Location
+	var req dto.BulkUpdateItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	updates := make([]*domain.ItemUpdate, len(req.Items))
+	for i := range req.Items {
+		updates[i] = &domain.ItemUpdate{ID: req.Items[i].ID, Patch: itemPatch(&req.Items[i].UpdateItemRequest)}
 	}
-	err = h.itemsUsecase.UpdateItem(c.Request.Context(), id, item, claims.Username)
+	mode := bulkMode(req.Mode)
+	results, err := h.itemsUsecase.UpdateItems(c.Request.Context(), updates, mode, claims.Username)
 	if err != nil {
+		h.logger.Error().Err(err).Msg("BulkUpdateItems failed")
 		h.writeError(c, err)
 		return
 	}
-	c.Status(http.StatusOK)
-	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Item updated")
+	h.writeBulkResults(c, http.StatusOK, mode, results)
+	h.logger.Info().Int("count", len(updates)).Str("user", claims.Username).Msg("Items bulk updated")
+}
+
+// writeBulkResults answers with the given status when every item succeeded.
+// Otherwise an atomic batch, of which nothing was written, is answered with
+// 422 and a best-effort one with 207.
+func (h *ItemsHandler) writeBulkResults(c *gin.Context, code int, mode domain.BulkMode, results []*domain.BulkItemResult) {
+	resp := dto.ToBulkItemsResponse(results)
+	if resp.Failed > 0 {
+		code = http.StatusMultiStatus
+		if mode == domain.BulkAtomic {
+			code = http.StatusUnprocessableEntity
+		}
+	}
+	c.JSON(code, resp)
+}
+
+func bulkMode(mode string) domain.BulkMode {
+	if mode == "" {
+		return domain.BulkAtomic
+	}
+	return domain.BulkMode(mode)
+}
+
+func newItem(req *dto.CreateItemRequest) *domain.Item {
+	item := &domain.Item{
+		Name:            req.Name,
+		SKU:             req.SKU,
+		Quantity:        req.Quantity,
+		Price:           domain.Money{Amount: req.Price, Currency: req.Currency},
+		Category:        req.Category,
+		Location:        req.Location,
+		Serialized:      req.Serialized,
+		ReorderLevel:    req.ReorderLevel,
+		ReorderQuantity: req.ReorderQuantity,
+		UnitCost:        req.UnitCost,
+		BaseUnit:        req.BaseUnit,
+		Attributes:      req.Attributes,
+	}
+	if req.CostMethod != "" {
+		method := domain.CostMethod(req.CostMethod)
+		item.CostMethod = &method
+	}
+	return item
+}
+
+func itemPatch(req *dto.UpdateItemRequest) *domain.ItemPatch {
+	return &domain.ItemPatch{
+		Name:            req.Name,
+		SKU:             req.SKU,
+		Quantity:        req.Quantity,
+		Price:           req.Price,
+		Currency:        req.Currency,
+		Category:        req.Category,
+		Location:        req.Location,
+		Serialized:      req.Serialized,
+		BaseUnit:        req.BaseUnit,
+		ReorderLevel:    req.ReorderLevel,
+		ReorderQuantity: req.ReorderQuantity,
+		CostMethod:      req.CostMethod,
+		Attributes:      req.Attributes,
+	}
+}
+
+func (h *ItemsHandler) ChangeStockStatus(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.ChangeStockStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	change := &domain.StockStatusChange{
+		ItemID:   id,
+		From:     domain.StockStatus(req.From),
+		To:       domain.StockStatus(req.To),
+		Quantity: req.Quantity,
+	}
+	if err := h.itemsUsecase.ChangeStockStatus(c.Request.Context(), change, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	item, err := h.itemsUsecase.GetItemByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToItemResponse(item))
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Stock status changed")
 }
 
 func (h *ItemsHandler) DeleteItem(c *gin.Context) {
@@ -195,6 +420,10 @@ func (h *ItemsHandler) writeError(c *gin.Context, err error) {
 		code = http.StatusBadRequest
 	case errors.Is(err, customErr.ErrItemNotFound):
 		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrItemExists):
+		code = http.StatusConflict
 	case errors.Is(err, customErr.ErrForbidden):
 		code = http.StatusForbidden
 	case errors.Is(err, customErr.ErrDatabase):
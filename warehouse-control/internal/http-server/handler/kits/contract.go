@@ -0,0 +1,14 @@
+package kits_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type kitsUsecase interface {
+	GetKit(ctx context.Context, itemID int64) (*domain.Kit, error)
+	SetKit(ctx context.Context, kit *domain.Kit, username string) error
+	DeleteKit(ctx context.Context, itemID int64, username string) error
+	AssembleKit(ctx context.Context, a *domain.KitAssembly, username string) error
+}
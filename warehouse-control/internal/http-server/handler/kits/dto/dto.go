@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type KitComponentRequest struct {
+	SKU      string `json:"sku" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required"`
+}
+
+type SetKitRequest struct {
+	Components []*KitComponentRequest `json:"components" binding:"required"`
+}
+
+type AssembleRequest struct {
+	Quantity   int    `json:"quantity" binding:"required"`
+	LocationID *int64 `json:"location_id,omitempty"`
+	Reference  string `json:"reference"`
+}
+
+type KitComponentResponse struct {
+	ItemID    int64  `json:"item_id"`
+	SKU       string `json:"sku"`
+	ItemName  string `json:"item_name"`
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"`
+}
+
+type KitResponse struct {
+	ItemID     int64                   `json:"item_id"`
+	Available  int                     `json:"available"`
+	Components []*KitComponentResponse `json:"components"`
+}
+
+type KitMovementResponse struct {
+	ID        int64           `json:"id"`
+	ItemID    int64           `json:"item_id"`
+	Type      string          `json:"type"`
+	Quantity  int             `json:"quantity"`
+	UnitCost  decimal.Decimal `json:"unit_cost"`
+	TotalCost decimal.Decimal `json:"total_cost"`
+}
+
+type AssemblyResponse struct {
+	KitID       int64                  `json:"kit_id"`
+	Quantity    int                    `json:"quantity"`
+	Disassemble bool                   `json:"disassemble"`
+	Movements   []*KitMovementResponse `json:"movements"`
+}
+
+func ToKitResponse(kit *domain.Kit) *KitResponse {
+	resp := &KitResponse{
+		ItemID:     kit.ItemID,
+		Available:  kit.Available(),
+		Components: make([]*KitComponentResponse, len(kit.Components)),
+	}
+	for i, c := range kit.Components {
+		resp.Components[i] = &KitComponentResponse{
+			ItemID:    c.ItemID,
+			SKU:       c.SKU,
+			ItemName:  c.ItemName,
+			Quantity:  c.Quantity,
+			Available: c.Available,
+		}
+	}
+	return resp
+}
+
+func ToAssemblyResponse(a *domain.KitAssembly) *AssemblyResponse {
+	resp := &AssemblyResponse{
+		KitID:       a.KitID,
+		Quantity:    a.Quantity,
+		Disassemble: a.Disassemble,
+		Movements:   make([]*KitMovementResponse, len(a.Movements)),
+	}
+	for i, m := range a.Movements {
+		mr := &KitMovementResponse{
+			ID:        m.ID,
+			ItemID:    m.ItemID,
+			Type:      string(m.Type),
+			Quantity:  m.Quantity,
+			TotalCost: m.TotalCost,
+		}
+		if m.UnitCost != nil {
+			mr.UnitCost = *m.UnitCost
+		}
+		resp.Movements[i] = mr
+	}
+	return resp
+}
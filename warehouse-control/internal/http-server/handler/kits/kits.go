@@ -0,0 +1,160 @@
+package kits_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/kits/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type KitsHandler struct {
+	kitsUsecase kitsUsecase
+	logger      *zlog.Zerolog
+}
+
+func NewHandler(kitsUsecase kitsUsecase, logger *zlog.Zerolog) *KitsHandler {
+	return &KitsHandler{
+		kitsUsecase: kitsUsecase,
+		logger:      logger,
+	}
+}
+
+func (h *KitsHandler) GetKit(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	kit, err := h.kitsUsecase.GetKit(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToKitResponse(kit))
+}
+
+func (h *KitsHandler) SetKit(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.SetKitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	kit := &domain.Kit{ItemID: id}
+	for _, comp := range req.Components {
+		if comp == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		kit.Components = append(kit.Components, &domain.KitComponent{SKU: comp.SKU, Quantity: comp.Quantity})
+	}
+	if err := h.kitsUsecase.SetKit(c.Request.Context(), kit, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	kit, err = h.kitsUsecase.GetKit(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToKitResponse(kit))
+	h.logger.Info().Int64("item_id", id).Str("user", claims.Username).Msg("Kit set")
+}
+
+func (h *KitsHandler) DeleteKit(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.kitsUsecase.DeleteKit(c.Request.Context(), id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("item_id", id).Str("user", claims.Username).Msg("Kit deleted")
+}
+
+func (h *KitsHandler) Assemble(c *gin.Context) {
+	h.assemble(c, false)
+}
+
+func (h *KitsHandler) Disassemble(c *gin.Context) {
+	h.assemble(c, true)
+}
+
+func (h *KitsHandler) assemble(c *gin.Context, disassemble bool) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.AssembleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	a := &domain.KitAssembly{
+		KitID:       id,
+		Quantity:    req.Quantity,
+		Disassemble: disassemble,
+		LocationID:  req.LocationID,
+		Reference:   req.Reference,
+	}
+	if err := h.kitsUsecase.AssembleKit(c.Request.Context(), a, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("kit_id", id).Msg("AssembleKit failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToAssemblyResponse(a))
+	h.logger.Info().Int64("kit_id", id).Int("quantity", a.Quantity).Bool("disassemble", disassemble).
+		Str("user", claims.Username).Msg("Kit assembled")
+}
+
+func (h *KitsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrKitNotFound), errors.Is(err, customErr.ErrItemNotFound),
+		errors.Is(err, customErr.ErrLocationNotFound), errors.Is(err, customErr.ErrLotNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock), errors.Is(err, customErr.ErrSerialNotFound):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
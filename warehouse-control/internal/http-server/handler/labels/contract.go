@@ -0,0 +1,12 @@
+package labels_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type labelsUsecase interface {
+	ItemLabel(ctx context.Context, id int64, symbology domain.Symbology, format domain.LabelFormat, scale int) ([]byte, error)
+	LabelSheet(ctx context.Context, req domain.LabelSheetRequest) ([]byte, error)
+}
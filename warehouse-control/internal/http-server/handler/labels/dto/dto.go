@@ -0,0 +1,7 @@
+package dto
+
+type LabelSheetRequest struct {
+	ItemIDs     []int64 `json:"item_ids"`
+	LocationIDs []int64 `json:"location_ids"`
+	Symbology   string  `json:"symbology"`
+}
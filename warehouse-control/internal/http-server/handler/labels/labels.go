@@ -0,0 +1,91 @@
+package labels_handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/labels/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type LabelsHandler struct {
+	labelsUsecase labelsUsecase
+	logger        *zlog.Zerolog
+}
+
+func NewHandler(labelsUsecase labelsUsecase, logger *zlog.Zerolog) *LabelsHandler {
+	return &LabelsHandler{
+		labelsUsecase: labelsUsecase,
+		logger:        logger,
+	}
+}
+
+// GetItemLabel renders the label of an item; a Code 128 PNG unless the
+// symbology and format query parameters ask for another.
+func (h *LabelsHandler) GetItemLabel(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	symbology := domain.Symbology(c.DefaultQuery("symbology", string(domain.SymbologyCode128)))
+	format := domain.LabelFormat(c.DefaultQuery("format", string(domain.LabelPNG)))
+	scale, err := strconv.Atoi(c.DefaultQuery("scale", "2"))
+	if err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	data, err := h.labelsUsecase.ItemLabel(c.Request.Context(), id, symbology, format, scale)
+	if err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("ItemLabel failed")
+		h.writeError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=item_%d.%s", id, format))
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+// GetLabelSheet renders a printable PDF with the labels of the selected
+// items and bins.
+func (h *LabelsHandler) GetLabelSheet(c *gin.Context) {
+	var req dto.LabelSheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	data, err := h.labelsUsecase.LabelSheet(c.Request.Context(), domain.LabelSheetRequest{
+		ItemIDs:     req.ItemIDs,
+		LocationIDs: req.LocationIDs,
+		Symbology:   domain.Symbology(req.Symbology),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("LabelSheet failed")
+		h.writeError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=labels.pdf")
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+func (h *LabelsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrLocationNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
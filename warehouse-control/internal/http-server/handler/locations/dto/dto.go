@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type CreateLocationRequest struct {
+	Type     string `json:"type" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+}
+
+type UpdateLocationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type LocationResponse struct {
+	ID          int64     `json:"id"`
+	ParentID    *int64    `json:"parent_id,omitempty"`
+	WarehouseID int64     `json:"warehouse_id"`
+	Type        string    `json:"type"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type LocationsResponse struct {
+	Locations []*LocationResponse `json:"locations"`
+	Total     int                 `json:"total"`
+}
+
+func ToLocationResponse(loc *domain.Location) *LocationResponse {
+	return &LocationResponse{
+		ID:          loc.ID,
+		ParentID:    loc.ParentID,
+		WarehouseID: loc.WarehouseID,
+		Type:        string(loc.Type),
+		Code:        loc.Code,
+		Name:        loc.Name,
+		Path:        loc.Path,
+		CreatedAt:   loc.CreatedAt,
+		UpdatedAt:   loc.UpdatedAt,
+	}
+}
@@ -0,0 +1,180 @@
+package locations_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/locations/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type LocationsHandler struct {
+	locationsUsecase locationsUsecase
+	logger           *zlog.Zerolog
+}
+
+func NewHandler(locationsUsecase locationsUsecase, logger *zlog.Zerolog) *LocationsHandler {
+	return &LocationsHandler{
+		locationsUsecase: locationsUsecase,
+		logger:           logger,
+	}
+}
+
+func (h *LocationsHandler) CreateLocation(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	loc := &domain.Location{
+		ParentID: req.ParentID,
+		Type:     domain.LocationType(req.Type),
+		Code:     req.Code,
+		Name:     req.Name,
+	}
+	id, err := h.locationsUsecase.CreateLocation(c.Request.Context(), loc)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateLocation failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id, "path": loc.Path})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Location created")
+}
+
+func (h *LocationsHandler) GetLocations(c *gin.Context) {
+	filter := domain.LocationFilter{
+		Limit: 100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if t := c.Query("type"); t != "" {
+		lt := domain.LocationType(t)
+		filter.Type = &lt
+	}
+	if parentID := c.Query("parent_id"); parentID != "" {
+		if id, err := strconv.ParseInt(parentID, 10, 64); err == nil && id > 0 {
+			filter.ParentID = &id
+		}
+	}
+	if warehouseID := c.Query("warehouse_id"); warehouseID != "" {
+		if id, err := strconv.ParseInt(warehouseID, 10, 64); err == nil && id > 0 {
+			filter.WarehouseID = &id
+		}
+	}
+	locations, total, err := h.locationsUsecase.GetLocations(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetLocations failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.LocationsResponse{
+		Locations: make([]*dto.LocationResponse, len(locations)),
+		Total:     total,
+	}
+	for i, loc := range locations {
+		resp.Locations[i] = dto.ToLocationResponse(loc)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *LocationsHandler) GetLocationByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	loc, err := h.locationsUsecase.GetLocationByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToLocationResponse(loc))
+}
+
+func (h *LocationsHandler) UpdateLocation(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.locationsUsecase.UpdateLocation(c.Request.Context(), id, req.Name); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Location updated")
+}
+
+func (h *LocationsHandler) DeleteLocation(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.locationsUsecase.DeleteLocation(c.Request.Context(), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Location deleted")
+}
+
+func (h *LocationsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrLocationExists), errors.Is(err, customErr.ErrLocationInUse):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
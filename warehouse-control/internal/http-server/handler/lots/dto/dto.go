@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type UpdateLotRequest struct {
+	ManufacturedAt string `json:"manufactured_at"`
+	ExpiresAt      string `json:"expires_at"`
+}
+
+type LotResponse struct {
+	ID             int64     `json:"id"`
+	ItemID         int64     `json:"item_id"`
+	LotNumber      string    `json:"lot_number"`
+	ManufacturedAt string    `json:"manufactured_at,omitempty"`
+	ExpiresAt      string    `json:"expires_at,omitempty"`
+	Expired        bool      `json:"expired"`
+	Quantity       int       `json:"quantity"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type LotsResponse struct {
+	Lots  []*LotResponse `json:"lots"`
+	Total int            `json:"total"`
+}
+
+type LotHistoryRecordResponse struct {
+	ID        int64        `json:"id"`
+	LotID     int64        `json:"lot_id"`
+	ItemID    int64        `json:"item_id"`
+	Action    string       `json:"action"`
+	OldData   *LotResponse `json:"old_data,omitempty"`
+	NewData   *LotResponse `json:"new_data,omitempty"`
+	ChangedBy string       `json:"changed_by"`
+	ChangedAt time.Time    `json:"changed_at"`
+}
+
+type LotHistoryResponse struct {
+	Records []*LotHistoryRecordResponse `json:"history"`
+}
+
+func ToLotResponse(lot *domain.Lot) *LotResponse {
+	resp := &LotResponse{
+		ID:        lot.ID,
+		ItemID:    lot.ItemID,
+		LotNumber: lot.LotNumber,
+		Expired:   lot.IsExpired(time.Now()),
+		Quantity:  lot.Quantity,
+		CreatedAt: lot.CreatedAt,
+		UpdatedAt: lot.UpdatedAt,
+	}
+	if lot.ManufacturedAt != nil {
+		resp.ManufacturedAt = lot.ManufacturedAt.Format(time.DateOnly)
+	}
+	if lot.ExpiresAt != nil {
+		resp.ExpiresAt = lot.ExpiresAt.Format(time.DateOnly)
+	}
+	return resp
+}
+
+func ToLotHistoryRecordResponse(rec *domain.LotHistoryRecord) *LotHistoryRecordResponse {
+	resp := &LotHistoryRecordResponse{
+		ID:        rec.ID,
+		LotID:     rec.LotID,
+		ItemID:    rec.ItemID,
+		Action:    rec.Action,
+		ChangedBy: rec.ChangedBy,
+		ChangedAt: rec.ChangedAt,
+	}
+	if rec.OldData != nil {
+		resp.OldData = ToLotResponse(rec.OldData)
+	}
+	if rec.NewData != nil {
+		resp.NewData = ToLotResponse(rec.NewData)
+	}
+	return resp
+}
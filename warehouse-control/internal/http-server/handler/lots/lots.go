@@ -0,0 +1,189 @@
+package lots_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/lots/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type LotsHandler struct {
+	lotsUsecase lotsUsecase
+	logger      *zlog.Zerolog
+}
+
+func NewHandler(lotsUsecase lotsUsecase, logger *zlog.Zerolog) *LotsHandler {
+	return &LotsHandler{
+		lotsUsecase: lotsUsecase,
+		logger:      logger,
+	}
+}
+
+func (h *LotsHandler) GetItemLots(c *gin.Context) {
+	idStr := c.Param("id")
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	filter := h.parseFilter(c)
+	filter.ItemID = &itemID
+	h.writeLots(c, filter)
+}
+
+func (h *LotsHandler) GetExpiringLots(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	filter := h.parseFilter(c)
+	filter.ExpiringWithinDays = &days
+	filter.IncludeEmpty = false
+	h.writeLots(c, filter)
+}
+
+func (h *LotsHandler) GetLotByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	lot, err := h.lotsUsecase.GetLotByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToLotResponse(lot))
+}
+
+func (h *LotsHandler) UpdateLot(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.UpdateLotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	manufacturedAt, err := parseDate(req.ManufacturedAt)
+	if err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	expiresAt, err := parseDate(req.ExpiresAt)
+	if err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.lotsUsecase.UpdateLotDates(c.Request.Context(), id, manufacturedAt, expiresAt, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Lot updated")
+}
+
+func (h *LotsHandler) GetLotHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	records, err := h.lotsUsecase.GetLotHistory(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetLotHistory failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.LotHistoryResponse{
+		Records: make([]*dto.LotHistoryRecordResponse, len(records)),
+	}
+	for i, rec := range records {
+		resp.Records[i] = dto.ToLotHistoryRecordResponse(rec)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *LotsHandler) parseFilter(c *gin.Context) domain.LotFilter {
+	filter := domain.LotFilter{
+		Limit:        100,
+		IncludeEmpty: c.Query("include_empty") == "true",
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	return filter
+}
+
+func (h *LotsHandler) writeLots(c *gin.Context, filter domain.LotFilter) {
+	lots, total, err := h.lotsUsecase.GetLots(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetLots failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.LotsResponse{
+		Lots:  make([]*dto.LotResponse, len(lots)),
+		Total: total,
+	}
+	for i, lot := range lots {
+		resp.Lots[i] = dto.ToLotResponse(lot)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *LotsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrLotNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
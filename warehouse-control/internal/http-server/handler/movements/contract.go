@@ -0,0 +1,12 @@
+package movements_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type movementsUsecase interface {
+	CreateMovement(ctx context.Context, m *domain.StockMovement, username string) (int64, error)
+	GetMovements(ctx context.Context, filter domain.MovementFilter) ([]*domain.StockMovement, int, error)
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type CreateMovementRequest struct {
+	Type           string           `json:"type" binding:"required"`
+	Quantity       decimal.Decimal  `json:"quantity"`
+	Unit           string           `json:"unit,omitempty"`
+	ReasonCode     string           `json:"reason_code"`
+	Reference      string           `json:"reference"`
+	FromLocationID *int64           `json:"from_location_id,omitempty"`
+	ToLocationID   *int64           `json:"to_location_id,omitempty"`
+	ReservationID  *int64           `json:"reservation_id,omitempty"`
+	FromLocation   string           `json:"from_location"`
+	ToLocation     string           `json:"to_location"`
+	LotID          *int64           `json:"lot_id,omitempty"`
+	LotNumber      string           `json:"lot_number,omitempty"`
+	ManufacturedAt string           `json:"manufactured_at,omitempty"`
+	ExpiresAt      string           `json:"expires_at,omitempty"`
+	Serials        []string         `json:"serials,omitempty"`
+	StockStatus    string           `json:"stock_status,omitempty"`
+	UnitCost       *decimal.Decimal `json:"unit_cost,omitempty"`
+}
+
+type LotAllocationResponse struct {
+	LotID     int64  `json:"lot_id"`
+	LotNumber string `json:"lot_number"`
+	Quantity  int    `json:"quantity"`
+}
+
+type MovementResponse struct {
+	ID             int64                    `json:"id"`
+	ItemID         int64                    `json:"item_id"`
+	Type           string                   `json:"type"`
+	Quantity       int                      `json:"quantity"`
+	ReasonCode     string                   `json:"reason_code"`
+	Reference      string                   `json:"reference"`
+	FromLocationID *int64                   `json:"from_location_id,omitempty"`
+	ToLocationID   *int64                   `json:"to_location_id,omitempty"`
+	ReservationID  *int64                   `json:"reservation_id,omitempty"`
+	FromLocation   string                   `json:"from_location,omitempty"`
+	ToLocation     string                   `json:"to_location,omitempty"`
+	Lots           []*LotAllocationResponse `json:"lots,omitempty"`
+	Serials        []string                 `json:"serials,omitempty"`
+	StockStatus    string                   `json:"stock_status"`
+	UnitCost       *decimal.Decimal         `json:"unit_cost"`
+	TotalCost      decimal.Decimal          `json:"total_cost"`
+	CreatedBy      string                   `json:"created_by"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
+
+type MovementsResponse struct {
+	Movements []*MovementResponse `json:"movements"`
+	Total     int                 `json:"total"`
+}
+
+func ToMovementResponse(m *domain.StockMovement) *MovementResponse {
+	resp := &MovementResponse{
+		ID:             m.ID,
+		ItemID:         m.ItemID,
+		Type:           string(m.Type),
+		Quantity:       m.Quantity,
+		ReasonCode:     m.ReasonCode,
+		Reference:      m.Reference,
+		FromLocationID: m.FromLocationID,
+		ToLocationID:   m.ToLocationID,
+		ReservationID:  m.ReservationID,
+		FromLocation:   m.FromLocation,
+		ToLocation:     m.ToLocation,
+		Serials:        m.Serials,
+		StockStatus:    string(m.StockStatus),
+		UnitCost:       m.UnitCost,
+		TotalCost:      m.TotalCost,
+		CreatedBy:      m.CreatedBy,
+		CreatedAt:      m.CreatedAt,
+	}
+	for _, a := range m.Lots {
+		resp.Lots = append(resp.Lots, &LotAllocationResponse{
+			LotID:     a.LotID,
+			LotNumber: a.LotNumber,
+			Quantity:  a.Quantity,
+		})
+	}
+	return resp
+}
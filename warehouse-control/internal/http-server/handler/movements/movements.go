@@ -0,0 +1,157 @@
+package movements_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/movements/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type MovementsHandler struct {
+	movementsUsecase movementsUsecase
+	logger           *zlog.Zerolog
+}
+
+func NewHandler(movementsUsecase movementsUsecase, logger *zlog.Zerolog) *MovementsHandler {
+	return &MovementsHandler{
+		movementsUsecase: movementsUsecase,
+		logger:           logger,
+	}
+}
+
+func (h *MovementsHandler) CreateMovement(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.CreateMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	m := &domain.StockMovement{
+		ItemID:         itemID,
+		Type:           domain.MovementType(req.Type),
+		Requested:      &domain.UnitQuantity{Quantity: req.Quantity, Unit: req.Unit},
+		ReasonCode:     req.ReasonCode,
+		Reference:      req.Reference,
+		FromLocationID: req.FromLocationID,
+		ToLocationID:   req.ToLocationID,
+		ReservationID:  req.ReservationID,
+		FromLocation:   req.FromLocation,
+		ToLocation:     req.ToLocation,
+		LotID:          req.LotID,
+		LotNumber:      req.LotNumber,
+		Serials:        req.Serials,
+		StockStatus:    domain.StockStatus(req.StockStatus),
+		UnitCost:       req.UnitCost,
+	}
+	if m.ManufacturedAt, err = parseDate(req.ManufacturedAt); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if m.ExpiresAt, err = parseDate(req.ExpiresAt); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	id, err := h.movementsUsecase.CreateMovement(c.Request.Context(), m, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateMovement failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToMovementResponse(m))
+	h.logger.Info().Int64("id", id).Int64("item_id", itemID).Str("user", claims.Username).Msg("Stock movement created")
+}
+
+func (h *MovementsHandler) GetMovements(c *gin.Context) {
+	idStr := c.Param("id")
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	filter := domain.MovementFilter{
+		ItemID: itemID,
+		Limit:  100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if t := c.Query("type"); t != "" {
+		mt := domain.MovementType(t)
+		filter.Type = &mt
+	}
+	movements, total, err := h.movementsUsecase.GetMovements(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetMovements failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.MovementsResponse{
+		Movements: make([]*dto.MovementResponse, len(movements)),
+		Total:     total,
+	}
+	for i, m := range movements {
+		resp.Movements[i] = dto.ToMovementResponse(m)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *MovementsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrLocationNotFound),
+		errors.Is(err, customErr.ErrReservationNotFound), errors.Is(err, customErr.ErrLotNotFound),
+		errors.Is(err, customErr.ErrSerialNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock), errors.Is(err, customErr.ErrReservationClosed),
+		errors.Is(err, customErr.ErrSerialConflict):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
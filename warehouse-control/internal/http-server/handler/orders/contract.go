@@ -0,0 +1,19 @@
+package orders_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type ordersUsecase interface {
+	CreateOrder(ctx context.Context, o *domain.Order, username string) (int64, error)
+	GetOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, int, error)
+	GetOrderByID(ctx context.Context, id int64) (*domain.Order, error)
+	GetOrderHistory(ctx context.Context, id int64) ([]*domain.OrderHistoryRecord, error)
+	GetPickList(ctx context.Context, id int64) (*domain.Order, []*domain.PickStop, error)
+	AllocateOrder(ctx context.Context, id int64, username string) error
+	ConfirmPicks(ctx context.Context, id int64, picks []*domain.OrderPick, complete bool, username string) error
+	ShipOrder(ctx context.Context, id int64, username string) error
+	CancelOrder(ctx context.Context, id int64, note, username string) error
+}
@@ -0,0 +1,181 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type OrderLineRequest struct {
+	SKU      string          `json:"sku" binding:"required"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Unit     string          `json:"unit,omitempty"`
+}
+
+type CreateOrderRequest struct {
+	Number   string              `json:"number" binding:"required"`
+	Customer string              `json:"customer" binding:"required"`
+	Notes    string              `json:"notes"`
+	Lines    []*OrderLineRequest `json:"lines" binding:"required"`
+}
+
+type PickRequest struct {
+	LineID     int64    `json:"line_id"`
+	LocationID *int64   `json:"location_id,omitempty"`
+	Quantity   int      `json:"quantity"`
+	Serials    []string `json:"serials,omitempty"`
+}
+
+type ConfirmPicksRequest struct {
+	Picks    []*PickRequest `json:"picks"`
+	Complete bool           `json:"complete"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+type OrderPickResponse struct {
+	ID           int64     `json:"id"`
+	LocationID   *int64    `json:"location_id,omitempty"`
+	LocationPath string    `json:"location_path,omitempty"`
+	Quantity     int       `json:"quantity"`
+	Serials      []string  `json:"serials,omitempty"`
+	PickedBy     string    `json:"picked_by"`
+	PickedAt     time.Time `json:"picked_at"`
+}
+
+type OrderLineResponse struct {
+	ID             int64                `json:"id"`
+	ItemID         int64                `json:"item_id"`
+	SKU            string               `json:"sku"`
+	ItemName       string               `json:"item_name"`
+	Quantity       int                  `json:"quantity"`
+	PickedQuantity int                  `json:"picked_quantity"`
+	ReservationID  *int64               `json:"reservation_id,omitempty"`
+	Picks          []*OrderPickResponse `json:"picks,omitempty"`
+}
+
+type OrderResponse struct {
+	ID        int64                `json:"id"`
+	Number    string               `json:"number"`
+	Customer  string               `json:"customer"`
+	Status    string               `json:"status"`
+	Notes     string               `json:"notes,omitempty"`
+	CreatedBy string               `json:"created_by"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	ShippedAt *time.Time           `json:"shipped_at,omitempty"`
+	Lines     []*OrderLineResponse `json:"lines,omitempty"`
+}
+
+type OrdersResponse struct {
+	Orders []*OrderResponse `json:"orders"`
+	Total  int              `json:"total"`
+}
+
+type OrderHistoryRecordResponse struct {
+	FromStatus *string   `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note,omitempty"`
+	ChangedBy  string    `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+type PickTaskResponse struct {
+	LineID   int64  `json:"line_id"`
+	ItemID   int64  `json:"item_id"`
+	SKU      string `json:"sku"`
+	ItemName string `json:"item_name"`
+	Quantity int    `json:"quantity"`
+}
+
+type PickStopResponse struct {
+	LocationID   *int64              `json:"location_id,omitempty"`
+	LocationPath string              `json:"location_path,omitempty"`
+	Tasks        []*PickTaskResponse `json:"tasks"`
+}
+
+type PickListResponse struct {
+	OrderID int64               `json:"order_id"`
+	Number  string              `json:"number"`
+	Stops   []*PickStopResponse `json:"stops"`
+}
+
+func ToOrderResponse(o *domain.Order) *OrderResponse {
+	resp := &OrderResponse{
+		ID:        o.ID,
+		Number:    o.Number,
+		Customer:  o.Customer,
+		Status:    string(o.Status),
+		Notes:     o.Notes,
+		CreatedBy: o.CreatedBy,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+		ShippedAt: o.ShippedAt,
+	}
+	for _, l := range o.Lines {
+		line := &OrderLineResponse{
+			ID:             l.ID,
+			ItemID:         l.ItemID,
+			SKU:            l.SKU,
+			ItemName:       l.ItemName,
+			Quantity:       l.Quantity,
+			PickedQuantity: l.PickedQuantity,
+			ReservationID:  l.ReservationID,
+		}
+		for _, p := range l.Picks {
+			line.Picks = append(line.Picks, &OrderPickResponse{
+				ID:           p.ID,
+				LocationID:   p.LocationID,
+				LocationPath: p.LocationPath,
+				Quantity:     p.Quantity,
+				Serials:      p.Serials,
+				PickedBy:     p.PickedBy,
+				PickedAt:     p.PickedAt,
+			})
+		}
+		resp.Lines = append(resp.Lines, line)
+	}
+	return resp
+}
+
+func ToOrderHistoryRecordResponse(rec *domain.OrderHistoryRecord) *OrderHistoryRecordResponse {
+	resp := &OrderHistoryRecordResponse{
+		ToStatus:  string(rec.ToStatus),
+		Note:      rec.Note,
+		ChangedBy: rec.ChangedBy,
+		ChangedAt: rec.ChangedAt,
+	}
+	if rec.FromStatus != nil {
+		from := string(*rec.FromStatus)
+		resp.FromStatus = &from
+	}
+	return resp
+}
+
+func ToPickListResponse(o *domain.Order, stops []*domain.PickStop) *PickListResponse {
+	resp := &PickListResponse{
+		OrderID: o.ID,
+		Number:  o.Number,
+		Stops:   make([]*PickStopResponse, len(stops)),
+	}
+	for i, stop := range stops {
+		s := &PickStopResponse{
+			LocationID:   stop.LocationID,
+			LocationPath: stop.LocationPath,
+			Tasks:        make([]*PickTaskResponse, len(stop.Tasks)),
+		}
+		for j, t := range stop.Tasks {
+			s.Tasks[j] = &PickTaskResponse{
+				LineID:   t.LineID,
+				ItemID:   t.ItemID,
+				SKU:      t.SKU,
+				ItemName: t.ItemName,
+				Quantity: t.Quantity,
+			}
+		}
+		resp.Stops[i] = s
+	}
+	return resp
+}
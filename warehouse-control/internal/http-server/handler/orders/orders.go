@@ -0,0 +1,281 @@
+package orders_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/orders/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type OrdersHandler struct {
+	ordersUsecase ordersUsecase
+	logger        *zlog.Zerolog
+}
+
+func NewHandler(ordersUsecase ordersUsecase, logger *zlog.Zerolog) *OrdersHandler {
+	return &OrdersHandler{
+		ordersUsecase: ordersUsecase,
+		logger:        logger,
+	}
+}
+
+func (h *OrdersHandler) CreateOrder(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	o := &domain.Order{
+		Number:   req.Number,
+		Customer: req.Customer,
+		Notes:    req.Notes,
+	}
+	for _, l := range req.Lines {
+		if l == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		o.Lines = append(o.Lines, &domain.OrderLine{
+			SKU:       l.SKU,
+			Requested: &domain.UnitQuantity{Quantity: l.Quantity, Unit: l.Unit},
+		})
+	}
+	id, err := h.ordersUsecase.CreateOrder(c.Request.Context(), o, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateOrder failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Order created")
+}
+
+func (h *OrdersHandler) GetOrders(c *gin.Context) {
+	filter := domain.OrderFilter{
+		Customer: c.Query("customer"),
+		Limit:    100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		s := domain.OrderStatus(status)
+		filter.Status = &s
+	}
+	orders, total, err := h.ordersUsecase.GetOrders(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetOrders failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.OrdersResponse{
+		Orders: make([]*dto.OrderResponse, len(orders)),
+		Total:  total,
+	}
+	for i, o := range orders {
+		resp.Orders[i] = dto.ToOrderResponse(o)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrdersHandler) GetOrderByID(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	o, err := h.ordersUsecase.GetOrderByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToOrderResponse(o))
+}
+
+func (h *OrdersHandler) GetOrderHistory(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	records, err := h.ordersUsecase.GetOrderHistory(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	resp := make([]*dto.OrderHistoryRecordResponse, len(records))
+	for i, rec := range records {
+		resp[i] = dto.ToOrderHistoryRecordResponse(rec)
+	}
+	c.JSON(http.StatusOK, gin.H{"records": resp})
+}
+
+func (h *OrdersHandler) GetPickList(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	o, stops, err := h.ordersUsecase.GetPickList(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToPickListResponse(o, stops))
+}
+
+func (h *OrdersHandler) AllocateOrder(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	if err := h.ordersUsecase.AllocateOrder(c.Request.Context(), id, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("AllocateOrder failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithOrder(c, id)
+}
+
+func (h *OrdersHandler) ConfirmPicks(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.ConfirmPicksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	picks := make([]*domain.OrderPick, 0, len(req.Picks))
+	for _, p := range req.Picks {
+		if p == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		picks = append(picks, &domain.OrderPick{
+			LineID:     p.LineID,
+			LocationID: p.LocationID,
+			Quantity:   p.Quantity,
+			Serials:    p.Serials,
+		})
+	}
+	if err := h.ordersUsecase.ConfirmPicks(c.Request.Context(), id, picks, req.Complete, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("ConfirmPicks failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithOrder(c, id)
+}
+
+func (h *OrdersHandler) ShipOrder(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	if err := h.ordersUsecase.ShipOrder(c.Request.Context(), id, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("ShipOrder failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithOrder(c, id)
+}
+
+func (h *OrdersHandler) CancelOrder(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.CancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+	}
+	if err := h.ordersUsecase.CancelOrder(c.Request.Context(), id, req.Reason, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("CancelOrder failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithOrder(c, id)
+}
+
+func (h *OrdersHandler) respondWithOrder(c *gin.Context, id int64) {
+	o, err := h.ordersUsecase.GetOrderByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToOrderResponse(o))
+}
+
+func (h *OrdersHandler) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *OrdersHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrOrderNotFound), errors.Is(err, customErr.ErrItemNotFound),
+		errors.Is(err, customErr.ErrLocationNotFound), errors.Is(err, customErr.ErrSerialNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrOrderExists), errors.Is(err, customErr.ErrInvalidTransition),
+		errors.Is(err, customErr.ErrInsufficientStock), errors.Is(err, customErr.ErrSerialConflict):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
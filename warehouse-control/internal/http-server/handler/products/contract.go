@@ -0,0 +1,15 @@
+package products_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type productsUsecase interface {
+	CreateProduct(ctx context.Context, p *domain.Product, username string) (int64, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, int, error)
+	GetProductByID(ctx context.Context, id int64) (*domain.Product, error)
+	UpdateProduct(ctx context.Context, p *domain.Product, username string) error
+	DeleteProduct(ctx context.Context, id int64, username string) error
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type AttributeRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Values []string `json:"values" binding:"required"`
+}
+
+type CreateProductRequest struct {
+	Name       string              `json:"name" binding:"required"`
+	SKU        string              `json:"sku" binding:"required"`
+	Category   string              `json:"category"`
+	Price      decimal.Decimal     `json:"price"`
+	Currency   string              `json:"currency"`
+	Attributes []*AttributeRequest `json:"attributes" binding:"required"`
+}
+
+type UpdateProductRequest struct {
+	Name     string           `json:"name,omitempty"`
+	Category *string          `json:"category,omitempty"`
+	Price    *decimal.Decimal `json:"price,omitempty"`
+	Currency string           `json:"currency,omitempty"`
+}
+
+type AttributeResponse struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type VariantResponse struct {
+	ID        int64             `json:"id"`
+	SKU       string            `json:"sku"`
+	Name      string            `json:"name"`
+	Category  string            `json:"category"`
+	Quantity  int               `json:"quantity"`
+	Price     decimal.Decimal   `json:"price"`
+	Currency  string            `json:"currency"`
+	Variant   map[string]string `json:"variant"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type ProductResponse struct {
+	ID         int64                `json:"id"`
+	Name       string               `json:"name"`
+	SKU        string               `json:"sku"`
+	Category   string               `json:"category"`
+	Price      decimal.Decimal      `json:"price"`
+	Currency   string               `json:"currency"`
+	Attributes []*AttributeResponse `json:"attributes"`
+	Variants   []*VariantResponse   `json:"variants,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+type ProductsResponse struct {
+	Products []*ProductResponse `json:"products"`
+	Total    int                `json:"total"`
+}
+
+func ToProductResponse(p *domain.Product) *ProductResponse {
+	resp := &ProductResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		SKU:        p.SKU,
+		Category:   p.Category,
+		Price:      p.Price.Amount,
+		Currency:   p.Price.Currency,
+		Attributes: make([]*AttributeResponse, len(p.Attributes)),
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}
+	for i, a := range p.Attributes {
+		resp.Attributes[i] = &AttributeResponse{Name: a.Name, Values: a.Values}
+	}
+	for _, v := range p.Variants {
+		resp.Variants = append(resp.Variants, &VariantResponse{
+			ID:        v.ID,
+			SKU:       v.SKU,
+			Name:      v.Name,
+			Category:  v.Category,
+			Quantity:  v.Quantity,
+			Price:     v.Price.Amount,
+			Currency:  v.Price.Currency,
+			Variant:   v.Variant,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,194 @@
+package products_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/products/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ProductsHandler struct {
+	productsUsecase productsUsecase
+	logger          *zlog.Zerolog
+}
+
+func NewHandler(productsUsecase productsUsecase, logger *zlog.Zerolog) *ProductsHandler {
+	return &ProductsHandler{
+		productsUsecase: productsUsecase,
+		logger:          logger,
+	}
+}
+
+func (h *ProductsHandler) CreateProduct(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	p := &domain.Product{
+		Name:     req.Name,
+		SKU:      req.SKU,
+		Category: req.Category,
+		Price:    domain.Money{Amount: req.Price, Currency: req.Currency},
+	}
+	for _, a := range req.Attributes {
+		if a == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		p.Attributes = append(p.Attributes, domain.VariantAttribute{Name: a.Name, Values: a.Values})
+	}
+	id, err := h.productsUsecase.CreateProduct(c.Request.Context(), p, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateProduct failed")
+		h.writeError(c, err)
+		return
+	}
+	p, err = h.productsUsecase.GetProductByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToProductResponse(p))
+	h.logger.Info().Int64("id", id).Int("variants", len(p.Variants)).Str("user", claims.Username).Msg("Product created")
+}
+
+func (h *ProductsHandler) GetProducts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit == 0 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	filter := domain.ProductFilter{
+		Search: c.Query("search"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	products, total, err := h.productsUsecase.GetProducts(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetProducts failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.ProductsResponse{
+		Products: make([]*dto.ProductResponse, len(products)),
+		Total:    total,
+	}
+	for i, p := range products {
+		resp.Products[i] = dto.ToProductResponse(p)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ProductsHandler) GetProductByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	p, err := h.productsUsecase.GetProductByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToProductResponse(p))
+}
+
+func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	p, err := h.productsUsecase.GetProductByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	if req.Name != "" {
+		p.Name = req.Name
+	}
+	if req.Category != nil {
+		p.Category = *req.Category
+	}
+	if req.Price != nil {
+		p.Price.Amount = *req.Price
+	}
+	if req.Currency != "" {
+		p.Price.Currency = req.Currency
+	}
+	if err := h.productsUsecase.UpdateProduct(c.Request.Context(), p, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	p, err = h.productsUsecase.GetProductByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToProductResponse(p))
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Product updated")
+}
+
+func (h *ProductsHandler) DeleteProduct(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.productsUsecase.DeleteProduct(c.Request.Context(), id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Product deleted")
+}
+
+func (h *ProductsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrProductNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrProductExists):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
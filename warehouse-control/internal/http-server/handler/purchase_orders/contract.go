@@ -0,0 +1,15 @@
+package purchase_orders_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type purchaseOrdersUsecase interface {
+	CreatePurchaseOrder(ctx context.Context, po *domain.PurchaseOrder, username string) (int64, error)
+	GetPurchaseOrders(ctx context.Context, filter domain.PurchaseOrderFilter) ([]*domain.PurchaseOrder, int, error)
+	GetPurchaseOrderByID(ctx context.Context, id int64) (*domain.PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, id int64, username string) error
+	ReceivePurchaseOrder(ctx context.Context, id int64, receipts []*domain.PurchaseOrderReceipt, allowOver bool, username string) error
+}
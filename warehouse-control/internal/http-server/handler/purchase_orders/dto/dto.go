@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type PurchaseOrderLineRequest struct {
+	SKU       string          `json:"sku" binding:"required"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Unit      string          `json:"unit,omitempty"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID int64                       `json:"supplier_id" binding:"required"`
+	Number     string                      `json:"number" binding:"required"`
+	ExpectedAt string                      `json:"expected_at"`
+	Notes      string                      `json:"notes"`
+	Lines      []*PurchaseOrderLineRequest `json:"lines" binding:"required"`
+}
+
+type ReceiptRequest struct {
+	LineID         int64           `json:"line_id"`
+	Quantity       decimal.Decimal `json:"quantity"`
+	Unit           string          `json:"unit,omitempty"`
+	LocationID     *int64          `json:"location_id,omitempty"`
+	LotNumber      string          `json:"lot_number,omitempty"`
+	ManufacturedAt string          `json:"manufactured_at,omitempty"`
+	ExpiresAt      string          `json:"expires_at,omitempty"`
+	StockStatus    string          `json:"stock_status,omitempty"`
+}
+
+type ReceivePurchaseOrderRequest struct {
+	Receipts []*ReceiptRequest `json:"receipts" binding:"required"`
+}
+
+type PurchaseOrderLineResponse struct {
+	ID               int64           `json:"id"`
+	ItemID           int64           `json:"item_id"`
+	SKU              string          `json:"sku"`
+	ItemName         string          `json:"item_name"`
+	Quantity         int             `json:"quantity"`
+	ReceivedQuantity int             `json:"received_quantity"`
+	Remaining        int             `json:"remaining"`
+	UnitPrice        decimal.Decimal `json:"unit_price"`
+}
+
+type PurchaseOrderResponse struct {
+	ID           int64                        `json:"id"`
+	SupplierID   int64                        `json:"supplier_id"`
+	SupplierName string                       `json:"supplier_name"`
+	Number       string                       `json:"number"`
+	Status       string                       `json:"status"`
+	ExpectedAt   string                       `json:"expected_at,omitempty"`
+	Notes        string                       `json:"notes,omitempty"`
+	CreatedBy    string                       `json:"created_by"`
+	CreatedAt    time.Time                    `json:"created_at"`
+	UpdatedAt    time.Time                    `json:"updated_at"`
+	ClosedAt     *time.Time                   `json:"closed_at,omitempty"`
+	Lines        []*PurchaseOrderLineResponse `json:"lines,omitempty"`
+}
+
+type PurchaseOrdersResponse struct {
+	PurchaseOrders []*PurchaseOrderResponse `json:"purchase_orders"`
+	Total          int                      `json:"total"`
+}
+
+func ToPurchaseOrderResponse(po *domain.PurchaseOrder) *PurchaseOrderResponse {
+	resp := &PurchaseOrderResponse{
+		ID:           po.ID,
+		SupplierID:   po.SupplierID,
+		SupplierName: po.SupplierName,
+		Number:       po.Number,
+		Status:       string(po.Status),
+		Notes:        po.Notes,
+		CreatedBy:    po.CreatedBy,
+		CreatedAt:    po.CreatedAt,
+		UpdatedAt:    po.UpdatedAt,
+		ClosedAt:     po.ClosedAt,
+	}
+	if po.ExpectedAt != nil {
+		resp.ExpectedAt = po.ExpectedAt.Format(time.DateOnly)
+	}
+	for _, l := range po.Lines {
+		resp.Lines = append(resp.Lines, &PurchaseOrderLineResponse{
+			ID:               l.ID,
+			ItemID:           l.ItemID,
+			SKU:              l.SKU,
+			ItemName:         l.ItemName,
+			Quantity:         l.Quantity,
+			ReceivedQuantity: l.ReceivedQuantity,
+			Remaining:        max(l.Remaining(), 0),
+			UnitPrice:        l.UnitPrice,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,247 @@
+package purchase_orders_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/purchase_orders/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type PurchaseOrdersHandler struct {
+	purchaseOrdersUsecase purchaseOrdersUsecase
+	logger                *zlog.Zerolog
+}
+
+func NewHandler(purchaseOrdersUsecase purchaseOrdersUsecase, logger *zlog.Zerolog) *PurchaseOrdersHandler {
+	return &PurchaseOrdersHandler{
+		purchaseOrdersUsecase: purchaseOrdersUsecase,
+		logger:                logger,
+	}
+}
+
+func (h *PurchaseOrdersHandler) CreatePurchaseOrder(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	po := &domain.PurchaseOrder{
+		SupplierID: req.SupplierID,
+		Number:     req.Number,
+		Notes:      req.Notes,
+	}
+	if req.ExpectedAt != "" {
+		t, err := time.Parse(time.DateOnly, req.ExpectedAt)
+		if err != nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		po.ExpectedAt = &t
+	}
+	for _, l := range req.Lines {
+		if l == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		po.Lines = append(po.Lines, &domain.PurchaseOrderLine{
+			SKU:       l.SKU,
+			Requested: &domain.UnitQuantity{Quantity: l.Quantity, Unit: l.Unit},
+			UnitPrice: l.UnitPrice,
+		})
+	}
+	id, err := h.purchaseOrdersUsecase.CreatePurchaseOrder(c.Request.Context(), po, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreatePurchaseOrder failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Purchase order created")
+}
+
+func (h *PurchaseOrdersHandler) GetPurchaseOrders(c *gin.Context) {
+	filter := domain.PurchaseOrderFilter{
+		Limit: 100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if supplierID := c.Query("supplier_id"); supplierID != "" {
+		if id, err := strconv.ParseInt(supplierID, 10, 64); err == nil && id > 0 {
+			filter.SupplierID = &id
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		s := domain.PurchaseOrderStatus(status)
+		filter.Status = &s
+	}
+	orders, total, err := h.purchaseOrdersUsecase.GetPurchaseOrders(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetPurchaseOrders failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.PurchaseOrdersResponse{
+		PurchaseOrders: make([]*dto.PurchaseOrderResponse, len(orders)),
+		Total:          total,
+	}
+	for i, po := range orders {
+		resp.PurchaseOrders[i] = dto.ToPurchaseOrderResponse(po)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PurchaseOrdersHandler) GetPurchaseOrderByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	po, err := h.purchaseOrdersUsecase.GetPurchaseOrderByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToPurchaseOrderResponse(po))
+}
+
+func (h *PurchaseOrdersHandler) CancelPurchaseOrder(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.purchaseOrdersUsecase.CancelPurchaseOrder(c.Request.Context(), id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// ReceivePurchaseOrder books receipts within the ordered quantities.
+func (h *PurchaseOrdersHandler) ReceivePurchaseOrder(c *gin.Context) {
+	h.receive(c, false)
+}
+
+// ReceivePurchaseOrderOverride books receipts that may exceed the ordered
+// quantities. The route is restricted to admins.
+func (h *PurchaseOrdersHandler) ReceivePurchaseOrderOverride(c *gin.Context) {
+	h.receive(c, true)
+}
+
+func (h *PurchaseOrdersHandler) receive(c *gin.Context, allowOver bool) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.ReceivePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	receipts := make([]*domain.PurchaseOrderReceipt, 0, len(req.Receipts))
+	for _, r := range req.Receipts {
+		if r == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		rc := &domain.PurchaseOrderReceipt{
+			LineID:      r.LineID,
+			Requested:   &domain.UnitQuantity{Quantity: r.Quantity, Unit: r.Unit},
+			LocationID:  r.LocationID,
+			LotNumber:   r.LotNumber,
+			StockStatus: domain.StockStatus(r.StockStatus),
+		}
+		if rc.ManufacturedAt, err = parseDate(r.ManufacturedAt); err != nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		if rc.ExpiresAt, err = parseDate(r.ExpiresAt); err != nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		receipts = append(receipts, rc)
+	}
+	if err := h.purchaseOrdersUsecase.ReceivePurchaseOrder(c.Request.Context(), id, receipts, allowOver, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("ReceivePurchaseOrder failed")
+		h.writeError(c, err)
+		return
+	}
+	po, err := h.purchaseOrdersUsecase.GetPurchaseOrderByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToPurchaseOrderResponse(po))
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Purchase order received")
+}
+
+func (h *PurchaseOrdersHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrPurchaseOrderNotFound), errors.Is(err, customErr.ErrSupplierNotFound),
+		errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrLocationNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrPurchaseOrderExists), errors.Is(err, customErr.ErrPurchaseOrderClosed),
+		errors.Is(err, customErr.ErrOverReceipt):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,13 @@
+package reservations_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type reservationsUsecase interface {
+	CreateReservation(ctx context.Context, res *domain.Reservation, username string) (int64, error)
+	GetReservations(ctx context.Context, filter domain.ReservationFilter) ([]*domain.Reservation, int, error)
+	ReleaseReservation(ctx context.Context, itemID, id int64, username string) error
+}
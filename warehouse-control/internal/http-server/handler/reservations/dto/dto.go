@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type CreateReservationRequest struct {
+	Quantity  int        `json:"quantity" binding:"required"`
+	Reference string     `json:"reference"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type ReservationResponse struct {
+	ID                int64     `json:"id"`
+	ItemID            int64     `json:"item_id"`
+	Quantity          int       `json:"quantity"`
+	FulfilledQuantity int       `json:"fulfilled_quantity"`
+	Reference         string    `json:"reference"`
+	Status            string    `json:"status"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	CreatedBy         string    `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type ReservationsResponse struct {
+	Reservations []*ReservationResponse `json:"reservations"`
+	Total        int                    `json:"total"`
+}
+
+func ToReservationResponse(res *domain.Reservation) *ReservationResponse {
+	return &ReservationResponse{
+		ID:                res.ID,
+		ItemID:            res.ItemID,
+		Quantity:          res.Quantity,
+		FulfilledQuantity: res.FulfilledQuantity,
+		Reference:         res.Reference,
+		Status:            string(res.Status),
+		ExpiresAt:         res.ExpiresAt,
+		CreatedBy:         res.CreatedBy,
+		CreatedAt:         res.CreatedAt,
+		UpdatedAt:         res.UpdatedAt,
+	}
+}
@@ -0,0 +1,149 @@
+package reservations_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/reservations/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ReservationsHandler struct {
+	reservationsUsecase reservationsUsecase
+	logger              *zlog.Zerolog
+}
+
+func NewHandler(reservationsUsecase reservationsUsecase, logger *zlog.Zerolog) *ReservationsHandler {
+	return &ReservationsHandler{
+		reservationsUsecase: reservationsUsecase,
+		logger:              logger,
+	}
+}
+
+func (h *ReservationsHandler) CreateReservation(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.CreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	res := &domain.Reservation{
+		ItemID:    itemID,
+		Quantity:  req.Quantity,
+		Reference: req.Reference,
+	}
+	if req.ExpiresAt != nil {
+		res.ExpiresAt = *req.ExpiresAt
+	}
+	id, err := h.reservationsUsecase.CreateReservation(c.Request.Context(), res, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateReservation failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToReservationResponse(res))
+	h.logger.Info().Int64("id", id).Int64("item_id", itemID).Str("user", claims.Username).Msg("Reservation created")
+}
+
+func (h *ReservationsHandler) GetReservations(c *gin.Context) {
+	idStr := c.Param("id")
+	itemID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	filter := domain.ReservationFilter{
+		ItemID: itemID,
+		Limit:  100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		st := domain.ReservationStatus(status)
+		filter.Status = &st
+	}
+	reservations, total, err := h.reservationsUsecase.GetReservations(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetReservations failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.ReservationsResponse{
+		Reservations: make([]*dto.ReservationResponse, len(reservations)),
+		Total:        total,
+	}
+	for i, res := range reservations {
+		resp.Reservations[i] = dto.ToReservationResponse(res)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReservationsHandler) ReleaseReservation(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || itemID <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("reservation_id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.reservationsUsecase.ReleaseReservation(c.Request.Context(), itemID, id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Reservation released")
+}
+
+func (h *ReservationsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrReservationNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock), errors.Is(err, customErr.ErrReservationClosed):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,16 @@
+package returns_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type returnsUsecase interface {
+	CreateReturn(ctx context.Context, ret *domain.Return, username string) (int64, error)
+	GetReturns(ctx context.Context, filter domain.ReturnFilter) ([]*domain.Return, int, error)
+	GetReturnByID(ctx context.Context, id int64) (*domain.Return, error)
+	ReceiveReturn(ctx context.Context, id int64, receipts []*domain.ReturnReceipt, username string) error
+	DispositionReturn(ctx context.Context, id int64, dispositions []*domain.ReturnDisposition, username string) error
+	CancelReturn(ctx context.Context, id int64, username string) error
+}
@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type ReturnLineRequest struct {
+	SKU      string `json:"sku" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required"`
+}
+
+type CreateReturnRequest struct {
+	Number   string               `json:"number" binding:"required"`
+	OrderID  *int64               `json:"order_id,omitempty"`
+	Customer string               `json:"customer"`
+	Reason   string               `json:"reason"`
+	Lines    []*ReturnLineRequest `json:"lines" binding:"required"`
+}
+
+type ReturnReceiptRequest struct {
+	LineID     int64    `json:"line_id"`
+	Quantity   int      `json:"quantity"`
+	LocationID *int64   `json:"location_id,omitempty"`
+	Serials    []string `json:"serials,omitempty"`
+}
+
+type ReceiveReturnRequest struct {
+	Receipts []*ReturnReceiptRequest `json:"receipts" binding:"required"`
+}
+
+type DispositionRequest struct {
+	LineID      int64    `json:"line_id"`
+	Disposition string   `json:"disposition"`
+	Quantity    int      `json:"quantity"`
+	Serials     []string `json:"serials,omitempty"`
+	SupplierID  *int64   `json:"supplier_id,omitempty"`
+}
+
+type DispositionReturnRequest struct {
+	Dispositions []*DispositionRequest `json:"dispositions" binding:"required"`
+}
+
+type DispositionResponse struct {
+	ID          int64     `json:"id"`
+	Disposition string    `json:"disposition"`
+	Quantity    int       `json:"quantity"`
+	Serials     []string  `json:"serials,omitempty"`
+	SupplierID  *int64    `json:"supplier_id,omitempty"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ReturnLineResponse struct {
+	ID               int64                  `json:"id"`
+	ItemID           int64                  `json:"item_id"`
+	SKU              string                 `json:"sku"`
+	ItemName         string                 `json:"item_name"`
+	Quantity         int                    `json:"quantity"`
+	ReceivedQuantity int                    `json:"received_quantity"`
+	Quarantined      int                    `json:"quarantined"`
+	LocationID       *int64                 `json:"location_id,omitempty"`
+	LocationPath     string                 `json:"location_path,omitempty"`
+	Serials          []string               `json:"serials,omitempty"`
+	Dispositions     []*DispositionResponse `json:"dispositions,omitempty"`
+}
+
+type ReturnResponse struct {
+	ID         int64                 `json:"id"`
+	Number     string                `json:"number"`
+	OrderID    *int64                `json:"order_id,omitempty"`
+	Customer   string                `json:"customer"`
+	Reason     string                `json:"reason,omitempty"`
+	Status     string                `json:"status"`
+	CreatedBy  string                `json:"created_by"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+	ReceivedAt *time.Time            `json:"received_at,omitempty"`
+	ClosedAt   *time.Time            `json:"closed_at,omitempty"`
+	Lines      []*ReturnLineResponse `json:"lines,omitempty"`
+}
+
+type ReturnsResponse struct {
+	Returns []*ReturnResponse `json:"returns"`
+	Total   int               `json:"total"`
+}
+
+func ToReturnResponse(ret *domain.Return) *ReturnResponse {
+	resp := &ReturnResponse{
+		ID:         ret.ID,
+		Number:     ret.Number,
+		OrderID:    ret.OrderID,
+		Customer:   ret.Customer,
+		Reason:     ret.Reason,
+		Status:     string(ret.Status),
+		CreatedBy:  ret.CreatedBy,
+		CreatedAt:  ret.CreatedAt,
+		UpdatedAt:  ret.UpdatedAt,
+		ReceivedAt: ret.ReceivedAt,
+		ClosedAt:   ret.ClosedAt,
+	}
+	for _, l := range ret.Lines {
+		line := &ReturnLineResponse{
+			ID:               l.ID,
+			ItemID:           l.ItemID,
+			SKU:              l.SKU,
+			ItemName:         l.ItemName,
+			Quantity:         l.Quantity,
+			ReceivedQuantity: l.ReceivedQuantity,
+			Quarantined:      l.Quarantined(),
+			LocationID:       l.LocationID,
+			LocationPath:     l.LocationPath,
+			Serials:          l.Serials,
+		}
+		for _, d := range l.Dispositions {
+			line.Dispositions = append(line.Dispositions, &DispositionResponse{
+				ID:          d.ID,
+				Disposition: string(d.Disposition),
+				Quantity:    d.Quantity,
+				Serials:     d.Serials,
+				SupplierID:  d.SupplierID,
+				CreatedBy:   d.CreatedBy,
+				CreatedAt:   d.CreatedAt,
+			})
+		}
+		resp.Lines = append(resp.Lines, line)
+	}
+	return resp
+}
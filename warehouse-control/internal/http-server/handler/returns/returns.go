@@ -0,0 +1,250 @@
+package returns_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/returns/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ReturnsHandler struct {
+	returnsUsecase returnsUsecase
+	logger         *zlog.Zerolog
+}
+
+func NewHandler(returnsUsecase returnsUsecase, logger *zlog.Zerolog) *ReturnsHandler {
+	return &ReturnsHandler{
+		returnsUsecase: returnsUsecase,
+		logger:         logger,
+	}
+}
+
+func (h *ReturnsHandler) CreateReturn(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	ret := &domain.Return{
+		Number:   req.Number,
+		OrderID:  req.OrderID,
+		Customer: req.Customer,
+		Reason:   req.Reason,
+	}
+	for _, l := range req.Lines {
+		if l == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		ret.Lines = append(ret.Lines, &domain.ReturnLine{SKU: l.SKU, Quantity: l.Quantity})
+	}
+	id, err := h.returnsUsecase.CreateReturn(c.Request.Context(), ret, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateReturn failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Return created")
+}
+
+func (h *ReturnsHandler) GetReturns(c *gin.Context) {
+	filter := domain.ReturnFilter{Limit: 100}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		s := domain.ReturnStatus(status)
+		filter.Status = &s
+	}
+	if orderStr := c.Query("order_id"); orderStr != "" {
+		orderID, err := strconv.ParseInt(orderStr, 10, 64)
+		if err != nil || orderID <= 0 {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		filter.OrderID = &orderID
+	}
+	returns, total, err := h.returnsUsecase.GetReturns(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetReturns failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.ReturnsResponse{
+		Returns: make([]*dto.ReturnResponse, len(returns)),
+		Total:   total,
+	}
+	for i, ret := range returns {
+		resp.Returns[i] = dto.ToReturnResponse(ret)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReturnsHandler) GetReturnByID(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	ret, err := h.returnsUsecase.GetReturnByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToReturnResponse(ret))
+}
+
+func (h *ReturnsHandler) ReceiveReturn(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.ReceiveReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	receipts := make([]*domain.ReturnReceipt, 0, len(req.Receipts))
+	for _, rc := range req.Receipts {
+		if rc == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		receipts = append(receipts, &domain.ReturnReceipt{
+			LineID:     rc.LineID,
+			Quantity:   rc.Quantity,
+			LocationID: rc.LocationID,
+			Serials:    rc.Serials,
+		})
+	}
+	if err := h.returnsUsecase.ReceiveReturn(c.Request.Context(), id, receipts, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("ReceiveReturn failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithReturn(c, id)
+}
+
+func (h *ReturnsHandler) DispositionReturn(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.DispositionReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	dispositions := make([]*domain.ReturnDisposition, 0, len(req.Dispositions))
+	for _, d := range req.Dispositions {
+		if d == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		dispositions = append(dispositions, &domain.ReturnDisposition{
+			LineID:      d.LineID,
+			Disposition: domain.Disposition(d.Disposition),
+			Quantity:    d.Quantity,
+			Serials:     d.Serials,
+			SupplierID:  d.SupplierID,
+		})
+	}
+	if err := h.returnsUsecase.DispositionReturn(c.Request.Context(), id, dispositions, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("DispositionReturn failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithReturn(c, id)
+}
+
+func (h *ReturnsHandler) CancelReturn(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	if err := h.returnsUsecase.CancelReturn(c.Request.Context(), id, claims.Username); err != nil {
+		h.logger.Error().Err(err).Int64("id", id).Msg("CancelReturn failed")
+		h.writeError(c, err)
+		return
+	}
+	h.respondWithReturn(c, id)
+}
+
+func (h *ReturnsHandler) respondWithReturn(c *gin.Context, id int64) {
+	ret, err := h.returnsUsecase.GetReturnByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToReturnResponse(ret))
+}
+
+func (h *ReturnsHandler) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *ReturnsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrReturnNotFound), errors.Is(err, customErr.ErrOrderNotFound),
+		errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrLocationNotFound),
+		errors.Is(err, customErr.ErrSupplierNotFound), errors.Is(err, customErr.ErrSerialNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrReturnExists), errors.Is(err, customErr.ErrReturnClosed),
+		errors.Is(err, customErr.ErrInsufficientStock), errors.Is(err, customErr.ErrSerialConflict):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,11 @@
+package serials_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type serialsUsecase interface {
+	GetSerialTrail(ctx context.Context, serialNumber string) (*domain.SerialTrail, error)
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type SerialItemResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	SKU  string `json:"sku"`
+}
+
+type SerialMovementResponse struct {
+	ID             int64     `json:"id"`
+	Type           string    `json:"type"`
+	ReasonCode     string    `json:"reason_code"`
+	Reference      string    `json:"reference"`
+	FromLocationID *int64    `json:"from_location_id,omitempty"`
+	ToLocationID   *int64    `json:"to_location_id,omitempty"`
+	FromLocation   string    `json:"from_location,omitempty"`
+	ToLocation     string    `json:"to_location,omitempty"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SerialResponse struct {
+	SerialNumber string                    `json:"serial_number"`
+	Status       string                    `json:"status"`
+	Item         *SerialItemResponse       `json:"item"`
+	LocationID   *int64                    `json:"location_id,omitempty"`
+	LocationPath string                    `json:"location_path,omitempty"`
+	Movements    []*SerialMovementResponse `json:"movements"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+func ToSerialResponse(trail *domain.SerialTrail) *SerialResponse {
+	resp := &SerialResponse{
+		SerialNumber: trail.Serial.SerialNumber,
+		Status:       string(trail.Serial.Status),
+		Item: &SerialItemResponse{
+			ID:   trail.Item.ID,
+			Name: trail.Item.Name,
+			SKU:  trail.Item.SKU,
+		},
+		LocationID:   trail.Serial.LocationID,
+		LocationPath: trail.Serial.LocationPath,
+		Movements:    make([]*SerialMovementResponse, len(trail.Movements)),
+		CreatedAt:    trail.Serial.CreatedAt,
+		UpdatedAt:    trail.Serial.UpdatedAt,
+	}
+	for i, m := range trail.Movements {
+		resp.Movements[i] = &SerialMovementResponse{
+			ID:             m.ID,
+			Type:           string(m.Type),
+			ReasonCode:     m.ReasonCode,
+			Reference:      m.Reference,
+			FromLocationID: m.FromLocationID,
+			ToLocationID:   m.ToLocationID,
+			FromLocation:   m.FromLocation,
+			ToLocation:     m.ToLocation,
+			CreatedBy:      m.CreatedBy,
+			CreatedAt:      m.CreatedAt,
+		}
+	}
+	return resp
+}
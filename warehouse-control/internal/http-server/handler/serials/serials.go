@@ -0,0 +1,53 @@
+package serials_handler
+
+import (
+	"errors"
+	"net/http"
+
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/serials/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type SerialsHandler struct {
+	serialsUsecase serialsUsecase
+	logger         *zlog.Zerolog
+}
+
+func NewHandler(serialsUsecase serialsUsecase, logger *zlog.Zerolog) *SerialsHandler {
+	return &SerialsHandler{
+		serialsUsecase: serialsUsecase,
+		logger:         logger,
+	}
+}
+
+func (h *SerialsHandler) GetSerial(c *gin.Context) {
+	trail, err := h.serialsUsecase.GetSerialTrail(c.Request.Context(), c.Param("sn"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetSerial failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToSerialResponse(trail))
+}
+
+func (h *SerialsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrSerialNotFound), errors.Is(err, customErr.ErrItemNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
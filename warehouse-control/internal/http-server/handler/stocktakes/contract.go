@@ -0,0 +1,18 @@
+package stocktakes_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type stocktakesUsecase interface {
+	CreateStocktake(ctx context.Context, st *domain.Stocktake, blind *bool, username string) (int64, error)
+	GetStocktakes(ctx context.Context, filter domain.StocktakeFilter) ([]*domain.Stocktake, int, error)
+	GetStocktakeByID(ctx context.Context, id int64) (*domain.Stocktake, error)
+	GetVariances(ctx context.Context, id int64) (*domain.Stocktake, error)
+	SubmitCounts(ctx context.Context, id int64, counts []*domain.StocktakeCount, username string) error
+	CloseCounting(ctx context.Context, id int64, username string) error
+	CancelStocktake(ctx context.Context, id int64, username string) error
+	PostStocktake(ctx context.Context, id int64, username string) error
+}
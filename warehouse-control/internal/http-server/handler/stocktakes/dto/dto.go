@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type CreateStocktakeRequest struct {
+	LocationIDs []int64  `json:"location_ids"`
+	Categories  []string `json:"categories"`
+	Blind       *bool    `json:"blind,omitempty"`
+}
+
+type CountRequest struct {
+	ItemID     int64  `json:"item_id"`
+	LocationID *int64 `json:"location_id,omitempty"`
+	Quantity   int    `json:"quantity"`
+}
+
+type SubmitCountsRequest struct {
+	Counts []*CountRequest `json:"counts" binding:"required"`
+}
+
+type StocktakeLineResponse struct {
+	ID              int64      `json:"id"`
+	ItemID          int64      `json:"item_id"`
+	ItemName        string     `json:"item_name"`
+	ItemSKU         string     `json:"item_sku"`
+	LocationID      *int64     `json:"location_id,omitempty"`
+	LocationPath    string     `json:"location_path,omitempty"`
+	SystemQuantity  *int       `json:"system_quantity,omitempty"`
+	CountedQuantity *int       `json:"counted_quantity,omitempty"`
+	Variance        *int       `json:"variance,omitempty"`
+	CountedBy       string     `json:"counted_by,omitempty"`
+	CountedAt       *time.Time `json:"counted_at,omitempty"`
+}
+
+type StocktakeResponse struct {
+	ID          int64                    `json:"id"`
+	Status      string                   `json:"status"`
+	Blind       bool                     `json:"blind"`
+	LocationIDs []int64                  `json:"location_ids"`
+	Categories  []string                 `json:"categories"`
+	CreatedBy   string                   `json:"created_by"`
+	CreatedAt   time.Time                `json:"created_at"`
+	PostedBy    string                   `json:"posted_by,omitempty"`
+	PostedAt    *time.Time               `json:"posted_at,omitempty"`
+	Lines       []*StocktakeLineResponse `json:"lines,omitempty"`
+}
+
+type StocktakesResponse struct {
+	Stocktakes []*StocktakeResponse `json:"stocktakes"`
+	Total      int                  `json:"total"`
+}
+
+// ToStocktakeResponse leaves out system quantities and variances while a
+// blind session is being counted.
+func ToStocktakeResponse(st *domain.Stocktake) *StocktakeResponse {
+	resp := &StocktakeResponse{
+		ID:          st.ID,
+		Status:      string(st.Status),
+		Blind:       st.Blind,
+		LocationIDs: st.LocationIDs,
+		Categories:  st.Categories,
+		CreatedBy:   st.CreatedBy,
+		CreatedAt:   st.CreatedAt,
+		PostedBy:    st.PostedBy,
+		PostedAt:    st.PostedAt,
+	}
+	hidden := st.VariancesHidden()
+	for _, l := range st.Lines {
+		line := &StocktakeLineResponse{
+			ID:              l.ID,
+			ItemID:          l.ItemID,
+			ItemName:        l.ItemName,
+			ItemSKU:         l.ItemSKU,
+			LocationID:      l.LocationID,
+			LocationPath:    l.LocationPath,
+			CountedQuantity: l.CountedQuantity,
+			CountedBy:       l.CountedBy,
+			CountedAt:       l.CountedAt,
+		}
+		if !hidden {
+			system := l.SystemQuantity
+			line.SystemQuantity = &system
+			if variance, ok := l.Variance(); ok {
+				line.Variance = &variance
+			}
+		}
+		resp.Lines = append(resp.Lines, line)
+	}
+	return resp
+}
@@ -0,0 +1,209 @@
+package stocktakes_handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/stocktakes/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type StocktakesHandler struct {
+	stocktakesUsecase stocktakesUsecase
+	logger            *zlog.Zerolog
+}
+
+func NewHandler(stocktakesUsecase stocktakesUsecase, logger *zlog.Zerolog) *StocktakesHandler {
+	return &StocktakesHandler{
+		stocktakesUsecase: stocktakesUsecase,
+		logger:            logger,
+	}
+}
+
+func (h *StocktakesHandler) CreateStocktake(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateStocktakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	st := &domain.Stocktake{
+		LocationIDs: req.LocationIDs,
+		Categories:  req.Categories,
+	}
+	id, err := h.stocktakesUsecase.CreateStocktake(c.Request.Context(), st, req.Blind, claims.Username)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateStocktake failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id, "blind": st.Blind})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Stocktake created")
+}
+
+func (h *StocktakesHandler) GetStocktakes(c *gin.Context) {
+	filter := domain.StocktakeFilter{
+		Limit: 100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	if st := c.Query("status"); st != "" {
+		status := domain.StocktakeStatus(st)
+		filter.Status = &status
+	}
+	stocktakes, total, err := h.stocktakesUsecase.GetStocktakes(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetStocktakes failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.StocktakesResponse{
+		Stocktakes: make([]*dto.StocktakeResponse, len(stocktakes)),
+		Total:      total,
+	}
+	for i, st := range stocktakes {
+		resp.Stocktakes[i] = dto.ToStocktakeResponse(st)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *StocktakesHandler) GetStocktakeByID(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	st, err := h.stocktakesUsecase.GetStocktakeByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToStocktakeResponse(st))
+}
+
+func (h *StocktakesHandler) GetVariances(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	st, err := h.stocktakesUsecase.GetVariances(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToStocktakeResponse(st))
+}
+
+func (h *StocktakesHandler) SubmitCounts(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.SubmitCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	counts := make([]*domain.StocktakeCount, 0, len(req.Counts))
+	for _, cnt := range req.Counts {
+		if cnt == nil {
+			h.writeError(c, customErr.ErrInvalidInput)
+			return
+		}
+		counts = append(counts, &domain.StocktakeCount{
+			ItemID:     cnt.ItemID,
+			LocationID: cnt.LocationID,
+			Quantity:   cnt.Quantity,
+		})
+	}
+	if err := h.stocktakesUsecase.SubmitCounts(c.Request.Context(), id, counts, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Stocktake counts submitted")
+}
+
+func (h *StocktakesHandler) CloseCounting(c *gin.Context) {
+	h.transition(c, h.stocktakesUsecase.CloseCounting, "Stocktake counting closed")
+}
+
+func (h *StocktakesHandler) PostStocktake(c *gin.Context) {
+	h.transition(c, h.stocktakesUsecase.PostStocktake, "Stocktake posted")
+}
+
+func (h *StocktakesHandler) CancelStocktake(c *gin.Context) {
+	h.transition(c, h.stocktakesUsecase.CancelStocktake, "Stocktake cancelled")
+}
+
+func (h *StocktakesHandler) transition(c *gin.Context, apply func(ctx context.Context, id int64, username string) error, msg string) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	if err := apply(c.Request.Context(), id, claims.Username); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg(msg)
+}
+
+func (h *StocktakesHandler) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *StocktakesHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrStocktakeNotFound), errors.Is(err, customErr.ErrLocationNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrStocktakeClosed), errors.Is(err, customErr.ErrInsufficientStock):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
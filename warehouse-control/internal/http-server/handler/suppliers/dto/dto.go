@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+)
+
+type CreateSupplierRequest struct {
+	Code  string `json:"code" binding:"required"`
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type UpdateSupplierRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type SupplierResponse struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SuppliersResponse struct {
+	Suppliers []*SupplierResponse `json:"suppliers"`
+	Total     int                 `json:"total"`
+}
+
+func ToSupplierResponse(s *domain.Supplier) *SupplierResponse {
+	return &SupplierResponse{
+		ID:        s.ID,
+		Code:      s.Code,
+		Name:      s.Name,
+		Email:     s.Email,
+		Phone:     s.Phone,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package suppliers_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/suppliers/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type SuppliersHandler struct {
+	suppliersUsecase suppliersUsecase
+	logger           *zlog.Zerolog
+}
+
+func NewHandler(suppliersUsecase suppliersUsecase, logger *zlog.Zerolog) *SuppliersHandler {
+	return &SuppliersHandler{
+		suppliersUsecase: suppliersUsecase,
+		logger:           logger,
+	}
+}
+
+func (h *SuppliersHandler) CreateSupplier(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateSupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to decode request")
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	supplier := &domain.Supplier{
+		Code:  req.Code,
+		Name:  req.Name,
+		Email: req.Email,
+		Phone: req.Phone,
+	}
+	id, err := h.suppliersUsecase.CreateSupplier(c.Request.Context(), supplier)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("CreateSupplier failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Supplier created")
+}
+
+func (h *SuppliersHandler) GetSuppliers(c *gin.Context) {
+	filter := domain.SupplierFilter{
+		Search: c.Query("search"),
+		Limit:  100,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			filter.Offset = o
+		}
+	}
+	suppliers, total, err := h.suppliersUsecase.GetSuppliers(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetSuppliers failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := dto.SuppliersResponse{
+		Suppliers: make([]*dto.SupplierResponse, len(suppliers)),
+		Total:     total,
+	}
+	for i, s := range suppliers {
+		resp.Suppliers[i] = dto.ToSupplierResponse(s)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SuppliersHandler) GetSupplierByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	supplier, err := h.suppliersUsecase.GetSupplierByID(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToSupplierResponse(supplier))
+}
+
+func (h *SuppliersHandler) UpdateSupplier(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.UpdateSupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	supplier := &domain.Supplier{
+		Name:  req.Name,
+		Email: req.Email,
+		Phone: req.Phone,
+	}
+	if err := h.suppliersUsecase.UpdateSupplier(c.Request.Context(), id, supplier); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+	h.logger.Info().Int64("id", id).Str("user", claims.Username).Msg("Supplier updated")
+}
+
+func (h *SuppliersHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrSupplierNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrSupplierExists):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,15 @@
+package units_handler
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type unitsUsecase interface {
+	CreateUnit(ctx context.Context, u *domain.Unit) error
+	GetUnits(ctx context.Context) ([]*domain.Unit, error)
+	GetItemUnits(ctx context.Context, itemID int64) (*domain.ItemUnits, error)
+	SetItemUnit(ctx context.Context, c *domain.ItemUnit) error
+	DeleteItemUnit(ctx context.Context, itemID int64, unit string) error
+}
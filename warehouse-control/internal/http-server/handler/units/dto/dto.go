@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type CreateUnitRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Discrete bool   `json:"discrete"`
+}
+
+type SetItemUnitRequest struct {
+	Factor decimal.Decimal `json:"factor"`
+}
+
+type UnitResponse struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Discrete  bool      `json:"discrete"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ItemUnitResponse struct {
+	Unit     string          `json:"unit"`
+	Factor   decimal.Decimal `json:"factor"`
+	Discrete bool            `json:"discrete"`
+}
+
+type ItemUnitsResponse struct {
+	ItemID      int64               `json:"item_id"`
+	BaseUnit    string              `json:"base_unit"`
+	Conversions []*ItemUnitResponse `json:"conversions"`
+}
+
+func ToUnitResponse(u *domain.Unit) *UnitResponse {
+	return &UnitResponse{
+		Code:      u.Code,
+		Name:      u.Name,
+		Discrete:  u.Discrete,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+func ToItemUnitResponse(c *domain.ItemUnit) *ItemUnitResponse {
+	return &ItemUnitResponse{
+		Unit:     c.Unit,
+		Factor:   c.Factor,
+		Discrete: c.Discrete,
+	}
+}
+
+func ToItemUnitsResponse(u *domain.ItemUnits) *ItemUnitsResponse {
+	resp := &ItemUnitsResponse{
+		ItemID:      u.ItemID,
+		BaseUnit:    u.BaseUnit,
+		Conversions: make([]*ItemUnitResponse, len(u.Conversions)),
+	}
+	for i, c := range u.Conversions {
+		resp.Conversions[i] = ToItemUnitResponse(c)
+	}
+	return resp
+}
@@ -0,0 +1,148 @@
+package units_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/units/dto"
+	"warehouse-control/internal/http-server/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type UnitsHandler struct {
+	unitsUsecase unitsUsecase
+	logger       *zlog.Zerolog
+}
+
+func NewHandler(unitsUsecase unitsUsecase, logger *zlog.Zerolog) *UnitsHandler {
+	return &UnitsHandler{
+		unitsUsecase: unitsUsecase,
+		logger:       logger,
+	}
+}
+
+func (h *UnitsHandler) CreateUnit(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	var req dto.CreateUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	unit := &domain.Unit{
+		Code:     req.Code,
+		Name:     req.Name,
+		Discrete: req.Discrete,
+	}
+	if err := h.unitsUsecase.CreateUnit(c.Request.Context(), unit); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToUnitResponse(unit))
+	h.logger.Info().Str("code", unit.Code).Str("user", claims.Username).Msg("Unit created")
+}
+
+func (h *UnitsHandler) GetUnits(c *gin.Context) {
+	units, err := h.unitsUsecase.GetUnits(c.Request.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetUnits failed")
+		h.writeError(c, err)
+		return
+	}
+	resp := make([]*dto.UnitResponse, len(units))
+	for i, u := range units {
+		resp[i] = dto.ToUnitResponse(u)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *UnitsHandler) GetItemUnits(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	units, err := h.unitsUsecase.GetItemUnits(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToItemUnitsResponse(units))
+}
+
+func (h *UnitsHandler) SetItemUnit(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	var req dto.SetItemUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	conversion := &domain.ItemUnit{
+		ItemID: id,
+		Unit:   c.Param("unit"),
+		Factor: req.Factor,
+	}
+	if err := h.unitsUsecase.SetItemUnit(c.Request.Context(), conversion); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToItemUnitResponse(conversion))
+	h.logger.Info().Int64("item_id", id).Str("unit", conversion.Unit).Str("user", claims.Username).Msg("Item unit set")
+}
+
+func (h *UnitsHandler) DeleteItemUnit(c *gin.Context) {
+	claims := middleware.GetClaimsFromContext(c)
+	if claims == nil {
+		h.writeError(c, customErr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	if err := h.unitsUsecase.DeleteItemUnit(c.Request.Context(), id, c.Param("unit")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+	h.logger.Info().Int64("item_id", id).Str("unit", c.Param("unit")).Str("user", claims.Username).Msg("Item unit deleted")
+}
+
+func (h *UnitsHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrUnitNotFound), errors.Is(err, customErr.ErrItemNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, customErr.ErrUnitExists):
+		code = http.StatusConflict
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
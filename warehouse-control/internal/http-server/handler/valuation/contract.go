@@ -0,0 +1,12 @@
+package valuation_handler
+
+import (
+	"context"
+	"time"
+
+	"warehouse-control/internal/domain"
+)
+
+type valuationUsecase interface {
+	GetValuation(ctx context.Context, asOf time.Time) (*domain.Valuation, error)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+)
+
+type CategoryValuationResponse struct {
+	Category string          `json:"category"`
+	Quantity int             `json:"quantity"`
+	Value    decimal.Decimal `json:"value"`
+}
+
+type LocationValuationResponse struct {
+	LocationID   *int64          `json:"location_id"`
+	LocationPath string          `json:"location_path"`
+	Quantity     int             `json:"quantity"`
+	Value        decimal.Decimal `json:"value"`
+}
+
+type ValuationResponse struct {
+	AsOf       time.Time                    `json:"as_of"`
+	Quantity   int                          `json:"quantity"`
+	Value      decimal.Decimal              `json:"value"`
+	ByCategory []*CategoryValuationResponse `json:"by_category"`
+	ByLocation []*LocationValuationResponse `json:"by_location"`
+}
+
+func ToValuationResponse(v *domain.Valuation) *ValuationResponse {
+	resp := &ValuationResponse{
+		AsOf:       v.AsOf,
+		Quantity:   v.Quantity,
+		Value:      v.Value,
+		ByCategory: make([]*CategoryValuationResponse, len(v.ByCategory)),
+		ByLocation: make([]*LocationValuationResponse, len(v.ByLocation)),
+	}
+	for i, c := range v.ByCategory {
+		resp.ByCategory[i] = &CategoryValuationResponse{
+			Category: c.Category,
+			Quantity: c.Quantity,
+			Value:    c.Value,
+		}
+	}
+	for i, l := range v.ByLocation {
+		resp.ByLocation[i] = &LocationValuationResponse{
+			LocationID:   l.LocationID,
+			LocationPath: l.LocationPath,
+			Quantity:     l.Quantity,
+			Value:        l.Value,
+		}
+	}
+	return resp
+}
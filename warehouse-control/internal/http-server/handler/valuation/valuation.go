@@ -0,0 +1,73 @@
+package valuation_handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	customErr "warehouse-control/internal/domain/errors"
+	"warehouse-control/internal/http-server/handler/valuation/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ValuationHandler struct {
+	valuationUsecase valuationUsecase
+	logger           *zlog.Zerolog
+}
+
+func NewHandler(valuationUsecase valuationUsecase, logger *zlog.Zerolog) *ValuationHandler {
+	return &ValuationHandler{
+		valuationUsecase: valuationUsecase,
+		logger:           logger,
+	}
+}
+
+func (h *ValuationHandler) GetValuation(c *gin.Context) {
+	asOf, err := parseAsOf(c.Query("as_of"))
+	if err != nil {
+		h.writeError(c, customErr.ErrInvalidInput)
+		return
+	}
+	valuation, err := h.valuationUsecase.GetValuation(c.Request.Context(), asOf)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("GetValuation failed")
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToValuationResponse(valuation))
+}
+
+func (h *ValuationHandler) writeError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		code = http.StatusBadRequest
+	case errors.Is(err, customErr.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(err, customErr.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(err, customErr.ErrDatabase):
+		code = http.StatusInternalServerError
+	case errors.Is(err, customErr.ErrInternal):
+		code = http.StatusInternalServerError
+	}
+	c.JSON(code, gin.H{"error": err.Error()})
+}
+
+// parseAsOf accepts a timestamp or a date; a date values the inventory at
+// the end of that day.
+func parseAsOf(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
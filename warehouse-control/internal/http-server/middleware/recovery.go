@@ -13,6 +13,12 @@ func RecoveryMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
+				// A handler aborts a response it has started to send by
+				// panicking with http.ErrAbortHandler; the server then drops
+				// the connection so the client sees the response fail.
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
 				zlog.Logger.Error().
 					Interface("error", err).
 					Msg("Panic recovered")
@@ -3,9 +3,30 @@ package router
 import (
 	"warehouse-control/internal/config"
 	"warehouse-control/internal/domain"
+	alertsH "warehouse-control/internal/http-server/handler/alerts"
+	attachmentsH "warehouse-control/internal/http-server/handler/attachments"
 	authH "warehouse-control/internal/http-server/handler/auth"
+	barcodesH "warehouse-control/internal/http-server/handler/barcodes"
+	categoriesH "warehouse-control/internal/http-server/handler/categories"
+	exchangeRatesH "warehouse-control/internal/http-server/handler/exchange_rates"
 	historyH "warehouse-control/internal/http-server/handler/history"
+	importsH "warehouse-control/internal/http-server/handler/imports"
 	itemsH "warehouse-control/internal/http-server/handler/items"
+	kitsH "warehouse-control/internal/http-server/handler/kits"
+	labelsH "warehouse-control/internal/http-server/handler/labels"
+	locationsH "warehouse-control/internal/http-server/handler/locations"
+	lotsH "warehouse-control/internal/http-server/handler/lots"
+	movementsH "warehouse-control/internal/http-server/handler/movements"
+	ordersH "warehouse-control/internal/http-server/handler/orders"
+	productsH "warehouse-control/internal/http-server/handler/products"
+	purchaseOrdersH "warehouse-control/internal/http-server/handler/purchase_orders"
+	reservationsH "warehouse-control/internal/http-server/handler/reservations"
+	returnsH "warehouse-control/internal/http-server/handler/returns"
+	serialsH "warehouse-control/internal/http-server/handler/serials"
+	stocktakesH "warehouse-control/internal/http-server/handler/stocktakes"
+	suppliersH "warehouse-control/internal/http-server/handler/suppliers"
+	unitsH "warehouse-control/internal/http-server/handler/units"
+	valuationH "warehouse-control/internal/http-server/handler/valuation"
 
 	"warehouse-control/internal/http-server/middleware"
 
@@ -15,6 +36,27 @@ import (
 
 func New(items *itemsH.ItemsHandler,
 	history *historyH.HistoryHandler,
+	movements *movementsH.MovementsHandler,
+	locations *locationsH.LocationsHandler,
+	reservations *reservationsH.ReservationsHandler,
+	lots *lotsH.LotsHandler,
+	serials *serialsH.SerialsHandler,
+	alerts *alertsH.AlertsHandler,
+	stocktakes *stocktakesH.StocktakesHandler,
+	suppliers *suppliersH.SuppliersHandler,
+	purchaseOrders *purchaseOrdersH.PurchaseOrdersHandler,
+	orders *ordersH.OrdersHandler,
+	returns *returnsH.ReturnsHandler,
+	valuation *valuationH.ValuationHandler,
+	exchangeRates *exchangeRatesH.ExchangeRatesHandler,
+	units *unitsH.UnitsHandler,
+	kits *kitsH.KitsHandler,
+	products *productsH.ProductsHandler,
+	categories *categoriesH.CategoriesHandler,
+	attachments *attachmentsH.AttachmentsHandler,
+	labels *labelsH.LabelsHandler,
+	barcodes *barcodesH.BarcodesHandler,
+	imports *importsH.ImportsHandler,
 	auth *authH.AuthHandler,
 	mw *middleware.AuthMiddleware,
 	cfg *config.Config,
@@ -43,14 +85,109 @@ func New(items *itemsH.ItemsHandler,
 	protected.Use(mw.Middleware())
 
 	protected.GET("/items", items.GetItems)
+	protected.GET("/items/export", items.ExportItems)
 	protected.GET("/items/:id", items.GetItemByID)
 	protected.POST("/items", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), items.CreateItem)
 	protected.PUT("/items/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), items.UpdateItem)
 	protected.DELETE("/items/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), items.DeleteItem)
+	protected.POST("/items/:id/stock-status", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), items.ChangeStockStatus)
+	protected.POST("/items/bulk", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), items.BulkCreateItems)
+	protected.PATCH("/items/bulk", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), items.BulkUpdateItems)
 	protected.DELETE("/items/bulk", mw.RequireRole(domain.RoleAdmin), items.BulkDeleteItems)
+	protected.GET("/items/:id/movements", movements.GetMovements)
+	protected.POST("/items/:id/movements", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), movements.CreateMovement)
+	protected.GET("/items/:id/reservations", reservations.GetReservations)
+	protected.POST("/items/:id/reservations", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), reservations.CreateReservation)
+	protected.DELETE("/items/:id/reservations/:reservation_id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), reservations.ReleaseReservation)
+	protected.GET("/items/:id/lots", lots.GetItemLots)
+	protected.GET("/lots/expiring", lots.GetExpiringLots)
+	protected.GET("/lots/:id", lots.GetLotByID)
+	protected.GET("/lots/:id/history", lots.GetLotHistory)
+	protected.PUT("/lots/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), lots.UpdateLot)
+	protected.GET("/serials/:sn", serials.GetSerial)
+	protected.GET("/stocktakes", stocktakes.GetStocktakes)
+	protected.GET("/stocktakes/:id", stocktakes.GetStocktakeByID)
+	protected.GET("/stocktakes/:id/variances", stocktakes.GetVariances)
+	protected.POST("/stocktakes", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), stocktakes.CreateStocktake)
+	protected.POST("/stocktakes/:id/counts", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), stocktakes.SubmitCounts)
+	protected.POST("/stocktakes/:id/review", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), stocktakes.CloseCounting)
+	protected.POST("/stocktakes/:id/post", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), stocktakes.PostStocktake)
+	protected.DELETE("/stocktakes/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), stocktakes.CancelStocktake)
+	protected.GET("/suppliers", suppliers.GetSuppliers)
+	protected.GET("/suppliers/:id", suppliers.GetSupplierByID)
+	protected.POST("/suppliers", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), suppliers.CreateSupplier)
+	protected.PUT("/suppliers/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), suppliers.UpdateSupplier)
+	protected.GET("/purchase-orders", purchaseOrders.GetPurchaseOrders)
+	protected.GET("/purchase-orders/:id", purchaseOrders.GetPurchaseOrderByID)
+	protected.POST("/purchase-orders", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), purchaseOrders.CreatePurchaseOrder)
+	protected.DELETE("/purchase-orders/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), purchaseOrders.CancelPurchaseOrder)
+	protected.POST("/purchase-orders/:id/receipts", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), purchaseOrders.ReceivePurchaseOrder)
+	protected.POST("/purchase-orders/:id/receipts/override", mw.RequireRole(domain.RoleAdmin), purchaseOrders.ReceivePurchaseOrderOverride)
+	protected.GET("/orders", orders.GetOrders)
+	protected.GET("/orders/:id", orders.GetOrderByID)
+	protected.GET("/orders/:id/history", orders.GetOrderHistory)
+	protected.GET("/orders/:id/pick-list", orders.GetPickList)
+	protected.POST("/orders", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), orders.CreateOrder)
+	protected.POST("/orders/:id/allocate", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), orders.AllocateOrder)
+	protected.POST("/orders/:id/picks", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), orders.ConfirmPicks)
+	protected.POST("/orders/:id/ship", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), orders.ShipOrder)
+	protected.POST("/orders/:id/cancel", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), orders.CancelOrder)
+	protected.GET("/returns", returns.GetReturns)
+	protected.GET("/returns/:id", returns.GetReturnByID)
+	protected.POST("/returns", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), returns.CreateReturn)
+	protected.POST("/returns/:id/receive", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), returns.ReceiveReturn)
+	protected.POST("/returns/:id/dispositions", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), returns.DispositionReturn)
+	protected.POST("/returns/:id/cancel", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), returns.CancelReturn)
+	protected.GET("/reports/valuation", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), valuation.GetValuation)
+	protected.GET("/exchange-rates", exchangeRates.GetRates)
+	protected.PUT("/exchange-rates/:currency", mw.RequireRole(domain.RoleAdmin), exchangeRates.SetRate)
+	protected.DELETE("/exchange-rates/:currency", mw.RequireRole(domain.RoleAdmin), exchangeRates.DeleteRate)
+	protected.GET("/units", units.GetUnits)
+	protected.POST("/units", mw.RequireRole(domain.RoleAdmin), units.CreateUnit)
+	protected.GET("/items/:id/units", units.GetItemUnits)
+	protected.PUT("/items/:id/units/:unit", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), units.SetItemUnit)
+	protected.DELETE("/items/:id/units/:unit", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), units.DeleteItemUnit)
+	protected.GET("/items/:id/kit", kits.GetKit)
+	protected.PUT("/items/:id/kit", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), kits.SetKit)
+	protected.DELETE("/items/:id/kit", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), kits.DeleteKit)
+	protected.POST("/items/:id/assemble", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), kits.Assemble)
+	protected.POST("/items/:id/disassemble", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), kits.Disassemble)
+	protected.GET("/products", products.GetProducts)
+	protected.GET("/products/:id", products.GetProductByID)
+	protected.POST("/products", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), products.CreateProduct)
+	protected.PUT("/products/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), products.UpdateProduct)
+	protected.DELETE("/products/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), products.DeleteProduct)
+	protected.GET("/categories", categories.GetCategories)
+	protected.GET("/categories/:id", categories.GetCategoryByID)
+	protected.POST("/categories", mw.RequireRole(domain.RoleAdmin), categories.CreateCategory)
+	protected.PUT("/categories/:id", mw.RequireRole(domain.RoleAdmin), categories.UpdateCategory)
+	protected.DELETE("/categories/:id", mw.RequireRole(domain.RoleAdmin), categories.DeleteCategory)
+	protected.GET("/categories/:id/attributes", categories.GetAttributes)
+	protected.PUT("/categories/:id/attributes/:name", mw.RequireRole(domain.RoleAdmin), categories.SetAttribute)
+	protected.DELETE("/categories/:id/attributes/:name", mw.RequireRole(domain.RoleAdmin), categories.DeleteAttribute)
+	protected.GET("/items/:id/attachments", attachments.GetAttachments)
+	protected.POST("/items/:id/attachments", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), attachments.UploadAttachment)
+	protected.GET("/items/:id/attachments/:attachment_id", attachments.DownloadAttachment)
+	protected.GET("/items/:id/attachments/:attachment_id/thumbnail", attachments.DownloadThumbnail)
+	protected.DELETE("/items/:id/attachments/:attachment_id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), attachments.DeleteAttachment)
+	protected.GET("/items/:id/label", labels.GetItemLabel)
+	protected.POST("/labels/sheet", labels.GetLabelSheet)
+	protected.GET("/items/:id/barcodes", barcodes.GetBarcodes)
+	protected.POST("/items/:id/barcodes", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), barcodes.AddBarcode)
+	protected.DELETE("/items/:id/barcodes/:barcode_id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), barcodes.DeleteBarcode)
+	protected.GET("/scan/*code", barcodes.Scan)
+	protected.POST("/imports/items", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), imports.ImportItems)
+	protected.GET("/imports/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), imports.GetImportJob)
+	protected.GET("/alerts", alerts.GetAlerts)
+	protected.POST("/alerts/:id/acknowledge", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), alerts.AcknowledgeAlert)
+	protected.GET("/locations", locations.GetLocations)
+	protected.GET("/locations/:id", locations.GetLocationByID)
+	protected.POST("/locations", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), locations.CreateLocation)
+	protected.PUT("/locations/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), locations.UpdateLocation)
+	protected.DELETE("/locations/:id", mw.RequireRole(domain.RoleManager, domain.RoleAdmin), locations.DeleteLocation)
 	protected.GET("/history", history.GetHistory)
 	protected.GET("/history/item/:id", history.GetItemHistory)
-	protected.GET("/history/export", history.ExportHistoryCSV)
+	protected.GET("/history/export", history.ExportHistory)
 
 	r.GET("/", func(c *gin.Context) {
 		c.File("./static/index.html")
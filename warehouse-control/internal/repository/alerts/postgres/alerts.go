@@ -0,0 +1,172 @@
+package alerts_postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+	items_postgres "warehouse-control/internal/repository/items/postgres"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type AlertsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *AlertsPostgresRepository {
+	return &AlertsPostgresRepository{db: db, retries: retries}
+}
+
+// EvaluateAlerts compares the available stock of items against their
+// reorder level: unresolved alerts of items that are back at or above it are
+// resolved, and low items without an unresolved alert get a new one, which
+// records the available quantity. A nil itemID evaluates every item.
+func (r *AlertsPostgresRepository) EvaluateAlerts(ctx context.Context, itemID *int64) (raised, resolved int64, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE alerts a SET status = 'resolved', resolved_at = NOW()
+		WHERE a.status <> 'resolved' AND ($1::INT IS NULL OR a.item_id = $1)
+		  AND NOT EXISTS (SELECT 1 FROM items WHERE items.id = a.item_id AND `+items_postgres.AvailableColumn+` < items.reorder_level)`, itemID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: failed to resolve alerts: %v", customErr.ErrDatabase, err)
+	}
+	if resolved, err = res.RowsAffected(); err != nil {
+		return 0, 0, fmt.Errorf("%w: failed to resolve alerts: %v", customErr.ErrDatabase, err)
+	}
+
+	res, err = tx.ExecContext(ctx, `
+		INSERT INTO alerts (item_id, quantity, reorder_level, reorder_quantity)
+		SELECT id, available, reorder_level, reorder_quantity
+		FROM (SELECT id, `+items_postgres.AvailableColumn+` AS available, reorder_level, reorder_quantity
+		      FROM items WHERE $1::INT IS NULL OR id = $1) low
+		WHERE available < reorder_level
+		ON CONFLICT (item_id) WHERE status <> 'resolved' DO NOTHING`, itemID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: failed to raise alerts: %v", customErr.ErrDatabase, err)
+	}
+	if raised, err = res.RowsAffected(); err != nil {
+		return 0, 0, fmt.Errorf("%w: failed to raise alerts: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return raised, resolved, nil
+}
+
+func (r *AlertsPostgresRepository) GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("a.status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+	if filter.ItemID != nil {
+		conditions = append(conditions, fmt.Sprintf("a.item_id = $%d", argIndex))
+		args = append(args, *filter.ItemID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM alerts a %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count alerts error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total alerts error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Alert{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.item_id, i.name, i.sku, a.quantity, a.reorder_level, a.reorder_quantity, a.status,
+		       COALESCE(a.acknowledged_by, ''), a.acknowledged_at, a.resolved_at, a.created_at
+		FROM alerts a
+		JOIN items i ON i.id = a.item_id
+		%s
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: select alerts error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	alerts := make([]*domain.Alert, 0, filter.Limit)
+	for rows.Next() {
+		a := &domain.Alert{}
+		var acknowledgedAt, resolvedAt sql.NullTime
+		err := rows.Scan(&a.ID, &a.ItemID, &a.ItemName, &a.ItemSKU, &a.Quantity, &a.ReorderLevel, &a.ReorderQuantity,
+			&a.Status, &a.AcknowledgedBy, &acknowledgedAt, &resolvedAt, &a.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan alert error: %v", customErr.ErrDatabase, err)
+		}
+		if acknowledgedAt.Valid {
+			a.AcknowledgedAt = &acknowledgedAt.Time
+		}
+		if resolvedAt.Valid {
+			a.ResolvedAt = &resolvedAt.Time
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: alerts rows error: %v", customErr.ErrDatabase, err)
+	}
+
+	return alerts, total, nil
+}
+
+func (r *AlertsPostgresRepository) AcknowledgeAlert(ctx context.Context, id int64, username string) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE alerts SET status = 'acknowledged', acknowledged_by = $1, acknowledged_at = NOW()
+		WHERE id = $2 AND status = 'open'`, username, id)
+	if err != nil {
+		return fmt.Errorf("%w: acknowledge failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: acknowledge failed: %v", customErr.ErrDatabase, err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `SELECT EXISTS (SELECT 1 FROM alerts WHERE id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if !exists {
+		return customErr.ErrAlertNotFound
+	}
+	return customErr.ErrAlertNotOpen
+}
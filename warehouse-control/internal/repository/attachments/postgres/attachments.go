@@ -0,0 +1,138 @@
+package attachments_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const foreignKeyViolation = "23503"
+
+const attachmentColumns = `
+	SELECT id, item_id, file_name, content_type, size, storage_key, COALESCE(thumbnail_key, ''), uploaded_by, created_at
+	FROM item_attachments`
+
+type AttachmentsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *AttachmentsPostgresRepository {
+	return &AttachmentsPostgresRepository{db: db, retries: retries}
+}
+
+// CreateAttachment records a stored file; the trigger on item_attachments
+// logs it to the item's history.
+func (r *AttachmentsPostgresRepository) CreateAttachment(ctx context.Context, a *domain.Attachment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", a.UploadedBy); err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO item_attachments (item_id, file_name, content_type, size, storage_key, thumbnail_key, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7) RETURNING id, created_at`,
+		a.ItemID, a.FileName, a.ContentType, a.Size, a.StorageKey, a.ThumbnailKey, a.UploadedBy,
+	).Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return customErr.ErrItemNotFound
+		}
+		return fmt.Errorf("%w: failed to insert attachment: %v", customErr.ErrDatabase, err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *AttachmentsPostgresRepository) GetAttachments(ctx context.Context, itemID int64) ([]*domain.Attachment, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, attachmentColumns+` WHERE item_id = $1 ORDER BY created_at, id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query attachments error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	attachments := make([]*domain.Attachment, 0)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan attachment error: %v", customErr.ErrDatabase, err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentsPostgresRepository) GetAttachment(ctx context.Context, itemID, id int64) (*domain.Attachment, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, attachmentColumns+` WHERE item_id = $1 AND id = $2`, itemID, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	a, err := scanAttachment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return a, nil
+}
+
+// DeleteAttachment removes the record and returns it, so the caller can
+// remove the stored files.
+func (r *AttachmentsPostgresRepository) DeleteAttachment(ctx context.Context, itemID, id int64, username string) (*domain.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username); err != nil {
+		return nil, fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	a, err := scanAttachment(tx.QueryRowContext(ctx, `
+		DELETE FROM item_attachments WHERE item_id = $1 AND id = $2
+		RETURNING id, item_id, file_name, content_type, size, storage_key, COALESCE(thumbnail_key, ''), uploaded_by, created_at`,
+		itemID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to delete attachment: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return a, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(s scanner) (*domain.Attachment, error) {
+	a := &domain.Attachment{}
+	err := s.Scan(&a.ID, &a.ItemID, &a.FileName, &a.ContentType, &a.Size, &a.StorageKey, &a.ThumbnailKey, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
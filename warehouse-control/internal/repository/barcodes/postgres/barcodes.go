@@ -0,0 +1,192 @@
+package barcodes_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+const barcodeColumns = `
+	SELECT id, item_id, code, barcode_type, supplier_id, created_at
+	FROM item_barcodes`
+
+type BarcodesPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *BarcodesPostgresRepository {
+	return &BarcodesPostgresRepository{db: db, retries: retries}
+}
+
+func (r *BarcodesPostgresRepository) CreateBarcode(ctx context.Context, b *domain.ItemBarcode) error {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `
+		INSERT INTO item_barcodes (item_id, code, barcode_type, supplier_id)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		b.ItemID, b.Code, b.Type, b.SupplierID)
+	if err == nil {
+		err = row.Scan(&b.ID, &b.CreatedAt)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch {
+			case pqErr.Code == uniqueViolation:
+				return customErr.ErrBarcodeExists
+			case pqErr.Code == foreignKeyViolation && pqErr.Constraint == "item_barcodes_supplier_id_fkey":
+				return customErr.ErrSupplierNotFound
+			case pqErr.Code == foreignKeyViolation:
+				return customErr.ErrItemNotFound
+			}
+		}
+		return fmt.Errorf("%w: failed to insert barcode: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *BarcodesPostgresRepository) GetBarcodes(ctx context.Context, itemID int64) ([]*domain.ItemBarcode, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, barcodeColumns+` WHERE item_id = $1 ORDER BY id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query barcodes error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	barcodes := make([]*domain.ItemBarcode, 0)
+	for rows.Next() {
+		b := &domain.ItemBarcode{}
+		var supplierID sql.NullInt64
+		if err := rows.Scan(&b.ID, &b.ItemID, &b.Code, &b.Type, &supplierID, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w: scan barcode error: %v", customErr.ErrDatabase, err)
+		}
+		if supplierID.Valid {
+			b.SupplierID = &supplierID.Int64
+		}
+		barcodes = append(barcodes, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return barcodes, nil
+}
+
+func (r *BarcodesPostgresRepository) DeleteBarcode(ctx context.Context, itemID, id int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `DELETE FROM item_barcodes WHERE item_id = $1 AND id = $2`, itemID, id)
+	if err != nil {
+		return fmt.Errorf("%w: delete barcode error: %v", customErr.ErrDatabase, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return customErr.ErrBarcodeNotFound
+	}
+	return nil
+}
+
+// FindItemID returns the item a scanned code belongs to: an item barcode
+// wins over a SKU. gtinKey is the code padded to 14 digits when it is a
+// valid GTIN, so GTINs match however many digits the scanner reported.
+func (r *BarcodesPostgresRepository) FindItemID(ctx context.Context, code, gtinKey string) (int64, error) {
+	var id int64
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `
+		SELECT item_id FROM (
+			SELECT item_id, 0 AS rank FROM item_barcodes WHERE code = $1
+			UNION ALL
+			SELECT item_id, 1 FROM item_barcodes
+			WHERE $2 <> '' AND barcode_type = 'gtin' AND lpad(code, 14, '0') = $2
+			UNION ALL
+			SELECT id, 2 FROM items WHERE sku = $1
+		) m ORDER BY rank LIMIT 1`, code, gtinKey)
+	if err == nil {
+		err = row.Scan(&id)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, customErr.ErrItemNotFound
+		}
+		return 0, fmt.Errorf("%w: find item by code error: %v", customErr.ErrDatabase, err)
+	}
+	return id, nil
+}
+
+// FindLocationID returns the location with the path; location labels carry
+// the path.
+func (r *BarcodesPostgresRepository) FindLocationID(ctx context.Context, path string) (int64, error) {
+	var id int64
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `SELECT id FROM locations WHERE path = $1`, path)
+	if err == nil {
+		err = row.Scan(&id)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, customErr.ErrLocationNotFound
+		}
+		return 0, fmt.Errorf("%w: find location by path error: %v", customErr.ErrDatabase, err)
+	}
+	return id, nil
+}
+
+// GetLocationContents returns up to limit items stocked in the location and
+// its sublocations by SKU, and the number of all such items.
+func (r *BarcodesPostgresRepository) GetLocationContents(ctx context.Context, path string, limit int) ([]*domain.LocationContent, int, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT i.id, i.sku, i.name, SUM(s.quantity)::INT, COUNT(*) OVER ()
+		FROM item_stock s
+		JOIN locations l ON l.id = s.location_id
+		JOIN items i ON i.id = s.item_id
+		WHERE s.quantity > 0 AND (l.path = $1 OR starts_with(l.path, $1 || '/'))
+		GROUP BY i.id, i.sku, i.name
+		ORDER BY i.sku
+		LIMIT $2`, path, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query location contents error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	contents := make([]*domain.LocationContent, 0)
+	total := 0
+	for rows.Next() {
+		c := &domain.LocationContent{}
+		if err := rows.Scan(&c.ItemID, &c.SKU, &c.Name, &c.Quantity, &total); err != nil {
+			return nil, 0, fmt.Errorf("%w: scan location content error: %v", customErr.ErrDatabase, err)
+		}
+		contents = append(contents, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return contents, total, nil
+}
+
+// FindLotIDs returns the lots with the number. Lot numbers are unique per
+// item only, so there may be several.
+func (r *BarcodesPostgresRepository) FindLotIDs(ctx context.Context, lotNumber string) ([]int64, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `SELECT id FROM lots WHERE lot_number = $1 ORDER BY id`, lotNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: find lots by number error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%w: scan lot error: %v", customErr.ErrDatabase, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return ids, nil
+}
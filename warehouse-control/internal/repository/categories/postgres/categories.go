@@ -0,0 +1,258 @@
+package categories_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+const categoryColumns = `SELECT id, name, parent_id, path, created_at, updated_at FROM categories`
+
+type CategoriesPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *CategoriesPostgresRepository {
+	return &CategoriesPostgresRepository{db: db, retries: retries}
+}
+
+func (r *CategoriesPostgresRepository) CreateCategory(ctx context.Context, c *domain.Category) (int64, error) {
+	var id int64
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries,
+		`INSERT INTO categories (name, parent_id, path) VALUES ($1, $2, $3) RETURNING id`,
+		c.Name, c.ParentID, c.Path)
+	if err == nil {
+		err = row.Scan(&id)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrCategoryExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert category: %v", customErr.ErrDatabase, err)
+	}
+	return id, nil
+}
+
+// GetCategories returns the whole tree flat, ordered by path.
+func (r *CategoriesPostgresRepository) GetCategories(ctx context.Context) ([]*domain.Category, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, categoryColumns+` ORDER BY path`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query categories error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	categories := make([]*domain.Category, 0)
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan category error: %v", customErr.ErrDatabase, err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return categories, nil
+}
+
+func (r *CategoriesPostgresRepository) GetCategoryByID(ctx context.Context, id int64) (*domain.Category, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, categoryColumns+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	c, err := scanCategory(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return c, nil
+}
+
+// UpdateCategory renames the category and moves it under c.ParentID, or to
+// the root when it is nil. The paths of the category and its descendants are
+// rewritten; items and products follow through the cascading foreign keys.
+func (r *CategoriesPostgresRepository) UpdateCategory(ctx context.Context, c *domain.Category, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username); err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	var oldPath string
+	err = tx.QueryRowContext(ctx, `SELECT path FROM categories WHERE id = $1 FOR UPDATE`, c.ID).Scan(&oldPath)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrCategoryNotFound
+		}
+		return fmt.Errorf("%w: failed to lock category: %v", customErr.ErrDatabase, err)
+	}
+
+	c.Path = c.Name
+	if c.ParentID != nil {
+		var parentPath string
+		err = tx.QueryRowContext(ctx, `SELECT path FROM categories WHERE id = $1 FOR SHARE`, *c.ParentID).Scan(&parentPath)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: parent category not found", customErr.ErrInvalidInput)
+			}
+			return fmt.Errorf("%w: failed to lock parent category: %v", customErr.ErrDatabase, err)
+		}
+		if parentPath == oldPath || strings.HasPrefix(parentPath, oldPath+domain.CategorySeparator) {
+			return fmt.Errorf("%w: a category cannot be moved under itself or its subcategory", customErr.ErrInvalidInput)
+		}
+		c.Path = parentPath + domain.CategorySeparator + c.Name
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE categories SET name = $1, parent_id = $2, path = $3, updated_at = NOW()
+		WHERE id = $4 RETURNING created_at, updated_at`,
+		c.Name, c.ParentID, c.Path, c.ID,
+	).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return mapWriteError(err)
+	}
+
+	if c.Path != oldPath {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE categories SET path = $1 || substr(path, length($2) + 1), updated_at = NOW()
+			WHERE starts_with(path, $2 || '/')`,
+			c.Path, oldPath)
+		if err != nil {
+			return mapWriteError(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteCategory removes a category that has no subcategories and is not
+// used by any item or product.
+func (r *CategoriesPostgresRepository) DeleteCategory(ctx context.Context, id int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return customErr.ErrCategoryInUse
+		}
+		return fmt.Errorf("%w: delete category error: %v", customErr.ErrDatabase, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return customErr.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func mapWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return customErr.ErrCategoryExists
+	}
+	return fmt.Errorf("%w: failed to update category: %v", customErr.ErrDatabase, err)
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCategory(s scanner) (*domain.Category, error) {
+	c := &domain.Category{}
+	var parentID sql.NullInt64
+	if err := s.Scan(&c.ID, &c.Name, &parentID, &c.Path, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		c.ParentID = &parentID.Int64
+	}
+	return c, nil
+}
+
+// GetAttributeSchema returns the custom attributes of the category with the
+// given path and of its ancestors. An attribute redefined by a subcategory
+// takes the subcategory's definition.
+func (r *CategoriesPostgresRepository) GetAttributeSchema(ctx context.Context, path string) (domain.AttributeSchema, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT * FROM (
+			SELECT DISTINCT ON (a.name) a.id, a.category_id, a.name, a.type, a.required, a.options, a.created_at, a.updated_at
+			FROM category_attributes a JOIN categories c ON c.id = a.category_id
+			WHERE c.path = $1 OR starts_with($1, c.path || '/')
+			ORDER BY a.name, length(c.path) DESC
+		) s ORDER BY name`, path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query attributes error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	schema := make(domain.AttributeSchema, 0)
+	for rows.Next() {
+		d := &domain.AttributeDefinition{}
+		var options []string
+		err := rows.Scan(&d.ID, &d.CategoryID, &d.Name, &d.Type, &d.Required, pq.Array(&options), &d.CreatedAt, &d.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan attribute error: %v", customErr.ErrDatabase, err)
+		}
+		d.Options = options
+		schema = append(schema, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return schema, nil
+}
+
+// SetAttribute defines a custom attribute of the category or replaces its
+// definition. Values already stored on items are checked against the new
+// definition the next time the item is written.
+func (r *CategoriesPostgresRepository) SetAttribute(ctx context.Context, d *domain.AttributeDefinition) error {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `
+		INSERT INTO category_attributes (category_id, name, type, required, options)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (category_id, name) DO UPDATE
+		SET type = EXCLUDED.type, required = EXCLUDED.required, options = EXCLUDED.options, updated_at = NOW()
+		RETURNING id, created_at, updated_at`,
+		d.CategoryID, d.Name, d.Type, d.Required, pq.Array(d.Options))
+	if err == nil {
+		err = row.Scan(&d.ID, &d.CreatedAt, &d.UpdatedAt)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return customErr.ErrCategoryNotFound
+		}
+		return fmt.Errorf("%w: failed to set attribute: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *CategoriesPostgresRepository) DeleteAttribute(ctx context.Context, categoryID int64, name string) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries,
+		`DELETE FROM category_attributes WHERE category_id = $1 AND name = $2`, categoryID, name)
+	if err != nil {
+		return fmt.Errorf("%w: delete attribute error: %v", customErr.ErrDatabase, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return customErr.ErrAttributeNotFound
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package exchange_rates_postgres
+
+import (
+	"context"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type ExchangeRatesPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *ExchangeRatesPostgresRepository {
+	return &ExchangeRatesPostgresRepository{db: db, retries: retries}
+}
+
+func (r *ExchangeRatesPostgresRepository) GetRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries,
+		`SELECT currency, rate, updated_by, updated_at FROM exchange_rates ORDER BY currency`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query exchange rates error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	rates := make([]*domain.ExchangeRate, 0)
+	for rows.Next() {
+		rate := &domain.ExchangeRate{}
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedBy, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%w: scan exchange rate error: %v", customErr.ErrDatabase, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return rates, nil
+}
+
+// SetRate inserts the rate of a currency or replaces the current one.
+func (r *ExchangeRatesPostgresRepository) SetRate(ctx context.Context, rate *domain.ExchangeRate) error {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `
+		INSERT INTO exchange_rates (currency, rate, updated_by) VALUES ($1, $2, $3)
+		ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at`, rate.Currency, rate.Rate, rate.UpdatedBy)
+	if err != nil {
+		return fmt.Errorf("%w: failed to set exchange rate: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&rate.UpdatedAt); err != nil {
+		return fmt.Errorf("%w: failed to set exchange rate: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *ExchangeRatesPostgresRepository) DeleteRate(ctx context.Context, currency string) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `DELETE FROM exchange_rates WHERE currency = $1`, currency)
+	if err != nil {
+		return fmt.Errorf("%w: delete failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return customErr.ErrExchangeRateNotFound
+	}
+	return nil
+}
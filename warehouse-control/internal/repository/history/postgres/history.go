@@ -2,10 +2,14 @@ package history_postgres
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
 	customErr "warehouse-control/internal/domain/errors"
 
 	"github.com/wb-go/wbf/dbpg"
@@ -25,6 +29,117 @@ func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *HistoryPostgres
 }
 
 func (r *HistoryPostgresRepository) GetHistory(ctx context.Context, filter domain.HistoryFilter) ([]*domain.HistoryRecord, int, error) {
+	whereClause, args := historyConditions(filter)
+	argIndex := len(args) + 1
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM items_history %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count history error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total history error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.HistoryRecord{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM items_history
+		%s
+		ORDER BY changed_at DESC
+		LIMIT $%d OFFSET $%d
+	`, historyColumns, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: select history error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := make([]*domain.HistoryRecord, 0, filter.Limit)
+	for rows.Next() {
+		rec, err := scanHistoryRecord(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: history rows error: %v", customErr.ErrDatabase, err)
+	}
+
+	return records, total, nil
+}
+
+// exportBatch is the number of rows fetched from the export cursor at a time.
+const exportBatch = 500
+
+// ExportHistory calls fn for every record matching the filter, newest first.
+// A zero limit exports all of them. The records are fetched in batches from a
+// cursor over a single snapshot, so no more than a batch is held at a time.
+// An error from fn stops the export and is returned as is.
+func (r *HistoryPostgresRepository) ExportHistory(ctx context.Context, filter domain.HistoryFilter, fn func(*domain.HistoryRecord) error) error {
+	whereClause, args := historyConditions(filter)
+	argIndex := len(args) + 1
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// LIMIT NULL means no limit.
+	query := fmt.Sprintf(`DECLARE history_export NO SCROLL CURSOR FOR
+		SELECT %s FROM items_history %s
+		ORDER BY changed_at DESC, id DESC
+		LIMIT NULLIF($%d, 0) OFFSET $%d`, historyColumns, whereClause, argIndex, argIndex+1)
+	if _, err := tx.ExecContext(ctx, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return fmt.Errorf("%w: declare export cursor error: %v", customErr.ErrDatabase, err)
+	}
+
+	for {
+		n, err := fetchExportBatch(ctx, tx, fn)
+		if err != nil {
+			return err
+		}
+		if n < exportBatch {
+			return nil
+		}
+	}
+}
+
+func fetchExportBatch(ctx context.Context, tx *sql.Tx, fn func(*domain.HistoryRecord) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH FORWARD %d FROM history_export", exportBatch))
+	if err != nil {
+		return 0, fmt.Errorf("%w: fetch export cursor error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	n := 0
+	for rows.Next() {
+		rec, err := scanHistoryRecord(rows)
+		if err != nil {
+			return 0, err
+		}
+		if err := fn(rec); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w: history rows error: %v", customErr.ErrDatabase, err)
+	}
+	return n, nil
+}
+
+// historyConditions builds the WHERE clause for a history filter.
+func historyConditions(filter domain.HistoryFilter) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
@@ -54,64 +169,52 @@ func (r *HistoryPostgresRepository) GetHistory(ctx context.Context, filter domai
 		args = append(args, *filter.DateTo)
 		argIndex++
 	}
+	if filter.StocktakeID != nil {
+		conditions = append(conditions, fmt.Sprintf("stocktake_id = $%d", argIndex))
+		args = append(args, *filter.StocktakeID)
+		argIndex++
+	}
 
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
+	return whereClause, args
+}
 
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM items_history %s", whereClause)
-	var total int
-	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+const historyColumns = `id, item_id, action, old_data, new_data, changed_by, changed_at, stocktake_id, attachment`
+
+func scanHistoryRecord(rows *sql.Rows) (*domain.HistoryRecord, error) {
+	rec := &domain.HistoryRecord{}
+	var oldData, newData, attachment []byte
+	var stocktakeID sql.NullInt64
+	err := rows.Scan(
+		&rec.ID,
+		&rec.ItemID,
+		&rec.Action,
+		&oldData,
+		&newData,
+		&rec.ChangedBy,
+		&rec.ChangedAt,
+		&stocktakeID,
+		&attachment,
+	)
 	if err != nil {
-		return nil, 0, fmt.Errorf("%w: count history error: %v", customErr.ErrDatabase, err)
+		return nil, fmt.Errorf("%w: scan history record: %v", customErr.ErrDatabase, err)
 	}
-	if err := row.Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("%w: scan total history error: %v", customErr.ErrDatabase, err)
-	}
-
-	if total == 0 {
-		return []*domain.HistoryRecord{}, 0, nil
+	if rec.OldData, err = decodeSnapshot(oldData); err != nil {
+		return nil, fmt.Errorf("%w: decode old data of history record %d: %v", customErr.ErrDatabase, rec.ID, err)
 	}
-
-	query := fmt.Sprintf(`
-		SELECT id, item_id, action, old_data, new_data, changed_by, changed_at
-		FROM items_history
-		%s
-		ORDER BY changed_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
-
-	finalArgs := append(args, filter.Limit, filter.Offset)
-	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("%w: select history error: %v", customErr.ErrDatabase, err)
+	if rec.NewData, err = decodeSnapshot(newData); err != nil {
+		return nil, fmt.Errorf("%w: decode new data of history record %d: %v", customErr.ErrDatabase, rec.ID, err)
 	}
-	defer func() { _ = rows.Close() }()
-
-	records := make([]*domain.HistoryRecord, 0, filter.Limit)
-	for rows.Next() {
-		rec := &domain.HistoryRecord{}
-		err := rows.Scan(
-			&rec.ID,
-			&rec.ItemID,
-			&rec.Action,
-			&rec.OldData,
-			&rec.NewData,
-			&rec.ChangedBy,
-			&rec.ChangedAt,
-		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("%w: scan history record: %v", customErr.ErrDatabase, err)
-		}
-		records = append(records, rec)
+	if stocktakeID.Valid {
+		rec.StocktakeID = &stocktakeID.Int64
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("%w: history rows error: %v", customErr.ErrDatabase, err)
+	if rec.Attachment, err = decodeAttachment(rec.ItemID, attachment); err != nil {
+		return nil, fmt.Errorf("%w: decode attachment of history record %d: %v", customErr.ErrDatabase, rec.ID, err)
 	}
-
-	return records, total, nil
+	return rec, nil
 }
 
 func (r *HistoryPostgresRepository) GetHistoryByItemID(ctx context.Context, itemID int64, limit, offset int) ([]*domain.HistoryRecord, error) {
@@ -125,3 +228,87 @@ func (r *HistoryPostgresRepository) GetHistoryByItemID(ctx context.Context, item
 	}
 	return records, nil
 }
+
+// itemSnapshot is an items row as the audit trigger logs it with
+// row_to_json. Prices are JSON numbers written from NUMERIC, so they decode
+// exactly.
+type itemSnapshot struct {
+	ID              int64              `json:"id"`
+	Name            string             `json:"name"`
+	SKU             string             `json:"sku"`
+	Quantity        int                `json:"quantity"`
+	Price           decimal.Decimal    `json:"price"`
+	Currency        string             `json:"currency"`
+	Category        string             `json:"category"`
+	Location        string             `json:"location"`
+	Serialized      bool               `json:"serialized"`
+	ReorderLevel    int                `json:"reorder_level"`
+	ReorderQuantity int                `json:"reorder_quantity"`
+	Quarantined     int                `json:"quarantine_quantity"`
+	Damaged         int                `json:"damaged_quantity"`
+	Blocked         int                `json:"blocked_quantity"`
+	CostMethod      *domain.CostMethod `json:"cost_method"`
+	BaseUnit        string             `json:"base_unit"`
+	ProductID       *int64             `json:"product_id"`
+	Variant         map[string]string  `json:"variant"`
+	Attributes      map[string]any     `json:"attributes"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+func decodeSnapshot(data []byte) (*domain.Item, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var s itemSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &domain.Item{
+		ID:              s.ID,
+		Name:            s.Name,
+		SKU:             s.SKU,
+		Quantity:        s.Quantity,
+		Price:           domain.Money{Amount: s.Price, Currency: strings.TrimSpace(s.Currency)},
+		Category:        s.Category,
+		Location:        s.Location,
+		Serialized:      s.Serialized,
+		ReorderLevel:    s.ReorderLevel,
+		ReorderQuantity: s.ReorderQuantity,
+		Quarantined:     s.Quarantined,
+		Damaged:         s.Damaged,
+		Blocked:         s.Blocked,
+		CostMethod:      s.CostMethod,
+		BaseUnit:        s.BaseUnit,
+		ProductID:       s.ProductID,
+		Variant:         s.Variant,
+		Attributes:      s.Attributes,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}, nil
+}
+
+// attachmentSnapshot is an attachment as the attachments trigger logs it.
+type attachmentSnapshot struct {
+	ID          int64  `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+func decodeAttachment(itemID int64, data []byte) (*domain.Attachment, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var s attachmentSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &domain.Attachment{
+		ID:          s.ID,
+		ItemID:      itemID,
+		FileName:    s.FileName,
+		ContentType: s.ContentType,
+		Size:        s.Size,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package imports_postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const jobColumns = `
+	SELECT id, file_name, format, mode, dry_run, status, total_rows, processed_rows,
+	       created_count, updated_count, failed_count, errors, message, created_by,
+	       created_at, started_at, finished_at
+	FROM item_import_jobs`
+
+// rowError is the stored form of a row error.
+type rowError struct {
+	Row   int    `json:"row"`
+	SKU   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+type ImportsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *ImportsPostgresRepository {
+	return &ImportsPostgresRepository{db: db, retries: retries}
+}
+
+func (r *ImportsPostgresRepository) CreateImportJob(ctx context.Context, j *domain.ImportJob) error {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `
+		INSERT INTO item_import_jobs (file_name, format, mode, dry_run, status, total_rows, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`,
+		j.FileName, j.Format, j.Mode, j.DryRun, j.Status, j.TotalRows, j.CreatedBy)
+	if err == nil {
+		err = row.Scan(&j.ID, &j.CreatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert import job: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+// UpdateImportJob saves the status and progress of a job.
+func (r *ImportsPostgresRepository) UpdateImportJob(ctx context.Context, j *domain.ImportJob) error {
+	stored := make([]rowError, len(j.Errors))
+	for i, e := range j.Errors {
+		stored[i] = rowError{Row: e.Row, SKU: e.SKU, Error: e.Error}
+	}
+	rowErrors, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encode row errors: %w", err)
+	}
+	_, err = r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE item_import_jobs
+		SET status = $2, processed_rows = $3, created_count = $4, updated_count = $5, failed_count = $6,
+		    errors = $7, message = $8, started_at = $9, finished_at = $10
+		WHERE id = $1`,
+		j.ID, j.Status, j.ProcessedRows, j.Created, j.Updated, j.Failed, rowErrors, j.Message, j.StartedAt, j.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update import job: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *ImportsPostgresRepository) GetImportJob(ctx context.Context, id int64) (*domain.ImportJob, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, jobColumns+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	j := &domain.ImportJob{}
+	var rowErrors []byte
+	var startedAt, finishedAt sql.NullTime
+	err = row.Scan(&j.ID, &j.FileName, &j.Format, &j.Mode, &j.DryRun, &j.Status, &j.TotalRows, &j.ProcessedRows,
+		&j.Created, &j.Updated, &j.Failed, &rowErrors, &j.Message, &j.CreatedBy,
+		&j.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrImportNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if startedAt.Valid {
+		j.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		j.FinishedAt = &finishedAt.Time
+	}
+	var stored []rowError
+	if err := json.Unmarshal(rowErrors, &stored); err != nil {
+		return nil, fmt.Errorf("decode row errors: %w", err)
+	}
+	j.Errors = make([]*domain.ImportRowError, len(stored))
+	for i, e := range stored {
+		j.Errors[i] = &domain.ImportRowError{Row: e.Row, SKU: e.SKU, Error: e.Error}
+	}
+	return j, nil
+}
+
+// FailUnfinishedImportJobs marks the jobs left queued or running by a
+// previous process as failed: their files were only held in memory.
+func (r *ImportsPostgresRepository) FailUnfinishedImportJobs(ctx context.Context, message string) (int64, error) {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE item_import_jobs SET status = 'failed', message = $1, finished_at = NOW()
+		WHERE status IN ('queued', 'running')`, message)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to fail unfinished import jobs: %v", customErr.ErrDatabase, err)
+	}
+	return res.RowsAffected()
+}
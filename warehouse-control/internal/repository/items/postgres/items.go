@@ -3,24 +3,59 @@ package items_postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"warehouse-control/internal/domain"
 	customErr "warehouse-control/internal/domain/errors"
 
+	"github.com/lib/pq"
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/retry"
 )
 
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// reservedColumn sums the stock held by active reservations of an item row.
+const reservedColumn = `COALESCE((SELECT SUM(r.quantity - r.fulfilled_quantity) FROM reservations r
+	WHERE r.item_id = items.id AND r.status = 'active' AND r.expires_at > NOW()), 0)`
+
+// AvailableColumn is the stock of an item row free to reserve and issue:
+// the on-hand quantity less stock held in other statuses and by active
+// reservations, as domain.Item.Available counts it.
+const AvailableColumn = `(items.quantity - items.quarantine_quantity - items.damaged_quantity - items.blocked_quantity - ` + reservedColumn + `)`
+
+// costColumn is the average cost of the stock on hand of an item row.
+const costColumn = `COALESCE((SELECT ROUND(SUM(c.remaining * c.unit_cost) / SUM(c.remaining), 4) FROM cost_layers c
+	WHERE c.item_id = items.id AND c.remaining > 0), 0)`
+
+// statusColumns maps the stock statuses held out of availability to the
+// items columns that count them.
+var statusColumns = map[domain.StockStatus]string{
+	domain.StockQuarantine: "quarantine_quantity",
+	domain.StockDamaged:    "damaged_quantity",
+	domain.StockBlocked:    "blocked_quantity",
+}
+
+// costBooker books the cost of a stock movement inserted within tx.
+type costBooker interface {
+	ApplyCost(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error
+}
+
 type ItemsPostgresRepository struct {
 	db      *dbpg.DB
 	retries retry.Strategy
+	costs   costBooker
 }
 
-func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *ItemsPostgresRepository {
-	return &ItemsPostgresRepository{db: db, retries: retries}
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, costs costBooker) *ItemsPostgresRepository {
+	return &ItemsPostgresRepository{db: db, retries: retries, costs: costs}
 }
 
 func (r *ItemsPostgresRepository) CreateItem(ctx context.Context, item *domain.Item, username string) (int64, error) {
@@ -34,15 +69,9 @@ func (r *ItemsPostgresRepository) CreateItem(ctx context.Context, item *domain.I
 		return 0, err
 	}
 
-	query := `INSERT INTO items (name, sku, quantity, price, category, location) 
-              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
-
-	var id int64
-	err = tx.QueryRowContext(ctx, query,
-		item.Name, item.SKU, item.Quantity, item.Price, item.Category, item.Location,
-	).Scan(&id)
+	id, err := r.createItem(ctx, tx, item, username)
 	if err != nil {
-		return 0, fmt.Errorf("%w: failed to insert item: %v", customErr.ErrDatabase, err)
+		return 0, err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -52,22 +81,95 @@ func (r *ItemsPostgresRepository) CreateItem(ctx context.Context, item *domain.I
 	return id, nil
 }
 
-func (r *ItemsPostgresRepository) GetItems(ctx context.Context, limit, offset int, search string) ([]*domain.Item, int, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+// CreateItems creates the items in a single transaction, returning their IDs
+// in order. When an item fails nothing is written and the error is a
+// *domain.BulkItemError naming it.
+func (r *ItemsPostgresRepository) CreateItems(ctx context.Context, items []*domain.Item, username string) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
 
-	if search != "" {
-		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR sku ILIKE $%d)", argIndex, argIndex))
-		args = append(args, "%"+search+"%")
-		argIndex++
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return nil, err
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		if ids[i], err = r.createItem(ctx, tx, item, username); err != nil {
+			return nil, &domain.BulkItemError{Index: i, Err: err}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
 	}
 
+	return ids, nil
+}
+
+func (r *ItemsPostgresRepository) createItem(ctx context.Context, tx *sql.Tx, item *domain.Item, username string) (int64, error) {
+	attrs, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO items (name, sku, quantity, price, currency, category, location, serialized, reorder_level, reorder_quantity, cost_method, base_unit, attributes) 
+              VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+
+	var id int64
+	err = tx.QueryRowContext(ctx, query,
+		item.Name, item.SKU, item.Quantity, item.Price.Amount, item.Price.Currency, item.Category, item.Location, item.Serialized,
+		item.ReorderLevel, item.ReorderQuantity, item.CostMethod, item.BaseUnit, attrs,
+	).Scan(&id)
+	if err != nil {
+		if refErr := referenceError(err, item); refErr != nil {
+			return 0, refErr
+		}
+		if isUniqueViolation(err) {
+			return 0, customErr.ErrItemExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert item: %v", customErr.ErrDatabase, err)
+	}
+
+	if item.Quantity != 0 {
+		receipt := &domain.StockMovement{
+			ItemID:     id,
+			Type:       domain.MovementReceipt,
+			Quantity:   item.Quantity,
+			ReasonCode: "initial_stock",
+			ToLocation: item.Location,
+			UnitCost:   &item.UnitCost,
+		}
+		// Initial stock goes straight into the bin when the location names one.
+		var binID int64
+		err := tx.QueryRowContext(ctx,
+			`SELECT id FROM locations WHERE path = $1 AND location_type = 'bin'`, item.Location,
+		).Scan(&binID)
+		switch {
+		case err == nil:
+			receipt.ToLocationID = &binID
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO item_stock (item_id, location_id, quantity) VALUES ($1, $2, $3)`, id, binID, item.Quantity)
+			if err != nil {
+				return 0, fmt.Errorf("%w: failed to insert bin stock: %v", customErr.ErrDatabase, err)
+			}
+		case !errors.Is(err, sql.ErrNoRows):
+			return 0, fmt.Errorf("%w: failed to resolve location: %v", customErr.ErrDatabase, err)
+		}
+		if err := r.insertMovement(ctx, tx, receipt, username); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+func (r *ItemsPostgresRepository) GetItems(ctx context.Context, filter domain.ItemFilter) ([]*domain.Item, int, error) {
+	whereClause, args, warehouseColumn := itemConditions(filter)
+	argIndex := len(args) + 1
+
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM items %s", whereClause)
 	var total int
 	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
@@ -83,24 +185,23 @@ func (r *ItemsPostgresRepository) GetItems(ctx context.Context, limit, offset in
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, name, sku, quantity, price, category, location, created_at, updated_at 
+		SELECT %s
 		FROM items %s 
 		ORDER BY created_at DESC 
-		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+		LIMIT $%d OFFSET $%d`, listColumns(warehouseColumn), whereClause, argIndex, argIndex+1)
 
-	finalArgs := append(args, limit, offset)
+	finalArgs := append(args, filter.Limit, filter.Offset)
 	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("%w: query items error: %v", customErr.ErrDatabase, err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	items := make([]*domain.Item, 0, limit)
+	items := make([]*domain.Item, 0, filter.Limit)
 	for rows.Next() {
-		i := &domain.Item{}
-		err := rows.Scan(&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price, &i.Category, &i.Location, &i.CreatedAt, &i.UpdatedAt)
+		i, err := scanListedItem(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("%w: scan item error: %v", customErr.ErrDatabase, err)
+			return nil, 0, err
 		}
 		items = append(items, i)
 	}
@@ -112,25 +213,283 @@ func (r *ItemsPostgresRepository) GetItems(ctx context.Context, limit, offset in
 	return items, total, nil
 }
 
+// exportBatch is the number of rows fetched from the export cursor at a time.
+const exportBatch = 500
+
+// ExportItems calls fn for every item matching the filter, in ID order,
+// ignoring its limit and offset. The items are fetched in batches from a
+// cursor over a single snapshot, so no more than a batch is held at a time.
+// An error from fn stops the export and is returned as is.
+func (r *ItemsPostgresRepository) ExportItems(ctx context.Context, filter domain.ItemFilter, fn func(*domain.Item) error) error {
+	whereClause, args, warehouseColumn := itemConditions(filter)
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`DECLARE items_export NO SCROLL CURSOR FOR
+		SELECT %s FROM items %s ORDER BY id`, listColumns(warehouseColumn), whereClause)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%w: declare export cursor error: %v", customErr.ErrDatabase, err)
+	}
+
+	for {
+		n, err := r.fetchExportBatch(ctx, tx, fn)
+		if err != nil {
+			return err
+		}
+		if n < exportBatch {
+			return nil
+		}
+	}
+}
+
+func (r *ItemsPostgresRepository) fetchExportBatch(ctx context.Context, tx *sql.Tx, fn func(*domain.Item) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH FORWARD %d FROM items_export", exportBatch))
+	if err != nil {
+		return 0, fmt.Errorf("%w: fetch export cursor error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	n := 0
+	for rows.Next() {
+		item, err := scanListedItem(rows)
+		if err != nil {
+			return 0, err
+		}
+		if err := fn(item); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return n, nil
+}
+
+// listColumns is the select list read by scanListedItem.
+func listColumns(warehouseColumn string) string {
+	return `id, name, sku, quantity, quarantine_quantity, damaged_quantity, blocked_quantity, price, currency, COALESCE(category, ''), location, serialized, reorder_level, reorder_quantity, cost_method, base_unit, product_id, variant, attributes, created_at, updated_at, ` +
+		reservedColumn + `, ` + costColumn + `, ` + warehouseColumn
+}
+
+func scanListedItem(rows *sql.Rows) (*domain.Item, error) {
+	i := &domain.Item{}
+	var warehouseQuantity, productID sql.NullInt64
+	var costMethod sql.NullString
+	var variant, attrs []byte
+	err := rows.Scan(&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Quarantined, &i.Damaged, &i.Blocked, &i.Price.Amount, &i.Price.Currency, &i.Category, &i.Location, &i.Serialized, &i.ReorderLevel, &i.ReorderQuantity, &costMethod, &i.BaseUnit, &productID, &variant, &attrs, &i.CreatedAt, &i.UpdatedAt, &i.Reserved, &i.UnitCost, &warehouseQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("%w: scan item error: %v", customErr.ErrDatabase, err)
+	}
+	if costMethod.Valid {
+		m := domain.CostMethod(costMethod.String)
+		i.CostMethod = &m
+	}
+	if err := setVariant(i, productID, variant); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(attrs, &i.Attributes); err != nil {
+		return nil, fmt.Errorf("decode attributes: %w", err)
+	}
+	if warehouseQuantity.Valid {
+		q := int(warehouseQuantity.Int64)
+		i.WarehouseQuantity = &q
+	}
+	return i, nil
+}
+
+// GetStockTotals sums the stock of the items matching the filter by status.
+// Statuses are tracked per item, so with a warehouse filter the totals still
+// cover all stock of the matching items.
+func (r *ItemsPostgresRepository) GetStockTotals(ctx context.Context, filter domain.ItemFilter) (*domain.StockTotals, error) {
+	whereClause, args, _ := itemConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(SUM(quantity), 0), COALESCE(SUM(reserved), 0),
+		       COALESCE(SUM(quarantine_quantity), 0), COALESCE(SUM(damaged_quantity), 0), COALESCE(SUM(blocked_quantity), 0)
+		FROM (SELECT quantity, quarantine_quantity, damaged_quantity, blocked_quantity, %s AS reserved
+		      FROM items %s) t`, reservedColumn, whereClause)
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: stock totals error: %v", customErr.ErrDatabase, err)
+	}
+	totals := &domain.StockTotals{}
+	if err := row.Scan(&totals.OnHand, &totals.Reserved, &totals.Quarantine, &totals.Damaged, &totals.Blocked); err != nil {
+		return nil, fmt.Errorf("%w: scan stock totals error: %v", customErr.ErrDatabase, err)
+	}
+	totals.Available = totals.OnHand - totals.Reserved - totals.Quarantine - totals.Damaged - totals.Blocked
+	return totals, nil
+}
+
+// itemConditions builds the WHERE clause for an item filter. With a
+// warehouse filter it also returns the column with the item's stock in that
+// warehouse.
+func itemConditions(filter domain.ItemFilter) (string, []interface{}, string) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR sku ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+filter.Search+"%")
+		argIndex++
+	}
+
+	warehouseColumn := "NULL::INT"
+	if filter.WarehouseID != nil {
+		stockInWarehouse := fmt.Sprintf(`FROM item_stock s JOIN locations l ON l.id = s.location_id
+			WHERE s.item_id = items.id AND l.warehouse_id = $%d AND s.quantity > 0`, argIndex)
+		conditions = append(conditions, "EXISTS (SELECT 1 "+stockInWarehouse+")")
+		warehouseColumn = "(SELECT SUM(s.quantity)::INT " + stockInWarehouse + ")"
+		args = append(args, *filter.WarehouseID)
+		argIndex++
+	}
+
+	if filter.ProductID != nil {
+		conditions = append(conditions, fmt.Sprintf("product_id = $%d", argIndex))
+		args = append(args, *filter.ProductID)
+		argIndex++
+	}
+
+	if filter.CategoryID != nil {
+		conditions = append(conditions, fmt.Sprintf(`category IN (SELECT d.path FROM categories c
+			JOIN categories d ON d.path = c.path OR starts_with(d.path, c.path || '/') WHERE c.id = $%d)`, argIndex))
+		args = append(args, *filter.CategoryID)
+		argIndex++
+	}
+
+	// Attributes are matched in name order so the query text is stable.
+	names := make([]string, 0, len(filter.Attributes))
+	for name := range filter.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		conditions = append(conditions, fmt.Sprintf("attributes->>$%d = $%d", argIndex, argIndex+1))
+		args = append(args, name, filter.Attributes[name])
+		argIndex += 2
+	}
+
+	if filter.Status != nil {
+		if column, ok := statusColumns[*filter.Status]; ok {
+			conditions = append(conditions, column+" > 0")
+		} else {
+			conditions = append(conditions,
+				"quantity - quarantine_quantity - damaged_quantity - blocked_quantity - "+reservedColumn+" > 0")
+		}
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	return whereClause, args, warehouseColumn
+}
+
 func (r *ItemsPostgresRepository) GetItemByID(ctx context.Context, id int64) (*domain.Item, error) {
-	query := `SELECT id, name, sku, quantity, price, category, location, created_at, updated_at FROM items WHERE id = $1`
-	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, id)
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, itemQuery, id)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
 	}
+	item, err := scanItem(row)
+	if err != nil {
+		return nil, err
+	}
 
+	item.Stock, err = r.getItemStock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// itemQuery reads an item row by ID for scanItem.
+const itemQuery = `SELECT id, name, sku, quantity, quarantine_quantity, damaged_quantity, blocked_quantity, price, currency, COALESCE(category, ''), location, serialized, reorder_level, reorder_quantity, cost_method, base_unit, product_id, variant, attributes, created_at, updated_at, ` + reservedColumn + `, ` + costColumn + ` FROM items WHERE id = $1`
+
+func scanItem(row *sql.Row) (*domain.Item, error) {
 	item := &domain.Item{}
-	err = row.Scan(&item.ID, &item.Name, &item.SKU, &item.Quantity, &item.Price, &item.Category, &item.Location, &item.CreatedAt, &item.UpdatedAt)
+	var costMethod sql.NullString
+	var productID sql.NullInt64
+	var variant, attrs []byte
+	err := row.Scan(&item.ID, &item.Name, &item.SKU, &item.Quantity, &item.Quarantined, &item.Damaged, &item.Blocked, &item.Price.Amount, &item.Price.Currency, &item.Category, &item.Location, &item.Serialized, &item.ReorderLevel, &item.ReorderQuantity, &costMethod, &item.BaseUnit, &productID, &variant, &attrs, &item.CreatedAt, &item.UpdatedAt, &item.Reserved, &item.UnitCost)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, customErr.ErrItemNotFound
 		}
 		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
 	}
+	if costMethod.Valid {
+		m := domain.CostMethod(costMethod.String)
+		item.CostMethod = &m
+	}
+	if err := setVariant(item, productID, variant); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(attrs, &item.Attributes); err != nil {
+		return nil, fmt.Errorf("decode attributes: %w", err)
+	}
 	return item, nil
 }
 
-func (r *ItemsPostgresRepository) UpdateItem(ctx context.Context, id int64, item *domain.Item, username string) error {
+// GetItemIDsBySKU returns the IDs of the items with the given SKUs, keyed by
+// SKU; unknown SKUs are left out.
+func (r *ItemsPostgresRepository) GetItemIDsBySKU(ctx context.Context, skus []string) (map[string]int64, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `SELECT id, sku FROM items WHERE sku = ANY($1)`, pq.Array(skus))
+	if err != nil {
+		return nil, fmt.Errorf("%w: query items by SKU error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make(map[string]int64, len(skus))
+	for rows.Next() {
+		var id int64
+		var sku string
+		if err := rows.Scan(&id, &sku); err != nil {
+			return nil, fmt.Errorf("%w: scan item error: %v", customErr.ErrDatabase, err)
+		}
+		ids[sku] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return ids, nil
+}
+
+func (r *ItemsPostgresRepository) getItemStock(ctx context.Context, itemID int64) ([]*domain.ItemStock, error) {
+	query := `
+		SELECT s.location_id, l.path, l.warehouse_id, s.quantity
+		FROM item_stock s
+		JOIN locations l ON l.id = s.location_id
+		WHERE s.item_id = $1 AND s.quantity > 0
+		ORDER BY l.path`
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query item stock error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	stock := make([]*domain.ItemStock, 0)
+	for rows.Next() {
+		st := &domain.ItemStock{ItemID: itemID}
+		if err := rows.Scan(&st.LocationID, &st.LocationPath, &st.WarehouseID, &st.Quantity); err != nil {
+			return nil, fmt.Errorf("%w: scan item stock error: %v", customErr.ErrDatabase, err)
+		}
+		stock = append(stock, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: item stock rows error: %v", customErr.ErrDatabase, err)
+	}
+	return stock, nil
+}
+
+// UpdateItem locks and reads the item within a transaction and hands it to
+// patch, which changes it in place; the item is then written as patch left
+// it. An error from patch is returned as it is, and nothing is written.
+func (r *ItemsPostgresRepository) UpdateItem(ctx context.Context, id int64, patch func(item *domain.Item) error, username string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -141,9 +500,116 @@ func (r *ItemsPostgresRepository) UpdateItem(ctx context.Context, id int64, item
 		return err
 	}
 
-	query := `UPDATE items SET name=$1, sku=$2, quantity=$3, price=$4, category=$5, location=$6, updated_at=NOW() WHERE id=$7`
-	res, err := tx.ExecContext(ctx, query, item.Name, item.SKU, item.Quantity, item.Price, item.Category, item.Location, id)
+	if err := r.patchItem(ctx, tx, id, patch, username); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateItems updates the items with the given IDs in a single transaction,
+// in order. Each item is locked and read within the transaction and handed to
+// patch, which changes it in place; the item is then written as patch left
+// it. When an item fails, or patch returns an error for it, nothing is
+// written and the error is a *domain.BulkItemError naming it.
+func (r *ItemsPostgresRepository) UpdateItems(ctx context.Context, ids []int64, patch func(i int, item *domain.Item) error, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		err := r.patchItem(ctx, tx, id, func(item *domain.Item) error { return patch(i, item) }, username)
+		if err != nil {
+			return &domain.BulkItemError{Index: i, Err: err}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// patchItem reads the item under a row lock, so that the fields patch leaves
+// alone keep their current values, and writes it back as patch left it.
+func (r *ItemsPostgresRepository) patchItem(ctx context.Context, tx *sql.Tx, id int64, patch func(item *domain.Item) error, username string) error {
+	item, err := scanItem(tx.QueryRowContext(ctx, itemQuery+" FOR UPDATE", id))
+	if err != nil {
+		return err
+	}
+	if err := patch(item); err != nil {
+		return err
+	}
+	return r.updateItem(ctx, tx, id, item, username)
+}
+
+func (r *ItemsPostgresRepository) updateItem(ctx context.Context, tx *sql.Tx, id int64, item *domain.Item, username string) error {
+	var oldQuantity, segregated, allocated, reserved int
+	var oldSerialized bool
+	var oldBaseUnit string
+	err := tx.QueryRowContext(ctx,
+		`SELECT quantity, quarantine_quantity + damaged_quantity + blocked_quantity, serialized, base_unit, `+reservedColumn+`
+		 FROM items WHERE id = $1 FOR UPDATE`, id,
+	).Scan(&oldQuantity, &segregated, &oldSerialized, &oldBaseUnit, &reserved)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrItemNotFound
+		}
+		return fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT GREATEST(
+		    (SELECT COALESCE(SUM(quantity), 0) FROM item_stock WHERE item_id = $1),
+		    (SELECT COALESCE(SUM(quantity), 0) FROM lots WHERE item_id = $1))`, id,
+	).Scan(&allocated)
 	if err != nil {
+		return fmt.Errorf("%w: failed to sum bin and lot stock: %v", customErr.ErrDatabase, err)
+	}
+	// Stock held in bins or lots can only be reduced through movements that
+	// name them, and reserved stock or stock held in another status cannot be
+	// edited away.
+	if item.Quantity < oldQuantity && (item.Quantity < allocated || item.Quantity < reserved+segregated) {
+		return customErr.ErrInsufficientStock
+	}
+
+	// Serial numbers can only be named by movements, and stock received
+	// without them cannot be serialized after the fact.
+	if item.Quantity != oldQuantity && (oldSerialized || item.Serialized) {
+		return fmt.Errorf("%w: quantity of a serialized item changes through movements", customErr.ErrInvalidInput)
+	}
+	if item.Serialized != oldSerialized && oldQuantity != 0 {
+		return fmt.Errorf("%w: serial tracking can only be changed while the item has no stock", customErr.ErrInvalidInput)
+	}
+	// Stock on hand is counted in the base unit, and the conversions of the
+	// other units are relative to it.
+	if item.BaseUnit != oldBaseUnit {
+		if oldQuantity != 0 || item.Quantity != 0 {
+			return fmt.Errorf("%w: base unit can only be changed while the item has no stock", customErr.ErrInvalidInput)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM item_units WHERE item_id = $1`, id); err != nil {
+			return fmt.Errorf("%w: failed to drop unit conversions: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	attrs, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE items SET name=$1, sku=$2, quantity=$3, price=$4, currency=$5, category=NULLIF($6, ''), location=$7, serialized=$8,
+              reorder_level=$9, reorder_quantity=$10, cost_method=$11, base_unit=$12, attributes=$13, updated_at=NOW() WHERE id=$14`
+	res, err := tx.ExecContext(ctx, query, item.Name, item.SKU, item.Quantity, item.Price.Amount, item.Price.Currency, item.Category, item.Location, item.Serialized,
+		item.ReorderLevel, item.ReorderQuantity, item.CostMethod, item.BaseUnit, attrs, id)
+	if err != nil {
+		if refErr := referenceError(err, item); refErr != nil {
+			return refErr
+		}
+		if isUniqueViolation(err) {
+			return customErr.ErrItemExists
+		}
 		return fmt.Errorf("%w: update failed: %v", customErr.ErrDatabase, err)
 	}
 
@@ -152,10 +618,26 @@ func (r *ItemsPostgresRepository) UpdateItem(ctx context.Context, id int64, item
 		return customErr.ErrItemNotFound
 	}
 
-	return tx.Commit()
+	// A direct quantity edit is booked as an adjustment so the ledger stays in sync.
+	if item.Quantity != oldQuantity {
+		err := r.insertMovement(ctx, tx, &domain.StockMovement{
+			ItemID:     id,
+			Type:       domain.MovementAdjustment,
+			Quantity:   item.Quantity - oldQuantity,
+			ReasonCode: "manual_update",
+		}, username)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (r *ItemsPostgresRepository) DeleteItem(ctx context.Context, id int64, username string) error {
+// ChangeStockStatus moves stock of an item between statuses. The on-hand
+// quantity does not change; the items trigger records the move in
+// items_history under the given user.
+func (r *ItemsPostgresRepository) ChangeStockStatus(ctx context.Context, change *domain.StockStatusChange, username string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -166,32 +648,99 @@ func (r *ItemsPostgresRepository) DeleteItem(ctx context.Context, id int64, user
 		return err
 	}
 
+	item := &domain.Item{ID: change.ItemID}
+	err = tx.QueryRowContext(ctx,
+		`SELECT quantity, quarantine_quantity, damaged_quantity, blocked_quantity, `+reservedColumn+`
+		 FROM items WHERE id = $1 FOR UPDATE`, change.ItemID,
+	).Scan(&item.Quantity, &item.Quarantined, &item.Damaged, &item.Blocked, &item.Reserved)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrItemNotFound
+		}
+		return fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+
+	// Reserved stock stays available: it cannot be moved into another status.
+	held := item.StatusQuantity(change.From)
+	if change.From == domain.StockAvailable {
+		held = item.Available()
+	}
+	if held < change.Quantity {
+		return fmt.Errorf("%w: %d in %s status, %d to move", customErr.ErrInsufficientStock, held, change.From, change.Quantity)
+	}
+
+	var sets []string
+	if column, ok := statusColumns[change.From]; ok {
+		sets = append(sets, fmt.Sprintf("%s = %s - $1", column, column))
+	}
+	if column, ok := statusColumns[change.To]; ok {
+		sets = append(sets, fmt.Sprintf("%s = %s + $1", column, column))
+	}
+	query := fmt.Sprintf(`UPDATE items SET %s, updated_at = NOW() WHERE id = $2`, strings.Join(sets, ", "))
+	if _, err := tx.ExecContext(ctx, query, change.Quantity, change.ItemID); err != nil {
+		return fmt.Errorf("%w: failed to change stock status: %v", customErr.ErrDatabase, err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteItem deletes the item together with its attachments and returns the
+// attachments, whose stored files are left to the caller.
+func (r *ItemsPostgresRepository) DeleteItem(ctx context.Context, id int64, username string) ([]*domain.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return nil, err
+	}
+
+	attachments, err := deleteAttachments(ctx, tx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := tx.ExecContext(ctx, `DELETE FROM items WHERE id=$1`, id)
 	if err != nil {
-		return fmt.Errorf("%w: delete failed: %v", customErr.ErrDatabase, err)
+		if refErr := deleteReferenceError(err); refErr != nil {
+			return nil, refErr
+		}
+		return nil, fmt.Errorf("%w: delete failed: %v", customErr.ErrDatabase, err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil || rows == 0 {
-		return customErr.ErrItemNotFound
+		return nil, customErr.ErrItemNotFound
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return attachments, nil
 }
 
-func (r *ItemsPostgresRepository) BulkDeleteItems(ctx context.Context, ids []int64, username string) error {
+// BulkDeleteItems deletes the items together with their attachments and
+// returns the attachments, whose stored files are left to the caller.
+func (r *ItemsPostgresRepository) BulkDeleteItems(ctx context.Context, ids []int64, username string) ([]*domain.Attachment, error) {
 	if len(ids) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
 	if err := r.setAuditUser(ctx, tx, username); err != nil {
-		return err
+		return nil, err
+	}
+
+	attachments, err := deleteAttachments(ctx, tx, ids)
+	if err != nil {
+		return nil, err
 	}
 
 	placeholders := make([]string, len(ids))
@@ -204,16 +753,118 @@ func (r *ItemsPostgresRepository) BulkDeleteItems(ctx context.Context, ids []int
 	query := fmt.Sprintf("DELETE FROM items WHERE id IN (%s)", strings.Join(placeholders, ","))
 	_, err = tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("%w: bulk delete failed: %v", customErr.ErrDatabase, err)
+		if refErr := deleteReferenceError(err); refErr != nil {
+			return nil, refErr
+		}
+		return nil, fmt.Errorf("%w: bulk delete failed: %v", customErr.ErrDatabase, err)
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return attachments, nil
+}
+
+// deleteAttachments deletes the attachments of the items within tx; their
+// trigger records the removals in the items' history.
+func deleteAttachments(ctx context.Context, tx *sql.Tx, itemIDs []int64) ([]*domain.Attachment, error) {
+	rows, err := tx.QueryContext(ctx,
+		`DELETE FROM item_attachments WHERE item_id = ANY($1) RETURNING id, item_id, storage_key, COALESCE(thumbnail_key, '')`,
+		pq.Array(itemIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to delete attachments: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attachments []*domain.Attachment
+	for rows.Next() {
+		a := &domain.Attachment{}
+		if err := rows.Scan(&a.ID, &a.ItemID, &a.StorageKey, &a.ThumbnailKey); err != nil {
+			return nil, fmt.Errorf("%w: scan attachment error: %v", customErr.ErrDatabase, err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: attachment rows error: %v", customErr.ErrDatabase, err)
+	}
+	return attachments, nil
 }
 
 func (r *ItemsPostgresRepository) setAuditUser(ctx context.Context, tx *sql.Tx, username string) error {
-	_, err := tx.ExecContext(ctx, "SET LOCAL warehouse_control.changed_by = $1", username)
+	_, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username)
 	if err != nil {
 		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
 	}
 	return nil
 }
+
+// encodeAttributes encodes custom attribute values for the attributes
+// column, which holds an empty object rather than null.
+func encodeAttributes(attrs map[string]any) (string, error) {
+	if len(attrs) == 0 {
+		return "{}", nil
+	}
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		return "", fmt.Errorf("encode attributes: %w", err)
+	}
+	return string(data), nil
+}
+
+// setVariant sets the product and attribute values of a variant item.
+func setVariant(item *domain.Item, productID sql.NullInt64, variant []byte) error {
+	if !productID.Valid {
+		return nil
+	}
+	item.ProductID = &productID.Int64
+	if variant != nil {
+		if err := json.Unmarshal(variant, &item.Variant); err != nil {
+			return fmt.Errorf("decode variant: %w", err)
+		}
+	}
+	return nil
+}
+
+// deleteReferenceError explains why an item cannot be deleted: it is still
+// a kit component. It returns nil for any other error.
+func deleteReferenceError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != foreignKeyViolation {
+		return nil
+	}
+	return fmt.Errorf("%w: the item is a component of a kit", customErr.ErrInvalidInput)
+}
+
+// referenceError explains a foreign key an insert or update of the item
+// broke: its category or base unit does not exist. It returns nil for any
+// other error.
+func referenceError(err error, item *domain.Item) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != foreignKeyViolation {
+		return nil
+	}
+	if pqErr.Constraint == "items_category_fkey" {
+		return fmt.Errorf("%w: unknown category %q", customErr.ErrInvalidInput, item.Category)
+	}
+	return fmt.Errorf("%w: unknown unit %q", customErr.ErrInvalidInput, item.BaseUnit)
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+func (r *ItemsPostgresRepository) insertMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error {
+	query := `INSERT INTO stock_movements (item_id, movement_type, quantity, reason_code, reference,
+                  from_location_id, to_location_id, from_location, to_location, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	err := tx.QueryRowContext(ctx, query,
+		m.ItemID, m.Type, m.Quantity, m.ReasonCode, m.Reference,
+		m.FromLocationID, m.ToLocationID, m.FromLocation, m.ToLocation, username,
+	).Scan(&m.ID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert movement: %v", customErr.ErrDatabase, err)
+	}
+	return r.costs.ApplyCost(ctx, tx, m)
+}
@@ -0,0 +1,224 @@
+package kits_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// componentsQuery lists the components of the kit $1 with their available
+// stock: on hand, less what other statuses and active reservations hold.
+const componentsQuery = `
+	SELECT k.kit_item_id, items.id, items.sku, items.name, k.quantity,
+	       items.quantity - items.quarantine_quantity - items.damaged_quantity - items.blocked_quantity -
+	       COALESCE((SELECT SUM(r.quantity - r.fulfilled_quantity) FROM reservations r
+	                 WHERE r.item_id = items.id AND r.status = 'active' AND r.expires_at > NOW()), 0)
+	FROM kit_components k
+	JOIN items ON items.id = k.component_item_id
+	WHERE k.kit_item_id = $1
+	ORDER BY items.id`
+
+// movementApplier books stock movements inside a caller's transaction.
+type movementApplier interface {
+	ApplyMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error
+}
+
+type KitsPostgresRepository struct {
+	db        *dbpg.DB
+	retries   retry.Strategy
+	movements movementApplier
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, movements movementApplier) *KitsPostgresRepository {
+	return &KitsPostgresRepository{db: db, retries: retries, movements: movements}
+}
+
+// GetKit returns the bill of materials of the item with the current
+// availability of each component.
+func (r *KitsPostgresRepository) GetKit(ctx context.Context, itemID int64) (*domain.Kit, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, componentsQuery, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query kit components error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	kit := &domain.Kit{ItemID: itemID}
+	for rows.Next() {
+		c := &domain.KitComponent{}
+		if err := rows.Scan(&c.KitID, &c.ItemID, &c.SKU, &c.ItemName, &c.Quantity, &c.Available); err != nil {
+			return nil, fmt.Errorf("%w: scan kit component error: %v", customErr.ErrDatabase, err)
+		}
+		kit.Components = append(kit.Components, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: kit components rows error: %v", customErr.ErrDatabase, err)
+	}
+	if len(kit.Components) == 0 {
+		return nil, customErr.ErrKitNotFound
+	}
+	return kit, nil
+}
+
+// SetKit replaces the bill of materials of the item, resolving component
+// SKUs to items. Kits are not nested and serialized items take no part in
+// them, since assembly cannot name serial numbers.
+func (r *KitsPostgresRepository) SetKit(ctx context.Context, kit *domain.Kit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var serialized, component bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT serialized, EXISTS (SELECT 1 FROM kit_components WHERE component_item_id = items.id)
+		FROM items WHERE id = $1 FOR UPDATE`, kit.ItemID,
+	).Scan(&serialized, &component)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrItemNotFound
+		}
+		return fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+	if serialized {
+		return fmt.Errorf("%w: a serialized item cannot be a kit", customErr.ErrInvalidInput)
+	}
+	if component {
+		return fmt.Errorf("%w: the item is a component of another kit", customErr.ErrInvalidInput)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kit_components WHERE kit_item_id = $1`, kit.ItemID); err != nil {
+		return fmt.Errorf("%w: failed to clear kit components: %v", customErr.ErrDatabase, err)
+	}
+	for _, c := range kit.Components {
+		var isKit bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, name, serialized, EXISTS (SELECT 1 FROM kit_components WHERE kit_item_id = items.id)
+			FROM items WHERE sku = $1`, c.SKU,
+		).Scan(&c.ItemID, &c.ItemName, &serialized, &isKit)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: unknown SKU %s", customErr.ErrItemNotFound, c.SKU)
+			}
+			return fmt.Errorf("%w: failed to resolve SKU: %v", customErr.ErrDatabase, err)
+		}
+		switch {
+		case c.ItemID == kit.ItemID:
+			return fmt.Errorf("%w: a kit cannot contain itself", customErr.ErrInvalidInput)
+		case serialized:
+			return fmt.Errorf("%w: serialized item %s cannot be a kit component", customErr.ErrInvalidInput, c.SKU)
+		case isKit:
+			return fmt.Errorf("%w: %s is a kit itself; kits are not nested", customErr.ErrInvalidInput, c.SKU)
+		}
+		c.KitID = kit.ItemID
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO kit_components (kit_item_id, component_item_id, quantity) VALUES ($1, $2, $3)`,
+			c.KitID, c.ItemID, c.Quantity)
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert kit component: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *KitsPostgresRepository) DeleteKit(ctx context.Context, itemID int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `DELETE FROM kit_components WHERE kit_item_id = $1`, itemID)
+	if err != nil {
+		return fmt.Errorf("%w: delete kit components error: %v", customErr.ErrDatabase, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return customErr.ErrKitNotFound
+	}
+	return nil
+}
+
+// AssembleKit consumes the components of the kits and receives the kits, or
+// the reverse when disassembling, as one transaction. Assembled kits are
+// received at the cost of the components consumed; components recovered by
+// disassembly come back at their current cost. Items are locked in id order
+// of the components, the kit last.
+func (r *KitsPostgresRepository) AssembleKit(ctx context.Context, a *domain.KitAssembly, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username); err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT component_item_id, quantity FROM kit_components
+		WHERE kit_item_id = $1 ORDER BY component_item_id FOR SHARE`, a.KitID)
+	if err != nil {
+		return fmt.Errorf("%w: select kit components error: %v", customErr.ErrDatabase, err)
+	}
+	var components []*domain.KitComponent
+	for rows.Next() {
+		c := &domain.KitComponent{KitID: a.KitID}
+		if err := rows.Scan(&c.ItemID, &c.Quantity); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("%w: scan kit component error: %v", customErr.ErrDatabase, err)
+		}
+		components = append(components, c)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: kit components rows error: %v", customErr.ErrDatabase, err)
+	}
+	if len(components) == 0 {
+		return customErr.ErrKitNotFound
+	}
+
+	reason, componentType, kitType := "kit_assembly", domain.MovementIssue, domain.MovementReceipt
+	if a.Disassemble {
+		reason, componentType, kitType = "kit_disassembly", domain.MovementReceipt, domain.MovementIssue
+	}
+	movement := func(itemID int64, t domain.MovementType, quantity int) *domain.StockMovement {
+		m := &domain.StockMovement{
+			ItemID:     itemID,
+			Type:       t,
+			Quantity:   quantity,
+			ReasonCode: reason,
+			Reference:  a.Reference,
+		}
+		if t == domain.MovementIssue {
+			m.FromLocationID = a.LocationID
+		} else {
+			m.ToLocationID = a.LocationID
+		}
+		return m
+	}
+
+	a.Movements = a.Movements[:0]
+	cost := decimal.Zero
+	for _, c := range components {
+		m := movement(c.ItemID, componentType, c.Quantity*a.Quantity)
+		if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+			return err
+		}
+		cost = cost.Add(m.TotalCost)
+		a.Movements = append(a.Movements, m)
+	}
+	m := movement(a.KitID, kitType, a.Quantity)
+	if !a.Disassemble {
+		unitCost := cost.Div(decimal.FromInt(int64(a.Quantity)), domain.CostPlaces)
+		m.UnitCost = &unitCost
+	}
+	if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+		return err
+	}
+	a.Movements = append(a.Movements, m)
+
+	return tx.Commit()
+}
@@ -0,0 +1,212 @@
+package locations_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const uniqueViolation = "23505"
+
+type LocationsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *LocationsPostgresRepository {
+	return &LocationsPostgresRepository{db: db, retries: retries}
+}
+
+func (r *LocationsPostgresRepository) CreateLocation(ctx context.Context, loc *domain.Location) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO locations (parent_id, warehouse_id, location_type, code, name, path)
+              VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6) RETURNING id`
+
+	var id int64
+	err = tx.QueryRowContext(ctx, query,
+		loc.ParentID, loc.WarehouseID, loc.Type, loc.Code, loc.Name, loc.Path,
+	).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrLocationExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert location: %v", customErr.ErrDatabase, err)
+	}
+
+	if loc.Type == domain.LocationWarehouse {
+		if _, err := tx.ExecContext(ctx, `UPDATE locations SET warehouse_id = id WHERE id = $1`, id); err != nil {
+			return 0, fmt.Errorf("%w: failed to set warehouse: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *LocationsPostgresRepository) GetLocations(ctx context.Context, filter domain.LocationFilter) ([]*domain.Location, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Type != nil {
+		conditions = append(conditions, fmt.Sprintf("location_type = $%d", argIndex))
+		args = append(args, *filter.Type)
+		argIndex++
+	}
+	if filter.ParentID != nil {
+		conditions = append(conditions, fmt.Sprintf("parent_id = $%d", argIndex))
+		args = append(args, *filter.ParentID)
+		argIndex++
+	}
+	if filter.WarehouseID != nil {
+		conditions = append(conditions, fmt.Sprintf("warehouse_id = $%d", argIndex))
+		args = append(args, *filter.WarehouseID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM locations %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count locations error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total locations error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Location{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, parent_id, warehouse_id, location_type, code, name, path, created_at, updated_at
+		FROM locations %s
+		ORDER BY path
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query locations error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	locations := make([]*domain.Location, 0, filter.Limit)
+	for rows.Next() {
+		loc, err := scanLocation(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan location error: %v", customErr.ErrDatabase, err)
+		}
+		locations = append(locations, loc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	return locations, total, nil
+}
+
+func (r *LocationsPostgresRepository) GetLocationByID(ctx context.Context, id int64) (*domain.Location, error) {
+	query := `SELECT id, parent_id, warehouse_id, location_type, code, name, path, created_at, updated_at FROM locations WHERE id = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	loc, err := scanLocation(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrLocationNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return loc, nil
+}
+
+func (r *LocationsPostgresRepository) UpdateLocation(ctx context.Context, id int64, name string) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries,
+		`UPDATE locations SET name=$1, updated_at=NOW() WHERE id=$2`, name, id)
+	if err != nil {
+		return fmt.Errorf("%w: update failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return customErr.ErrLocationNotFound
+	}
+	return nil
+}
+
+func (r *LocationsPostgresRepository) DeleteLocation(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var inUse bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM locations WHERE parent_id = $1)
+		    OR EXISTS (SELECT 1 FROM item_stock WHERE location_id = $1 AND quantity > 0)`, id,
+	).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("%w: usage check failed: %v", customErr.ErrDatabase, err)
+	}
+	if inUse {
+		return customErr.ErrLocationInUse
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM locations WHERE id=$1`, id)
+	if err != nil {
+		return fmt.Errorf("%w: delete failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return customErr.ErrLocationNotFound
+	}
+
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLocation(s scanner) (*domain.Location, error) {
+	loc := &domain.Location{}
+	var parentID, warehouseID sql.NullInt64
+	err := s.Scan(&loc.ID, &parentID, &warehouseID, &loc.Type, &loc.Code, &loc.Name, &loc.Path, &loc.CreatedAt, &loc.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		loc.ParentID = &parentID.Int64
+	}
+	loc.WarehouseID = warehouseID.Int64
+	return loc, nil
+}
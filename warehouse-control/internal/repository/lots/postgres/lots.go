@@ -0,0 +1,253 @@
+package lots_postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type LotsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *LotsPostgresRepository {
+	return &LotsPostgresRepository{db: db, retries: retries}
+}
+
+func (r *LotsPostgresRepository) GetLots(ctx context.Context, filter domain.LotFilter) ([]*domain.Lot, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.ItemID != nil {
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", argIndex))
+		args = append(args, *filter.ItemID)
+		argIndex++
+	}
+	if filter.ExpiringWithinDays != nil {
+		conditions = append(conditions, fmt.Sprintf("expires_at <= CURRENT_DATE + $%d::INT", argIndex))
+		args = append(args, *filter.ExpiringWithinDays)
+		argIndex++
+	}
+	if !filter.IncludeEmpty {
+		conditions = append(conditions, "quantity > 0")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM lots %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count lots error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total lots error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Lot{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, lot_number, manufactured_at, expires_at, quantity, created_at, updated_at
+		FROM lots %s
+		ORDER BY expires_at NULLS LAST, id
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query lots error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	lots := make([]*domain.Lot, 0, filter.Limit)
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan lot error: %v", customErr.ErrDatabase, err)
+		}
+		lots = append(lots, lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	return lots, total, nil
+}
+
+func (r *LotsPostgresRepository) GetLotByID(ctx context.Context, id int64) (*domain.Lot, error) {
+	query := `SELECT id, item_id, lot_number, manufactured_at, expires_at, quantity, created_at, updated_at FROM lots WHERE id = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	lot, err := scanLot(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrLotNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return lot, nil
+}
+
+// UpdateLotDates corrects the manufacture and expiry dates of a lot.
+// Quantities only change through stock movements.
+func (r *LotsPostgresRepository) UpdateLotDates(ctx context.Context, id int64, manufacturedAt, expiresAt *time.Time, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE lots SET manufactured_at=$1, expires_at=$2, updated_at=NOW() WHERE id=$3`,
+		manufacturedAt, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("%w: update failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return customErr.ErrLotNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (r *LotsPostgresRepository) GetLotHistory(ctx context.Context, lotID int64, limit, offset int) ([]*domain.LotHistoryRecord, error) {
+	query := `
+		SELECT id, lot_id, item_id, action, old_data, new_data, changed_by, changed_at
+		FROM lots_history
+		WHERE lot_id = $1
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, lotID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select lot history error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := make([]*domain.LotHistoryRecord, 0, limit)
+	for rows.Next() {
+		rec := &domain.LotHistoryRecord{}
+		var oldData, newData []byte
+		err := rows.Scan(&rec.ID, &rec.LotID, &rec.ItemID, &rec.Action, &oldData, &newData, &rec.ChangedBy, &rec.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan lot history record: %v", customErr.ErrDatabase, err)
+		}
+		if rec.OldData, err = decodeLotRow(oldData); err != nil {
+			return nil, fmt.Errorf("%w: decode lot history: %v", customErr.ErrDatabase, err)
+		}
+		if rec.NewData, err = decodeLotRow(newData); err != nil {
+			return nil, fmt.Errorf("%w: decode lot history: %v", customErr.ErrDatabase, err)
+		}
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: lot history rows error: %v", customErr.ErrDatabase, err)
+	}
+
+	return records, nil
+}
+
+func (r *LotsPostgresRepository) setAuditUser(ctx context.Context, tx *sql.Tx, username string) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username)
+	if err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLot(s scanner) (*domain.Lot, error) {
+	lot := &domain.Lot{}
+	var manufacturedAt, expiresAt sql.NullTime
+	err := s.Scan(&lot.ID, &lot.ItemID, &lot.LotNumber, &manufacturedAt, &expiresAt, &lot.Quantity, &lot.CreatedAt, &lot.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if manufacturedAt.Valid {
+		lot.ManufacturedAt = &manufacturedAt.Time
+	}
+	if expiresAt.Valid {
+		lot.ExpiresAt = &expiresAt.Time
+	}
+	return lot, nil
+}
+
+// lotRow mirrors row_to_json(lots) as written by the log_lot_changes trigger.
+type lotRow struct {
+	ID             int64     `json:"id"`
+	ItemID         int64     `json:"item_id"`
+	LotNumber      string    `json:"lot_number"`
+	ManufacturedAt *string   `json:"manufactured_at"`
+	ExpiresAt      *string   `json:"expires_at"`
+	Quantity       int       `json:"quantity"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func decodeLotRow(data []byte) (*domain.Lot, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var row lotRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return nil, err
+	}
+	lot := &domain.Lot{
+		ID:        row.ID,
+		ItemID:    row.ItemID,
+		LotNumber: row.LotNumber,
+		Quantity:  row.Quantity,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	var err error
+	if lot.ManufacturedAt, err = parseDate(row.ManufacturedAt); err != nil {
+		return nil, err
+	}
+	if lot.ExpiresAt, err = parseDate(row.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+func parseDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,213 @@
+package movements_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+)
+
+// applyLots books the lot side of a movement. Incoming stock tops up a lot
+// when one is named; outgoing stock is taken from the named lot or, failing
+// that, from unexpired lots first-expiring-first-out and then from stock
+// that was never assigned to a lot.
+func (r *MovementsPostgresRepository) applyLots(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, onHand int) error {
+	delta := m.Delta()
+	if m.StockStatus != domain.StockAvailable {
+		return r.checkUnlotted(ctx, tx, m, onHand)
+	}
+	switch {
+	case delta > 0:
+		if m.LotID == nil && m.LotNumber == "" {
+			return nil
+		}
+		allocation, err := r.receiveLot(ctx, tx, m, delta)
+		if err != nil {
+			return err
+		}
+		m.Lots = []*domain.LotAllocation{allocation}
+	case delta < 0:
+		var lotted int
+		err := tx.QueryRowContext(ctx,
+			`SELECT COALESCE(SUM(quantity), 0) FROM lots WHERE item_id = $1`, m.ItemID,
+		).Scan(&lotted)
+		if err != nil {
+			return fmt.Errorf("%w: failed to sum lot stock: %v", customErr.ErrDatabase, err)
+		}
+		allocations, err := r.consumeLots(ctx, tx, m, -delta, onHand-lotted)
+		if err != nil {
+			return err
+		}
+		m.Lots = allocations
+	}
+	return nil
+}
+
+// checkUnlotted keeps movements of stock held out of availability away from
+// lots: such stock is received without a lot, and leaves the same way.
+func (r *MovementsPostgresRepository) checkUnlotted(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, onHand int) error {
+	if m.LotID != nil || m.LotNumber != "" {
+		return fmt.Errorf("%w: %s stock is not tracked by lot", customErr.ErrInvalidInput, m.StockStatus)
+	}
+	if m.Delta() >= 0 {
+		return nil
+	}
+	var lotted int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(quantity), 0) FROM lots WHERE item_id = $1`, m.ItemID,
+	).Scan(&lotted)
+	if err != nil {
+		return fmt.Errorf("%w: failed to sum lot stock: %v", customErr.ErrDatabase, err)
+	}
+	if onHand-lotted < -m.Delta() {
+		return customErr.ErrInsufficientStock
+	}
+	return nil
+}
+
+func (r *MovementsPostgresRepository) receiveLot(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, quantity int) (*domain.LotAllocation, error) {
+	allocation := &domain.LotAllocation{Quantity: quantity}
+	var err error
+	if m.LotID != nil {
+		err = tx.QueryRowContext(ctx, `
+			UPDATE lots SET quantity = quantity + $1, updated_at = NOW()
+			WHERE id = $2 AND item_id = $3
+			RETURNING id, lot_number`, quantity, *m.LotID, m.ItemID,
+		).Scan(&allocation.LotID, &allocation.LotNumber)
+	} else {
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO lots (item_id, lot_number, manufactured_at, expires_at, quantity)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (item_id, lot_number) DO UPDATE SET
+			    quantity = lots.quantity + EXCLUDED.quantity,
+			    manufactured_at = COALESCE(lots.manufactured_at, EXCLUDED.manufactured_at),
+			    expires_at = COALESCE(lots.expires_at, EXCLUDED.expires_at),
+			    updated_at = NOW()
+			RETURNING id, lot_number`, m.ItemID, m.LotNumber, m.ManufacturedAt, m.ExpiresAt, quantity,
+		).Scan(&allocation.LotID, &allocation.LotNumber)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrLotNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to receive lot: %v", customErr.ErrDatabase, err)
+	}
+	m.LotID = &allocation.LotID
+	m.LotNumber = allocation.LotNumber
+	return allocation, nil
+}
+
+func (r *MovementsPostgresRepository) consumeLots(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, quantity, unlotted int) ([]*domain.LotAllocation, error) {
+	var candidates []*domain.Lot
+	if m.LotID != nil {
+		lot := &domain.Lot{}
+		err := tx.QueryRowContext(ctx,
+			`SELECT id, lot_number, quantity FROM lots WHERE id = $1 AND item_id = $2 FOR UPDATE`, *m.LotID, m.ItemID,
+		).Scan(&lot.ID, &lot.LotNumber, &lot.Quantity)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, customErr.ErrLotNotFound
+			}
+			return nil, fmt.Errorf("%w: failed to lock lot: %v", customErr.ErrDatabase, err)
+		}
+		if lot.Quantity < quantity {
+			return nil, customErr.ErrInsufficientStock
+		}
+		candidates = []*domain.Lot{lot}
+	} else {
+		// Expired lots are never picked automatically; they have to be
+		// issued (e.g. scrapped) by naming the lot explicitly.
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, lot_number, quantity FROM lots
+			WHERE item_id = $1 AND quantity > 0 AND (expires_at IS NULL OR expires_at >= CURRENT_DATE)
+			ORDER BY expires_at NULLS LAST, id
+			FOR UPDATE`, m.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to select lots: %v", customErr.ErrDatabase, err)
+		}
+		for rows.Next() {
+			lot := &domain.Lot{}
+			if err := rows.Scan(&lot.ID, &lot.LotNumber, &lot.Quantity); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("%w: scan lot error: %v", customErr.ErrDatabase, err)
+			}
+			candidates = append(candidates, lot)
+		}
+		if err := rows.Close(); err != nil {
+			return nil, fmt.Errorf("%w: lots rows error: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	allocations, remaining := domain.AllocateFEFO(candidates, quantity)
+	if remaining > unlotted {
+		return nil, customErr.ErrInsufficientStock
+	}
+
+	for _, a := range allocations {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE lots SET quantity = quantity - $1, updated_at = NOW() WHERE id = $2`, a.Quantity, a.LotID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to update lot: %v", customErr.ErrDatabase, err)
+		}
+	}
+	return allocations, nil
+}
+
+func (r *MovementsPostgresRepository) insertMovementLots(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error {
+	for _, a := range m.Lots {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO movement_lots (movement_id, lot_id, quantity) VALUES ($1, $2, $3)`, m.ID, a.LotID, a.Quantity)
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert movement lot: %v", customErr.ErrDatabase, err)
+		}
+	}
+	return nil
+}
+
+func (r *MovementsPostgresRepository) loadMovementLots(ctx context.Context, movements []*domain.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.StockMovement, len(movements))
+	placeholders := make([]string, len(movements))
+	args := make([]interface{}, len(movements))
+	for i, m := range movements {
+		byID[m.ID] = m
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = m.ID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT ml.movement_id, ml.lot_id, l.lot_number, ml.quantity
+		FROM movement_lots ml
+		JOIN lots l ON l.id = ml.lot_id
+		WHERE ml.movement_id IN (%s)
+		ORDER BY l.expires_at NULLS LAST, l.id`, strings.Join(placeholders, ","))
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: select movement lots error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var movementID int64
+		a := &domain.LotAllocation{}
+		if err := rows.Scan(&movementID, &a.LotID, &a.LotNumber, &a.Quantity); err != nil {
+			return fmt.Errorf("%w: scan movement lot error: %v", customErr.ErrDatabase, err)
+		}
+		if m, ok := byID[movementID]; ok {
+			m.Lots = append(m.Lots, a)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: movement lots rows error: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
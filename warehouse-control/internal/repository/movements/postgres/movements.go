@@ -0,0 +1,410 @@
+package movements_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// statusColumns maps the stock statuses held out of availability to the
+// items columns that count them.
+var statusColumns = map[domain.StockStatus]string{
+	domain.StockQuarantine: "quarantine_quantity",
+	domain.StockDamaged:    "damaged_quantity",
+	domain.StockBlocked:    "blocked_quantity",
+}
+
+// costBooker books the cost of a stock movement inserted within tx.
+type costBooker interface {
+	ApplyCost(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error
+}
+
+type MovementsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+	costs   costBooker
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, costs costBooker) *MovementsPostgresRepository {
+	return &MovementsPostgresRepository{db: db, retries: retries, costs: costs}
+}
+
+// CreateMovement books the movement and applies it to the item's quantity
+// and bin stock in the same transaction, so the ledger, item_stock and
+// items.quantity never diverge. The item row lock serialises all movements
+// of one item.
+func (r *MovementsPostgresRepository) CreateMovement(ctx context.Context, m *domain.StockMovement, username string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return 0, err
+	}
+
+	if err := r.ApplyMovement(ctx, tx, m, username); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return m.ID, nil
+}
+
+// ApplyMovement books the movement within tx, for callers that combine
+// several movements with their own changes in one transaction. The caller
+// sets the audit user.
+func (r *MovementsPostgresRepository) ApplyMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error {
+	var quantity, quarantined, damaged, blocked, allocated int
+	var location string
+	var serialized bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT quantity, quarantine_quantity, damaged_quantity, blocked_quantity, COALESCE(location, ''), serialized
+		 FROM items WHERE id = $1 FOR UPDATE`, m.ItemID,
+	).Scan(&quantity, &quarantined, &damaged, &blocked, &location, &serialized)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrItemNotFound
+		}
+		return fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(quantity), 0) FROM item_stock WHERE item_id = $1`, m.ItemID,
+	).Scan(&allocated)
+	if err != nil {
+		return fmt.Errorf("%w: failed to sum bin stock: %v", customErr.ErrDatabase, err)
+	}
+
+	reserved, err := r.reservedQuantity(ctx, tx, m)
+	if err != nil {
+		return err
+	}
+
+	if m.FromLocationID != nil {
+		if m.FromLocation, err = r.binPath(ctx, tx, *m.FromLocationID); err != nil {
+			return err
+		}
+	}
+	if m.ToLocationID != nil {
+		if m.ToLocation, err = r.binPath(ctx, tx, *m.ToLocationID); err != nil {
+			return err
+		}
+	}
+
+	// Stock held by other reservations or in another status cannot be issued
+	// or adjusted away; movements in another status only ever touch the stock
+	// held in that status.
+	if m.StockStatus == "" {
+		m.StockStatus = domain.StockAvailable
+	}
+	newQuantity := quantity + m.Delta()
+	held := map[domain.StockStatus]int{
+		domain.StockQuarantine: quarantined,
+		domain.StockDamaged:    damaged,
+		domain.StockBlocked:    blocked,
+	}
+	column, segregated := statusColumns[m.StockStatus]
+	if segregated {
+		if (m.Type != domain.MovementReceipt && m.Type != domain.MovementIssue) || m.ReservationID != nil {
+			return fmt.Errorf("%w: %s stock can only be received or issued", customErr.ErrInvalidInput, m.StockStatus)
+		}
+		if held[m.StockStatus]+m.Delta() < 0 {
+			return customErr.ErrInsufficientStock
+		}
+	}
+	if newQuantity < 0 || (m.Delta() < 0 && !segregated && newQuantity < reserved+quarantined+damaged+blocked) {
+		return customErr.ErrInsufficientStock
+	}
+
+	if err := r.applyLots(ctx, tx, m, quantity); err != nil {
+		return err
+	}
+
+	// Take stock out of the source bin (or the unassigned pool) first, then
+	// put it into the destination bin.
+	var take, put int
+	switch m.Type {
+	case domain.MovementReceipt:
+		put = m.Quantity
+	case domain.MovementIssue:
+		take = m.Quantity
+	case domain.MovementAdjustment:
+		if m.Quantity < 0 {
+			take = -m.Quantity
+		} else {
+			put = m.Quantity
+		}
+	case domain.MovementTransfer:
+		take, put = m.Quantity, m.Quantity
+	}
+
+	legacyTransfer := m.Type == domain.MovementTransfer && m.FromLocationID == nil && m.ToLocationID == nil
+	if legacyTransfer {
+		if m.Quantity > quantity {
+			return customErr.ErrInsufficientStock
+		}
+		if m.FromLocation == "" {
+			m.FromLocation = location
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE items SET location=$1, updated_at=NOW() WHERE id=$2`, m.ToLocation, m.ItemID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to apply movement: %v", customErr.ErrDatabase, err)
+		}
+	} else {
+		if take > 0 {
+			if err := r.takeStock(ctx, tx, m.ItemID, m.FromLocationID, take, quantity-allocated); err != nil {
+				return err
+			}
+		}
+		if put > 0 && m.ToLocationID != nil {
+			if err := r.putStock(ctx, tx, m.ItemID, *m.ToLocationID, put); err != nil {
+				return err
+			}
+		}
+	}
+
+	if newQuantity != quantity {
+		if segregated {
+			_, err = tx.ExecContext(ctx,
+				fmt.Sprintf(`UPDATE items SET quantity=$1, %s=%s+$2, updated_at=NOW() WHERE id=$3`, column, column),
+				newQuantity, m.Delta(), m.ItemID)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE items SET quantity=$1, updated_at=NOW() WHERE id=$2`, newQuantity, m.ItemID)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: failed to apply movement: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	if m.ReservationID != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE reservations SET fulfilled_quantity = fulfilled_quantity + $1,
+			    status = CASE WHEN fulfilled_quantity + $1 = quantity THEN 'fulfilled' ELSE status END,
+			    updated_at = NOW()
+			WHERE id = $2`, m.Quantity, *m.ReservationID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to fulfil reservation: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	query := `INSERT INTO stock_movements (item_id, movement_type, quantity, reason_code, reference,
+                  from_location_id, to_location_id, reservation_id, purchase_order_line_id, from_location, to_location, stock_status, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		m.ItemID, m.Type, m.Quantity, m.ReasonCode, m.Reference,
+		m.FromLocationID, m.ToLocationID, m.ReservationID, m.PurchaseOrderLineID, m.FromLocation, m.ToLocation, m.StockStatus, username,
+	).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert movement: %v", customErr.ErrDatabase, err)
+	}
+	m.CreatedBy = username
+
+	if err := r.costs.ApplyCost(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := r.insertMovementLots(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := r.applySerials(ctx, tx, m, serialized); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *MovementsPostgresRepository) GetMovements(ctx context.Context, filter domain.MovementFilter) ([]*domain.StockMovement, int, error) {
+	conditions := []string{"item_id = $1"}
+	args := []interface{}{filter.ItemID}
+	argIndex := 2
+
+	if filter.Type != nil {
+		conditions = append(conditions, fmt.Sprintf("movement_type = $%d", argIndex))
+		args = append(args, *filter.Type)
+		argIndex++
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM stock_movements %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count movements error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total movements error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.StockMovement{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, movement_type, quantity, reason_code, reference,
+		       from_location_id, to_location_id, reservation_id, from_location, to_location, stock_status, unit_cost, total_cost, created_by, created_at
+		FROM stock_movements
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: select movements error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	movements := make([]*domain.StockMovement, 0, filter.Limit)
+	for rows.Next() {
+		m := &domain.StockMovement{}
+		var fromID, toID, reservationID sql.NullInt64
+		var unitCost decimal.Decimal
+		err := rows.Scan(&m.ID, &m.ItemID, &m.Type, &m.Quantity, &m.ReasonCode, &m.Reference,
+			&fromID, &toID, &reservationID, &m.FromLocation, &m.ToLocation, &m.StockStatus, &unitCost, &m.TotalCost, &m.CreatedBy, &m.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan movement error: %v", customErr.ErrDatabase, err)
+		}
+		m.UnitCost = &unitCost
+		if fromID.Valid {
+			m.FromLocationID = &fromID.Int64
+		}
+		if toID.Valid {
+			m.ToLocationID = &toID.Int64
+		}
+		if reservationID.Valid {
+			m.ReservationID = &reservationID.Int64
+		}
+		movements = append(movements, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: movements rows error: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := r.loadMovementLots(ctx, movements); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadMovementSerials(ctx, movements); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+// reservedQuantity returns the stock held by active reservations of the item,
+// excluding the reservation the movement fulfils. That reservation is locked
+// and checked to be able to cover the movement.
+func (r *MovementsPostgresRepository) reservedQuantity(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) (int, error) {
+	var exclude int64
+	if m.ReservationID != nil {
+		res := domain.Reservation{}
+		var expired bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT item_id, quantity, fulfilled_quantity, status, expires_at <= NOW()
+			FROM reservations WHERE id = $1 FOR UPDATE`, *m.ReservationID,
+		).Scan(&res.ItemID, &res.Quantity, &res.FulfilledQuantity, &res.Status, &expired)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, customErr.ErrReservationNotFound
+			}
+			return 0, fmt.Errorf("%w: failed to lock reservation: %v", customErr.ErrDatabase, err)
+		}
+		if res.ItemID != m.ItemID {
+			return 0, customErr.ErrReservationNotFound
+		}
+		if res.Status != domain.ReservationActive || expired {
+			return 0, customErr.ErrReservationClosed
+		}
+		if m.Quantity > res.Remaining() {
+			return 0, fmt.Errorf("%w: issue exceeds the reserved quantity", customErr.ErrInvalidInput)
+		}
+		exclude = *m.ReservationID
+	}
+
+	var reserved int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(quantity - fulfilled_quantity), 0) FROM reservations
+		WHERE item_id = $1 AND status = 'active' AND expires_at > NOW() AND id <> $2`, m.ItemID, exclude,
+	).Scan(&reserved)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to sum reservations: %v", customErr.ErrDatabase, err)
+	}
+	return reserved, nil
+}
+
+// binPath resolves a location id to its path, refusing anything but bins.
+func (r *MovementsPostgresRepository) binPath(ctx context.Context, tx *sql.Tx, locationID int64) (string, error) {
+	var path string
+	var locationType domain.LocationType
+	err := tx.QueryRowContext(ctx, `SELECT path, location_type FROM locations WHERE id = $1`, locationID).Scan(&path, &locationType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", customErr.ErrLocationNotFound
+		}
+		return "", fmt.Errorf("%w: failed to get location: %v", customErr.ErrDatabase, err)
+	}
+	if locationType != domain.LocationBin {
+		return "", fmt.Errorf("%w: stock can only be held in bins, %s is a %s", customErr.ErrInvalidInput, path, locationType)
+	}
+	return path, nil
+}
+
+// takeStock removes quantity from a bin, or from the unassigned pool when no
+// bin is given.
+func (r *MovementsPostgresRepository) takeStock(ctx context.Context, tx *sql.Tx, itemID int64, locationID *int64, quantity, unassigned int) error {
+	if locationID == nil {
+		if quantity > unassigned {
+			return customErr.ErrInsufficientStock
+		}
+		return nil
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE item_stock SET quantity = quantity - $1 WHERE item_id = $2 AND location_id = $3 AND quantity >= $1`,
+		quantity, itemID, *locationID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update bin stock: %v", customErr.ErrDatabase, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to update bin stock: %v", customErr.ErrDatabase, err)
+	}
+	if rows == 0 {
+		return customErr.ErrInsufficientStock
+	}
+	return nil
+}
+
+func (r *MovementsPostgresRepository) putStock(ctx context.Context, tx *sql.Tx, itemID, locationID int64, quantity int) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO item_stock (item_id, location_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, location_id) DO UPDATE SET quantity = item_stock.quantity + EXCLUDED.quantity`,
+		itemID, locationID, quantity)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update bin stock: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *MovementsPostgresRepository) setAuditUser(ctx context.Context, tx *sql.Tx, username string) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username)
+	if err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
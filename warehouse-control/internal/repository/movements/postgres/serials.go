@@ -0,0 +1,131 @@
+package movements_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+)
+
+// applySerials moves the serial numbers named by a movement of a serialized
+// item and links them to the (already inserted) movement. Every unit leaving
+// or entering stock has to be named, so the serials in stock always add up
+// to the item's quantity.
+func (r *MovementsPostgresRepository) applySerials(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, serialized bool) error {
+	if !serialized {
+		if len(m.Serials) > 0 {
+			return fmt.Errorf("%w: item is not serialized", customErr.ErrInvalidInput)
+		}
+		return nil
+	}
+
+	required := m.Delta()
+	if required < 0 {
+		required = -required
+	}
+	if m.Type == domain.MovementTransfer {
+		required = m.Quantity
+	}
+	if len(m.Serials) != required {
+		return fmt.Errorf("%w: serialized item requires %d serial numbers, got %d", customErr.ErrInvalidInput, required, len(m.Serials))
+	}
+
+	for _, sn := range m.Serials {
+		var serialID int64
+		var err error
+		switch {
+		case m.Delta() > 0:
+			// A serial that was issued before may come back (e.g. a return);
+			// one that is still in stock, or belongs to another item, may not.
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO serials (item_id, serial_number, status, location_id)
+				VALUES ($1, $2, 'in_stock', $3)
+				ON CONFLICT (serial_number) DO UPDATE SET
+				    status = 'in_stock', location_id = EXCLUDED.location_id, updated_at = NOW()
+				WHERE serials.item_id = EXCLUDED.item_id AND serials.status <> 'in_stock'
+				RETURNING id`, m.ItemID, sn, m.ToLocationID,
+			).Scan(&serialID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s", customErr.ErrSerialConflict, sn)
+			}
+		case m.Type == domain.MovementTransfer && m.FromLocationID == nil && m.ToLocationID == nil:
+			err = tx.QueryRowContext(ctx, `
+				UPDATE serials SET updated_at = NOW()
+				WHERE item_id = $1 AND serial_number = $2 AND status = 'in_stock'
+				RETURNING id`, m.ItemID, sn,
+			).Scan(&serialID)
+		default:
+			status := domain.SerialIssued
+			if m.Type == domain.MovementTransfer {
+				status = domain.SerialInStock
+			}
+			err = tx.QueryRowContext(ctx, `
+				UPDATE serials SET status = $1, location_id = $2, updated_at = NOW()
+				WHERE item_id = $3 AND serial_number = $4 AND status = 'in_stock'
+				  AND location_id IS NOT DISTINCT FROM $5
+				RETURNING id`, status, m.ToLocationID, m.ItemID, sn, m.FromLocationID,
+			).Scan(&serialID)
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s is not in stock at the source location", customErr.ErrSerialNotFound, sn)
+			}
+			return fmt.Errorf("%w: failed to update serial: %v", customErr.ErrDatabase, err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO movement_serials (movement_id, serial_id) VALUES ($1, $2)`, m.ID, serialID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert movement serial: %v", customErr.ErrDatabase, err)
+		}
+	}
+	return nil
+}
+
+func (r *MovementsPostgresRepository) loadMovementSerials(ctx context.Context, movements []*domain.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.StockMovement, len(movements))
+	placeholders := make([]string, len(movements))
+	args := make([]interface{}, len(movements))
+	for i, m := range movements {
+		byID[m.ID] = m
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = m.ID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT ms.movement_id, s.serial_number
+		FROM movement_serials ms
+		JOIN serials s ON s.id = ms.serial_id
+		WHERE ms.movement_id IN (%s)
+		ORDER BY s.serial_number`, strings.Join(placeholders, ","))
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: select movement serials error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var movementID int64
+		var sn string
+		if err := rows.Scan(&movementID, &sn); err != nil {
+			return fmt.Errorf("%w: scan movement serial error: %v", customErr.ErrDatabase, err)
+		}
+		if m, ok := byID[movementID]; ok {
+			m.Serials = append(m.Serials, sn)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: movement serials rows error: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
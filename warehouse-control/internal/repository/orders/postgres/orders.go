@@ -0,0 +1,680 @@
+package orders_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const uniqueViolation = "23505"
+
+const orderColumns = `
+	SELECT id, number, customer, status, notes, created_by, created_at, updated_at, shipped_at
+	FROM orders`
+
+// movementApplier books stock movements inside a caller's transaction.
+type movementApplier interface {
+	ApplyMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error
+}
+
+type OrdersPostgresRepository struct {
+	db        *dbpg.DB
+	retries   retry.Strategy
+	movements movementApplier
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, movements movementApplier) *OrdersPostgresRepository {
+	return &OrdersPostgresRepository{db: db, retries: retries, movements: movements}
+}
+
+// CreateOrder inserts a new order and its lines, resolving each line's SKU
+// to an item, and records the creation in the order history.
+func (r *OrdersPostgresRepository) CreateOrder(ctx context.Context, o *domain.Order) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	o.Status = domain.OrderNew
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (number, customer, status, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		o.Number, o.Customer, o.Status, o.Notes, o.CreatedBy,
+	).Scan(&o.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrOrderExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert order: %v", customErr.ErrDatabase, err)
+	}
+
+	for _, line := range o.Lines {
+		err := tx.QueryRowContext(ctx, `SELECT id FROM items WHERE sku = $1`, line.SKU).Scan(&line.ItemID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, fmt.Errorf("%w: unknown SKU %s", customErr.ErrItemNotFound, line.SKU)
+			}
+			return 0, fmt.Errorf("%w: failed to resolve SKU: %v", customErr.ErrDatabase, err)
+		}
+		line.OrderID = o.ID
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO order_lines (order_id, item_id, sku, quantity) VALUES ($1, $2, $3, $4) RETURNING id`,
+			line.OrderID, line.ItemID, line.SKU, line.Quantity,
+		).Scan(&line.ID)
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to insert order line: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO order_history (order_id, to_status, changed_by) VALUES ($1, $2, $3)`, o.ID, o.Status, o.CreatedBy)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to insert order history: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return o.ID, nil
+}
+
+func (r *OrdersPostgresRepository) GetOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+	if filter.Customer != "" {
+		conditions = append(conditions, fmt.Sprintf("customer ILIKE $%d", argIndex))
+		args = append(args, "%"+filter.Customer+"%")
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM orders %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count orders error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total orders error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Order{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`%s %s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		orderColumns, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query orders error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	orders := make([]*domain.Order, 0, filter.Limit)
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan order error: %v", customErr.ErrDatabase, err)
+		}
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	return orders, total, nil
+}
+
+// GetOrderByID returns the order with its lines and the picks confirmed for
+// each line.
+func (r *OrdersPostgresRepository) GetOrderByID(ctx context.Context, id int64) (*domain.Order, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, orderColumns+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	o, err := scanOrder(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT l.id, l.order_id, l.item_id, l.sku, i.name, l.quantity, l.reservation_id
+		FROM order_lines l
+		JOIN items i ON i.id = l.item_id
+		WHERE l.order_id = $1
+		ORDER BY l.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select order lines error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	o.Lines = make([]*domain.OrderLine, 0)
+	byID := make(map[int64]*domain.OrderLine)
+	for rows.Next() {
+		l := &domain.OrderLine{}
+		var reservationID sql.NullInt64
+		if err := rows.Scan(&l.ID, &l.OrderID, &l.ItemID, &l.SKU, &l.ItemName, &l.Quantity, &reservationID); err != nil {
+			return nil, fmt.Errorf("%w: scan order line error: %v", customErr.ErrDatabase, err)
+		}
+		if reservationID.Valid {
+			l.ReservationID = &reservationID.Int64
+		}
+		o.Lines = append(o.Lines, l)
+		byID[l.ID] = l
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	pickRows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT p.id, p.line_id, p.location_id, COALESCE(loc.path, ''), p.quantity, p.serials, p.picked_by, p.picked_at
+		FROM order_picks p
+		JOIN order_lines l ON l.id = p.line_id
+		LEFT JOIN locations loc ON loc.id = p.location_id
+		WHERE l.order_id = $1
+		ORDER BY p.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select order picks error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = pickRows.Close() }()
+
+	for pickRows.Next() {
+		p := &domain.OrderPick{}
+		var locationID sql.NullInt64
+		if err := pickRows.Scan(&p.ID, &p.LineID, &locationID, &p.LocationPath, &p.Quantity,
+			pq.Array(&p.Serials), &p.PickedBy, &p.PickedAt); err != nil {
+			return nil, fmt.Errorf("%w: scan order pick error: %v", customErr.ErrDatabase, err)
+		}
+		if locationID.Valid {
+			p.LocationID = &locationID.Int64
+		}
+		if l, ok := byID[p.LineID]; ok {
+			l.Picks = append(l.Picks, p)
+			l.PickedQuantity += p.Quantity
+		}
+	}
+	if err := pickRows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return o, nil
+}
+
+func (r *OrdersPostgresRepository) GetOrderHistory(ctx context.Context, id int64) ([]*domain.OrderHistoryRecord, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT id, order_id, from_status, to_status, note, changed_by, changed_at
+		FROM order_history WHERE order_id = $1
+		ORDER BY changed_at, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select order history error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := make([]*domain.OrderHistoryRecord, 0)
+	for rows.Next() {
+		rec := &domain.OrderHistoryRecord{}
+		var from sql.NullString
+		if err := rows.Scan(&rec.ID, &rec.OrderID, &from, &rec.ToStatus, &rec.Note, &rec.ChangedBy, &rec.ChangedAt); err != nil {
+			return nil, fmt.Errorf("%w: scan order history error: %v", customErr.ErrDatabase, err)
+		}
+		if from.Valid {
+			status := domain.OrderStatus(from.String)
+			rec.FromStatus = &status
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	if len(records) == 0 {
+		return nil, customErr.ErrOrderNotFound
+	}
+	return records, nil
+}
+
+// GetPickStock returns the bin stock of every item on the order, ordered by
+// bin path.
+func (r *OrdersPostgresRepository) GetPickStock(ctx context.Context, id int64) ([]*domain.ItemStock, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT s.item_id, s.location_id, loc.path, loc.warehouse_id, s.quantity
+		FROM item_stock s
+		JOIN locations loc ON loc.id = s.location_id
+		WHERE s.quantity > 0 AND s.item_id IN (SELECT item_id FROM order_lines WHERE order_id = $1)
+		ORDER BY loc.path, s.item_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select pick stock error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	stock := make([]*domain.ItemStock, 0)
+	for rows.Next() {
+		st := &domain.ItemStock{}
+		if err := rows.Scan(&st.ItemID, &st.LocationID, &st.LocationPath, &st.WarehouseID, &st.Quantity); err != nil {
+			return nil, fmt.Errorf("%w: scan item stock error: %v", customErr.ErrDatabase, err)
+		}
+		stock = append(stock, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return stock, nil
+}
+
+// AllocateOrder reserves the full quantity of every line until expiresAt.
+// Items are locked in id order, as reservations do, so an order is either
+// allocated completely or not at all.
+func (r *OrdersPostgresRepository) AllocateOrder(ctx context.Context, id int64, from []domain.OrderStatus, expiresAt time.Time, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	number, err := r.lockOrder(ctx, tx, id, from, domain.OrderAllocated)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, item_id, sku, quantity FROM order_lines WHERE order_id = $1 ORDER BY item_id`, id)
+	if err != nil {
+		return fmt.Errorf("%w: select order lines error: %v", customErr.ErrDatabase, err)
+	}
+	var lines []*domain.OrderLine
+	for rows.Next() {
+		l := &domain.OrderLine{}
+		if err := rows.Scan(&l.ID, &l.ItemID, &l.SKU, &l.Quantity); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("%w: scan order line error: %v", customErr.ErrDatabase, err)
+		}
+		lines = append(lines, l)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	for _, l := range lines {
+		var available int
+		err := tx.QueryRowContext(ctx, `
+			SELECT i.quantity - i.quarantine_quantity - i.damaged_quantity - i.blocked_quantity - COALESCE((
+			    SELECT SUM(quantity - fulfilled_quantity) FROM reservations
+			    WHERE item_id = i.id AND status = 'active' AND expires_at > NOW()), 0)
+			FROM items i WHERE i.id = $1 FOR UPDATE`, l.ItemID,
+		).Scan(&available)
+		if err != nil {
+			return fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+		}
+		if available < l.Quantity {
+			return fmt.Errorf("%w: %s has %d available, %d ordered", customErr.ErrInsufficientStock, l.SKU, available, l.Quantity)
+		}
+
+		var reservationID int64
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO reservations (item_id, quantity, reference, status, expires_at, created_by)
+			VALUES ($1, $2, $3, 'active', $4, $5) RETURNING id`,
+			l.ItemID, l.Quantity, "order:"+number, expiresAt, username,
+		).Scan(&reservationID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert reservation: %v", customErr.ErrDatabase, err)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE order_lines SET reservation_id = $1 WHERE id = $2`, reservationID, l.ID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to update order line: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	if err := r.setStatus(ctx, tx, id, domain.OrderAllocated, "", username); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// ConfirmPicks records picks against the order's lines. A line may be picked
+// short, but never beyond its outstanding quantity. With complete set the
+// order moves on to picked; lines left short are noted in the history.
+func (r *OrdersPostgresRepository) ConfirmPicks(ctx context.Context, id int64, picks []*domain.OrderPick, complete bool, from []domain.OrderStatus, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := r.lockOrder(ctx, tx, id, from, domain.OrderPicked); err != nil {
+		return err
+	}
+
+	for _, p := range picks {
+		if err := r.insertPick(ctx, tx, id, p, username); err != nil {
+			return err
+		}
+	}
+
+	if complete {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT l.sku, l.quantity, COALESCE(SUM(p.quantity), 0)::INT
+			FROM order_lines l
+			LEFT JOIN order_picks p ON p.line_id = l.id
+			WHERE l.order_id = $1
+			GROUP BY l.id
+			ORDER BY l.id`, id)
+		if err != nil {
+			return fmt.Errorf("%w: select picked quantities error: %v", customErr.ErrDatabase, err)
+		}
+		var short []string
+		var picked int
+		for rows.Next() {
+			var sku string
+			var quantity, linePicked int
+			if err := rows.Scan(&sku, &quantity, &linePicked); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("%w: scan picked quantity error: %v", customErr.ErrDatabase, err)
+			}
+			if linePicked < quantity {
+				short = append(short, fmt.Sprintf("%s %d/%d", sku, linePicked, quantity))
+			}
+			picked += linePicked
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+		}
+		if picked == 0 {
+			return fmt.Errorf("%w: nothing was picked, cancel the order instead", customErr.ErrInvalidInput)
+		}
+
+		note := ""
+		if len(short) > 0 {
+			note = "short picked: " + strings.Join(short, ", ")
+		}
+		if err := r.setStatus(ctx, tx, id, domain.OrderPicked, note, username); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *OrdersPostgresRepository) insertPick(ctx context.Context, tx *sql.Tx, orderID int64, p *domain.OrderPick, username string) error {
+	var itemID int64
+	var sku string
+	var outstanding int
+	var serialized bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT l.item_id, l.sku, l.quantity - COALESCE((SELECT SUM(quantity) FROM order_picks WHERE line_id = l.id), 0), i.serialized
+		FROM order_lines l
+		JOIN items i ON i.id = l.item_id
+		WHERE l.id = $1 AND l.order_id = $2
+		FOR UPDATE OF l`, p.LineID, orderID,
+	).Scan(&itemID, &sku, &outstanding, &serialized)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: line %d does not belong to the order", customErr.ErrInvalidInput, p.LineID)
+		}
+		return fmt.Errorf("%w: failed to lock order line: %v", customErr.ErrDatabase, err)
+	}
+	if p.Quantity > outstanding {
+		return fmt.Errorf("%w: %d of %s picked, only %d outstanding", customErr.ErrInvalidInput, p.Quantity, sku, outstanding)
+	}
+	if serialized && len(p.Serials) != p.Quantity {
+		return fmt.Errorf("%w: serialized item requires %d serial numbers, got %d", customErr.ErrInvalidInput, p.Quantity, len(p.Serials))
+	}
+	if !serialized && len(p.Serials) > 0 {
+		return fmt.Errorf("%w: item is not serialized", customErr.ErrInvalidInput)
+	}
+
+	if p.LocationID != nil {
+		var locationType domain.LocationType
+		var held int
+		err := tx.QueryRowContext(ctx, `
+			SELECT loc.path, loc.location_type, COALESCE(s.quantity, 0)
+			FROM locations loc
+			LEFT JOIN item_stock s ON s.location_id = loc.id AND s.item_id = $2
+			WHERE loc.id = $1`, *p.LocationID, itemID,
+		).Scan(&p.LocationPath, &locationType, &held)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErr.ErrLocationNotFound
+			}
+			return fmt.Errorf("%w: failed to get location: %v", customErr.ErrDatabase, err)
+		}
+		if locationType != domain.LocationBin {
+			return fmt.Errorf("%w: stock can only be held in bins, %s is a %s", customErr.ErrInvalidInput, p.LocationPath, locationType)
+		}
+		if held < p.Quantity {
+			return fmt.Errorf("%w: %s holds %d of %s", customErr.ErrInsufficientStock, p.LocationPath, held, sku)
+		}
+	}
+
+	p.PickedBy = username
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO order_picks (line_id, location_id, quantity, serials, picked_by)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, picked_at`,
+		p.LineID, p.LocationID, p.Quantity, pq.Array(p.Serials), p.PickedBy,
+	).Scan(&p.ID, &p.PickedAt)
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert pick: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+// ShipOrder issues every confirmed pick as a stock movement from its bin,
+// fulfilling the line's reservation where it still holds the stock, and
+// releases whatever short picks left reserved. It returns the ids of the
+// shipped items.
+func (r *OrdersPostgresRepository) ShipOrder(ctx context.Context, id int64, from []domain.OrderStatus, username string) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	number, err := r.lockOrder(ctx, tx, id, from, domain.OrderShipped)
+	if err != nil {
+		return nil, err
+	}
+
+	// Movements lock their item, so picks are issued in item order to keep
+	// concurrent shipments from deadlocking.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT l.item_id, l.reservation_id, p.location_id, p.quantity, p.serials
+		FROM order_picks p
+		JOIN order_lines l ON l.id = p.line_id
+		WHERE l.order_id = $1
+		ORDER BY l.item_id, p.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select order picks error: %v", customErr.ErrDatabase, err)
+	}
+	var movements []*domain.StockMovement
+	for rows.Next() {
+		m := &domain.StockMovement{
+			Type:       domain.MovementIssue,
+			ReasonCode: "sales_order",
+			Reference:  number,
+		}
+		var reservationID, locationID sql.NullInt64
+		if err := rows.Scan(&m.ItemID, &reservationID, &locationID, &m.Quantity, pq.Array(&m.Serials)); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("%w: scan order pick error: %v", customErr.ErrDatabase, err)
+		}
+		if reservationID.Valid {
+			m.ReservationID = &reservationID.Int64
+		}
+		if locationID.Valid {
+			m.FromLocationID = &locationID.Int64
+		}
+		movements = append(movements, m)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	var shipped []int64
+	for _, m := range movements {
+		if m.ReservationID != nil {
+			// An expired or released reservation no longer holds the stock;
+			// the issue then has to come out of available stock.
+			var holds bool
+			err := tx.QueryRowContext(ctx, `
+				SELECT status = 'active' AND expires_at > NOW() AND quantity - fulfilled_quantity >= $2
+				FROM reservations WHERE id = $1`, *m.ReservationID, m.Quantity,
+			).Scan(&holds)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: failed to check reservation: %v", customErr.ErrDatabase, err)
+			}
+			if !holds {
+				m.ReservationID = nil
+			}
+		}
+		if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+			return nil, err
+		}
+		if len(shipped) == 0 || shipped[len(shipped)-1] != m.ItemID {
+			shipped = append(shipped, m.ItemID)
+		}
+	}
+
+	if err := r.releaseReservations(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE orders SET shipped_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update order: %v", customErr.ErrDatabase, err)
+	}
+	if err := r.setStatus(ctx, tx, id, domain.OrderShipped, "", username); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return shipped, nil
+}
+
+// CancelOrder cancels the order and releases its reservations. Picks that
+// were confirmed never left the warehouse, so no stock is moved.
+func (r *OrdersPostgresRepository) CancelOrder(ctx context.Context, id int64, from []domain.OrderStatus, note, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := r.lockOrder(ctx, tx, id, from, domain.OrderCancelled); err != nil {
+		return err
+	}
+	if err := r.releaseReservations(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := r.setStatus(ctx, tx, id, domain.OrderCancelled, note, username); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// lockOrder locks the order and checks that its current status is one of
+// from, i.e. that it may move to status. It returns the order number.
+func (r *OrdersPostgresRepository) lockOrder(ctx context.Context, tx *sql.Tx, id int64, from []domain.OrderStatus, status domain.OrderStatus) (string, error) {
+	var number string
+	var current domain.OrderStatus
+	err := tx.QueryRowContext(ctx, `SELECT number, status FROM orders WHERE id = $1 FOR UPDATE`, id).Scan(&number, &current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", customErr.ErrOrderNotFound
+		}
+		return "", fmt.Errorf("%w: failed to lock order: %v", customErr.ErrDatabase, err)
+	}
+	if !slices.Contains(from, current) {
+		return "", fmt.Errorf("%w: order %s is %s and cannot become %s", customErr.ErrInvalidTransition, number, current, status)
+	}
+	return number, nil
+}
+
+// setStatus moves a locked order to status and records the transition.
+func (r *OrdersPostgresRepository) setStatus(ctx context.Context, tx *sql.Tx, id int64, status domain.OrderStatus, note, username string) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH prev AS (SELECT status FROM orders WHERE id = $1)
+		INSERT INTO order_history (order_id, from_status, to_status, note, changed_by)
+		SELECT $1, prev.status, $2, $3, $4 FROM prev`, id, status, note, username)
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert order history: %v", customErr.ErrDatabase, err)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("%w: failed to update order status: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *OrdersPostgresRepository) releaseReservations(ctx context.Context, tx *sql.Tx, id int64) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reservations SET status = 'released', updated_at = NOW()
+		WHERE status = 'active' AND id IN (SELECT reservation_id FROM order_lines WHERE order_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("%w: failed to release reservations: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrder(s scanner) (*domain.Order, error) {
+	o := &domain.Order{}
+	var shippedAt sql.NullTime
+	err := s.Scan(&o.ID, &o.Number, &o.Customer, &o.Status, &o.Notes, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt, &shippedAt)
+	if err != nil {
+		return nil, err
+	}
+	if shippedAt.Valid {
+		o.ShippedAt = &shippedAt.Time
+	}
+	return o, nil
+}
@@ -0,0 +1,304 @@
+package products_postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+const productColumns = `
+	SELECT id, name, sku, COALESCE(category, ''), price, currency, attributes, created_at, updated_at
+	FROM products`
+
+// attribute is a variant attribute as stored in products.attributes.
+type attribute struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ProductsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *ProductsPostgresRepository {
+	return &ProductsPostgresRepository{db: db, retries: retries}
+}
+
+// CreateProduct inserts the product and one item for every combination of
+// its attribute values. Variants start without stock and inherit the
+// product's name, category and price.
+func (r *ProductsPostgresRepository) CreateProduct(ctx context.Context, p *domain.Product, username string) (int64, error) {
+	attrs := make([]attribute, len(p.Attributes))
+	for i, a := range p.Attributes {
+		attrs[i] = attribute{Name: a.Name, Values: a.Values}
+	}
+	attrsJSON, err := json.Marshal(attrs)
+	if err != nil {
+		return 0, fmt.Errorf("encode attributes: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username); err != nil {
+		return 0, fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO products (name, sku, category, price, currency, attributes)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6) RETURNING id, created_at, updated_at`,
+		p.Name, p.SKU, p.Category, p.Price.Amount, p.Price.Currency, string(attrsJSON),
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrProductExists
+		}
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return 0, fmt.Errorf("%w: unknown category %q", customErr.ErrInvalidInput, p.Category)
+		}
+		return 0, fmt.Errorf("%w: failed to insert product: %v", customErr.ErrDatabase, err)
+	}
+
+	p.Variants = p.Variants[:0]
+	for _, values := range p.Matrix() {
+		variantJSON, err := json.Marshal(values)
+		if err != nil {
+			return 0, fmt.Errorf("encode variant: %w", err)
+		}
+		item := &domain.Item{
+			Name:      p.Name,
+			SKU:       p.VariantSKU(values),
+			Price:     p.Price,
+			Category:  p.Category,
+			ProductID: &p.ID,
+			Variant:   values,
+		}
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO items (name, sku, quantity, price, currency, category, location, product_id, variant)
+			VALUES ($1, $2, 0, $3, $4, NULLIF($5, ''), '', $6, $7) RETURNING id`,
+			item.Name, item.SKU, item.Price.Amount, item.Price.Currency, item.Category, p.ID, string(variantJSON),
+		).Scan(&item.ID)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return 0, fmt.Errorf("%w: variant SKU %s is taken", customErr.ErrProductExists, item.SKU)
+			}
+			return 0, fmt.Errorf("%w: failed to insert variant: %v", customErr.ErrDatabase, err)
+		}
+		p.Variants = append(p.Variants, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return p.ID, nil
+}
+
+func (r *ProductsPostgresRepository) GetProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, int, error) {
+	whereClause := ""
+	var args []interface{}
+	argIndex := 1
+	if filter.Search != "" {
+		whereClause = "WHERE name ILIKE $1 OR sku ILIKE $1"
+		args = append(args, "%"+filter.Search+"%")
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM products %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count products error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total products error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Product{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`%s %s
+		ORDER BY name, id
+		LIMIT $%d OFFSET $%d`, productColumns, whereClause, argIndex, argIndex+1)
+	products, err := r.queryProducts(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// GetProductsByIDs returns the products with the given ids, without their
+// variants.
+func (r *ProductsPostgresRepository) GetProductsByIDs(ctx context.Context, ids []int64) ([]*domain.Product, error) {
+	if len(ids) == 0 {
+		return []*domain.Product{}, nil
+	}
+	return r.queryProducts(ctx, productColumns+` WHERE id = ANY($1) ORDER BY id`, pq.Array(ids))
+}
+
+func (r *ProductsPostgresRepository) GetProductByID(ctx context.Context, id int64) (*domain.Product, error) {
+	products, err := r.queryProducts(ctx, productColumns+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, customErr.ErrProductNotFound
+	}
+	p := products[0]
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT id, name, sku, quantity, price, currency, COALESCE(category, ''), variant, created_at, updated_at
+		FROM items WHERE product_id = $1
+		ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query variants error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		item := &domain.Item{ProductID: &p.ID}
+		var variant []byte
+		err := rows.Scan(&item.ID, &item.Name, &item.SKU, &item.Quantity, &item.Price.Amount, &item.Price.Currency,
+			&item.Category, &variant, &item.CreatedAt, &item.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan variant error: %v", customErr.ErrDatabase, err)
+		}
+		item.Price.Currency = strings.TrimSpace(item.Price.Currency)
+		if variant != nil {
+			if err := json.Unmarshal(variant, &item.Variant); err != nil {
+				return nil, fmt.Errorf("decode variant: %w", err)
+			}
+		}
+		p.Variants = append(p.Variants, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: variants rows error: %v", customErr.ErrDatabase, err)
+	}
+	return p, nil
+}
+
+// UpdateProduct changes the shared fields of the product and passes each
+// change on to the variants that have not overridden the field, i.e. whose
+// value still equals the product's old one.
+func (r *ProductsPostgresRepository) UpdateProduct(ctx context.Context, p *domain.Product, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username); err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	var old domain.Product
+	err = tx.QueryRowContext(ctx,
+		`SELECT name, COALESCE(category, ''), price, currency FROM products WHERE id = $1 FOR UPDATE`, p.ID,
+	).Scan(&old.Name, &old.Category, &old.Price.Amount, &old.Price.Currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrProductNotFound
+		}
+		return fmt.Errorf("%w: failed to lock product: %v", customErr.ErrDatabase, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE products SET name = $1, category = NULLIF($2, ''), price = $3, currency = $4, updated_at = NOW()
+		WHERE id = $5 RETURNING updated_at`,
+		p.Name, p.Category, p.Price.Amount, p.Price.Currency, p.ID,
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return fmt.Errorf("%w: unknown category %q", customErr.ErrInvalidInput, p.Category)
+		}
+		return fmt.Errorf("%w: failed to update product: %v", customErr.ErrDatabase, err)
+	}
+
+	inherit := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE items SET name = $1, updated_at = NOW() WHERE product_id = $2 AND name = $3`,
+			[]interface{}{p.Name, p.ID, old.Name}},
+		{`UPDATE items SET category = NULLIF($1, ''), updated_at = NOW() WHERE product_id = $2 AND COALESCE(category, '') = $3`,
+			[]interface{}{p.Category, p.ID, old.Category}},
+		{`UPDATE items SET price = $1, currency = $2, updated_at = NOW() WHERE product_id = $3 AND price = $4 AND currency = $5`,
+			[]interface{}{p.Price.Amount, p.Price.Currency, p.ID, old.Price.Amount, old.Price.Currency}},
+	}
+	for _, u := range inherit {
+		if _, err := tx.ExecContext(ctx, u.query, u.args...); err != nil {
+			return fmt.Errorf("%w: failed to update variants: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteProduct removes a product whose variants have all been deleted.
+func (r *ProductsPostgresRepository) DeleteProduct(ctx context.Context, id int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `DELETE FROM products WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return fmt.Errorf("%w: the product still has variants", customErr.ErrInvalidInput)
+		}
+		return fmt.Errorf("%w: delete product error: %v", customErr.ErrDatabase, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return customErr.ErrProductNotFound
+	}
+	return nil
+}
+
+func (r *ProductsPostgresRepository) queryProducts(ctx context.Context, query string, args ...interface{}) ([]*domain.Product, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query products error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	products := make([]*domain.Product, 0)
+	for rows.Next() {
+		p := &domain.Product{}
+		var attrsJSON []byte
+		err := rows.Scan(&p.ID, &p.Name, &p.SKU, &p.Category, &p.Price.Amount, &p.Price.Currency, &attrsJSON, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan product error: %v", customErr.ErrDatabase, err)
+		}
+		p.Price.Currency = strings.TrimSpace(p.Price.Currency)
+		var attrs []attribute
+		if err := json.Unmarshal(attrsJSON, &attrs); err != nil {
+			return nil, fmt.Errorf("decode attributes: %w", err)
+		}
+		for _, a := range attrs {
+			p.Attributes = append(p.Attributes, domain.VariantAttribute{Name: a.Name, Values: a.Values})
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return products, nil
+}
@@ -0,0 +1,367 @@
+package purchase_orders_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const uniqueViolation = "23505"
+
+const orderColumns = `
+	SELECT o.id, o.supplier_id, s.name, o.number, o.status, o.expected_at, o.notes,
+	       o.created_by, o.created_at, o.updated_at, o.closed_at
+	FROM purchase_orders o
+	JOIN suppliers s ON s.id = o.supplier_id`
+
+// movementApplier books stock movements inside a caller's transaction.
+type movementApplier interface {
+	ApplyMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error
+}
+
+type PurchaseOrdersPostgresRepository struct {
+	db        *dbpg.DB
+	retries   retry.Strategy
+	movements movementApplier
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, movements movementApplier) *PurchaseOrdersPostgresRepository {
+	return &PurchaseOrdersPostgresRepository{db: db, retries: retries, movements: movements}
+}
+
+// CreatePurchaseOrder inserts the order and its lines, resolving each line's
+// SKU to an item.
+func (r *PurchaseOrdersPostgresRepository) CreatePurchaseOrder(ctx context.Context, po *domain.PurchaseOrder) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var supplierExists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM suppliers WHERE id = $1)`, po.SupplierID).Scan(&supplierExists)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to check supplier: %v", customErr.ErrDatabase, err)
+	}
+	if !supplierExists {
+		return 0, customErr.ErrSupplierNotFound
+	}
+
+	po.Status = domain.PurchaseOrderOpen
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO purchase_orders (supplier_id, number, status, expected_at, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		po.SupplierID, po.Number, po.Status, po.ExpectedAt, po.Notes, po.CreatedBy,
+	).Scan(&po.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrPurchaseOrderExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert purchase order: %v", customErr.ErrDatabase, err)
+	}
+
+	for _, line := range po.Lines {
+		err := tx.QueryRowContext(ctx, `SELECT id FROM items WHERE sku = $1`, line.SKU).Scan(&line.ItemID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, fmt.Errorf("%w: unknown SKU %s", customErr.ErrItemNotFound, line.SKU)
+			}
+			return 0, fmt.Errorf("%w: failed to resolve SKU: %v", customErr.ErrDatabase, err)
+		}
+		line.OrderID = po.ID
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO purchase_order_lines (order_id, item_id, sku, quantity, unit_price)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			line.OrderID, line.ItemID, line.SKU, line.Quantity, line.UnitPrice,
+		).Scan(&line.ID)
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to insert purchase order line: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return po.ID, nil
+}
+
+func (r *PurchaseOrdersPostgresRepository) GetPurchaseOrders(ctx context.Context, filter domain.PurchaseOrderFilter) ([]*domain.PurchaseOrder, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.SupplierID != nil {
+		conditions = append(conditions, fmt.Sprintf("o.supplier_id = $%d", argIndex))
+		args = append(args, *filter.SupplierID)
+		argIndex++
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("o.status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM purchase_orders o %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count purchase orders error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total purchase orders error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.PurchaseOrder{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`%s %s ORDER BY o.created_at DESC, o.id DESC LIMIT $%d OFFSET $%d`,
+		orderColumns, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query purchase orders error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	orders := make([]*domain.PurchaseOrder, 0, filter.Limit)
+	for rows.Next() {
+		po, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan purchase order error: %v", customErr.ErrDatabase, err)
+		}
+		orders = append(orders, po)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	return orders, total, nil
+}
+
+func (r *PurchaseOrdersPostgresRepository) GetPurchaseOrderByID(ctx context.Context, id int64) (*domain.PurchaseOrder, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, orderColumns+` WHERE o.id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	po, err := scanOrder(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrPurchaseOrderNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT l.id, l.order_id, l.item_id, l.sku, i.name, l.quantity, l.received_quantity, l.unit_price
+		FROM purchase_order_lines l
+		JOIN items i ON i.id = l.item_id
+		WHERE l.order_id = $1
+		ORDER BY l.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select purchase order lines error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	po.Lines = make([]*domain.PurchaseOrderLine, 0)
+	for rows.Next() {
+		l := &domain.PurchaseOrderLine{}
+		if err := rows.Scan(&l.ID, &l.OrderID, &l.ItemID, &l.SKU, &l.ItemName, &l.Quantity, &l.ReceivedQuantity, &l.UnitPrice); err != nil {
+			return nil, fmt.Errorf("%w: scan purchase order line error: %v", customErr.ErrDatabase, err)
+		}
+		po.Lines = append(po.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return po, nil
+}
+
+// CancelPurchaseOrder cancels an order nothing has been received against yet.
+func (r *PurchaseOrdersPostgresRepository) CancelPurchaseOrder(ctx context.Context, id int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE purchase_orders SET status = 'cancelled', updated_at = NOW(), closed_at = NOW()
+		WHERE id = $1 AND status = 'open'`, id)
+	if err != nil {
+		return fmt.Errorf("%w: cancel failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: cancel failed: %v", customErr.ErrDatabase, err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `SELECT EXISTS (SELECT 1 FROM purchase_orders WHERE id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if !exists {
+		return customErr.ErrPurchaseOrderNotFound
+	}
+	return customErr.ErrPurchaseOrderClosed
+}
+
+// ReceivePurchaseOrder books receipts against order lines in one transaction:
+// each receipt is booked as a receipt movement linked to its line, into the
+// bin, lot and stock status it names, and counts towards the line's received
+// quantity. Receiving more than a line's
+// remaining quantity fails with ErrOverReceipt unless allowOver is set. The
+// order is closed once every line is fully received. It returns the ids of
+// the received items.
+func (r *PurchaseOrdersPostgresRepository) ReceivePurchaseOrder(ctx context.Context, id int64, receipts []*domain.PurchaseOrderReceipt, allowOver bool, username string) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	var number string
+	var status domain.PurchaseOrderStatus
+	err = tx.QueryRowContext(ctx, `SELECT number, status FROM purchase_orders WHERE id = $1 FOR UPDATE`, id).Scan(&number, &status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrPurchaseOrderNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to lock purchase order: %v", customErr.ErrDatabase, err)
+	}
+	if !status.IsOpen() {
+		return nil, customErr.ErrPurchaseOrderClosed
+	}
+
+	var received []int64
+	for _, rc := range receipts {
+		itemID, err := r.receiveLine(ctx, tx, id, number, rc, allowOver, username)
+		if err != nil {
+			return nil, err
+		}
+		received = append(received, itemID)
+	}
+
+	var outstanding bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM purchase_order_lines WHERE order_id = $1 AND received_quantity < quantity)`, id,
+	).Scan(&outstanding)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to check outstanding lines: %v", customErr.ErrDatabase, err)
+	}
+	if outstanding {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE purchase_orders SET status = 'partially_received', updated_at = NOW() WHERE id = $1`, id)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE purchase_orders SET status = 'received', updated_at = NOW(), closed_at = NOW() WHERE id = $1`, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update purchase order status: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return received, nil
+}
+
+func (r *PurchaseOrdersPostgresRepository) receiveLine(ctx context.Context, tx *sql.Tx, orderID int64, number string, rc *domain.PurchaseOrderReceipt, allowOver bool, username string) (int64, error) {
+	line := &domain.PurchaseOrderLine{}
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, item_id, sku, quantity, received_quantity, unit_price FROM purchase_order_lines
+		WHERE id = $1 AND order_id = $2 FOR UPDATE`, rc.LineID, orderID,
+	).Scan(&line.ID, &line.ItemID, &line.SKU, &line.Quantity, &line.ReceivedQuantity, &line.UnitPrice)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: line %d does not belong to purchase order %s", customErr.ErrInvalidInput, rc.LineID, number)
+		}
+		return 0, fmt.Errorf("%w: failed to lock purchase order line: %v", customErr.ErrDatabase, err)
+	}
+	if rc.Quantity > line.Remaining() && !allowOver {
+		return 0, fmt.Errorf("%w: %d of %s received, only %d outstanding", customErr.ErrOverReceipt, rc.Quantity, line.SKU, line.Remaining())
+	}
+
+	var serialized bool
+	err = tx.QueryRowContext(ctx, `SELECT serialized FROM items WHERE id = $1 FOR UPDATE`, line.ItemID).Scan(&serialized)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, customErr.ErrItemNotFound
+		}
+		return 0, fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+	if serialized {
+		return 0, fmt.Errorf("%w: %s is serialized, receive it as a movement with serial numbers", customErr.ErrInvalidInput, line.SKU)
+	}
+
+	// Received goods are costed at the price agreed on the order line.
+	m := &domain.StockMovement{
+		ItemID:              line.ItemID,
+		Type:                domain.MovementReceipt,
+		Quantity:            rc.Quantity,
+		ReasonCode:          "purchase_order",
+		Reference:           number,
+		ToLocationID:        rc.LocationID,
+		LotNumber:           rc.LotNumber,
+		ManufacturedAt:      rc.ManufacturedAt,
+		ExpiresAt:           rc.ExpiresAt,
+		UnitCost:            &line.UnitPrice,
+		StockStatus:         rc.StockStatus,
+		PurchaseOrderLineID: &line.ID,
+	}
+	if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE purchase_order_lines SET received_quantity = received_quantity + $1 WHERE id = $2`, rc.Quantity, line.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to update purchase order line: %v", customErr.ErrDatabase, err)
+	}
+	return line.ItemID, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrder(s scanner) (*domain.PurchaseOrder, error) {
+	po := &domain.PurchaseOrder{}
+	var expectedAt, closedAt sql.NullTime
+	err := s.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Number, &po.Status, &expectedAt, &po.Notes,
+		&po.CreatedBy, &po.CreatedAt, &po.UpdatedAt, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expectedAt.Valid {
+		po.ExpectedAt = &expectedAt.Time
+	}
+	if closedAt.Valid {
+		po.ClosedAt = &closedAt.Time
+	}
+	return po, nil
+}
@@ -0,0 +1,183 @@
+package reservations_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type ReservationsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *ReservationsPostgresRepository {
+	return &ReservationsPostgresRepository{db: db, retries: retries}
+}
+
+// CreateReservation locks the item row before checking availability, so
+// concurrent reservations for the same item are serialised and can never
+// hold more than the on-hand quantity between them.
+func (r *ReservationsPostgresRepository) CreateReservation(ctx context.Context, res *domain.Reservation) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var quantity, segregated int
+	err = tx.QueryRowContext(ctx,
+		`SELECT quantity, quarantine_quantity + damaged_quantity + blocked_quantity FROM items WHERE id = $1 FOR UPDATE`, res.ItemID,
+	).Scan(&quantity, &segregated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, customErr.ErrItemNotFound
+		}
+		return 0, fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+
+	var reserved int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(quantity - fulfilled_quantity), 0) FROM reservations
+		WHERE item_id = $1 AND status = 'active' AND expires_at > NOW()`, res.ItemID,
+	).Scan(&reserved)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to sum reservations: %v", customErr.ErrDatabase, err)
+	}
+
+	if quantity-segregated-reserved < res.Quantity {
+		return 0, customErr.ErrInsufficientStock
+	}
+
+	query := `INSERT INTO reservations (item_id, quantity, reference, status, expires_at, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+
+	res.Status = domain.ReservationActive
+	err = tx.QueryRowContext(ctx, query,
+		res.ItemID, res.Quantity, res.Reference, res.Status, res.ExpiresAt, res.CreatedBy,
+	).Scan(&res.ID, &res.CreatedAt, &res.UpdatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to insert reservation: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return res.ID, nil
+}
+
+func (r *ReservationsPostgresRepository) GetReservations(ctx context.Context, filter domain.ReservationFilter) ([]*domain.Reservation, int, error) {
+	conditions := []string{"item_id = $1"}
+	args := []interface{}{filter.ItemID}
+	argIndex := 2
+
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM reservations %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count reservations error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total reservations error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Reservation{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, quantity, fulfilled_quantity, reference, status, expires_at, created_by, created_at, updated_at
+		FROM reservations
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: select reservations error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	reservations := make([]*domain.Reservation, 0, filter.Limit)
+	for rows.Next() {
+		res := &domain.Reservation{}
+		err := rows.Scan(&res.ID, &res.ItemID, &res.Quantity, &res.FulfilledQuantity, &res.Reference,
+			&res.Status, &res.ExpiresAt, &res.CreatedBy, &res.CreatedAt, &res.UpdatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan reservation error: %v", customErr.ErrDatabase, err)
+		}
+		reservations = append(reservations, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: reservations rows error: %v", customErr.ErrDatabase, err)
+	}
+
+	return reservations, total, nil
+}
+
+func (r *ReservationsPostgresRepository) ReleaseReservation(ctx context.Context, itemID, id int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE reservations SET status = 'released', updated_at = NOW()
+		WHERE id = $1 AND item_id = $2 AND status = 'active'`, id, itemID)
+	if err != nil {
+		return fmt.Errorf("%w: release failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: release failed: %v", customErr.ErrDatabase, err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries,
+		`SELECT EXISTS (SELECT 1 FROM reservations WHERE id = $1 AND item_id = $2)`, id, itemID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if !exists {
+		return customErr.ErrReservationNotFound
+	}
+	return customErr.ErrReservationClosed
+}
+
+// ReleaseExpired marks active reservations past their expiry as expired.
+// Availability checks already ignore them; this only keeps statuses honest.
+func (r *ReservationsPostgresRepository) ReleaseExpired(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE reservations SET status = 'expired', updated_at = NOW()
+		WHERE status = 'active' AND expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("%w: expire reservations failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: expire reservations failed: %v", customErr.ErrDatabase, err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,644 @@
+package returns_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const uniqueViolation = "23505"
+
+const returnColumns = `
+	SELECT id, number, order_id, customer, reason, status, created_by, created_at, updated_at, received_at, closed_at
+	FROM returns`
+
+// movementApplier books stock movements inside a caller's transaction.
+type movementApplier interface {
+	ApplyMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error
+}
+
+type ReturnsPostgresRepository struct {
+	db        *dbpg.DB
+	retries   retry.Strategy
+	movements movementApplier
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, movements movementApplier) *ReturnsPostgresRepository {
+	return &ReturnsPostgresRepository{db: db, retries: retries, movements: movements}
+}
+
+// CreateReturn authorises a return. Against an order, every SKU must have
+// been shipped on it, and the returns raised for the order can never add up
+// to more than was shipped; the order row lock serialises them.
+func (r *ReturnsPostgresRepository) CreateReturn(ctx context.Context, ret *domain.Return) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if ret.OrderID != nil {
+		var status domain.OrderStatus
+		var customer string
+		err := tx.QueryRowContext(ctx,
+			`SELECT status, customer FROM orders WHERE id = $1 FOR UPDATE`, *ret.OrderID,
+		).Scan(&status, &customer)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, customErr.ErrOrderNotFound
+			}
+			return 0, fmt.Errorf("%w: failed to lock order: %v", customErr.ErrDatabase, err)
+		}
+		if status != domain.OrderShipped {
+			return 0, fmt.Errorf("%w: only shipped orders can be returned, the order is %s", customErr.ErrInvalidInput, status)
+		}
+		if ret.Customer == "" {
+			ret.Customer = customer
+		}
+	}
+
+	ret.Status = domain.ReturnOpen
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO returns (number, order_id, customer, reason, status, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		ret.Number, ret.OrderID, ret.Customer, ret.Reason, ret.Status, ret.CreatedBy,
+	).Scan(&ret.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrReturnExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert return: %v", customErr.ErrDatabase, err)
+	}
+
+	for _, line := range ret.Lines {
+		err := tx.QueryRowContext(ctx, `SELECT id FROM items WHERE sku = $1`, line.SKU).Scan(&line.ItemID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, fmt.Errorf("%w: unknown SKU %s", customErr.ErrItemNotFound, line.SKU)
+			}
+			return 0, fmt.Errorf("%w: failed to resolve SKU: %v", customErr.ErrDatabase, err)
+		}
+		if ret.OrderID != nil {
+			if err := r.checkReturnable(ctx, tx, *ret.OrderID, line); err != nil {
+				return 0, err
+			}
+		}
+		line.ReturnID = ret.ID
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO return_lines (return_id, item_id, sku, quantity) VALUES ($1, $2, $3, $4) RETURNING id`,
+			line.ReturnID, line.ItemID, line.SKU, line.Quantity,
+		).Scan(&line.ID)
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to insert return line: %v", customErr.ErrDatabase, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return ret.ID, nil
+}
+
+// checkReturnable compares a line with what the order shipped of the item,
+// less what earlier returns against the order already claim.
+func (r *ReturnsPostgresRepository) checkReturnable(ctx context.Context, tx *sql.Tx, orderID int64, line *domain.ReturnLine) error {
+	var onOrder bool
+	var shipped, returned int
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM order_lines WHERE order_id = $1 AND item_id = $2),
+		       (SELECT COALESCE(SUM(p.quantity), 0) FROM order_picks p
+		        JOIN order_lines l ON l.id = p.line_id
+		        WHERE l.order_id = $1 AND l.item_id = $2),
+		       (SELECT COALESCE(SUM(rl.quantity), 0) FROM return_lines rl
+		        JOIN returns rt ON rt.id = rl.return_id
+		        WHERE rt.order_id = $1 AND rt.status <> 'cancelled' AND rl.item_id = $2)`,
+		orderID, line.ItemID,
+	).Scan(&onOrder, &shipped, &returned)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check returnable quantity: %v", customErr.ErrDatabase, err)
+	}
+	if !onOrder {
+		return fmt.Errorf("%w: %s was not shipped on the order", customErr.ErrInvalidInput, line.SKU)
+	}
+	if line.Quantity > shipped-returned {
+		return fmt.Errorf("%w: %d of %s returned, only %d returnable", customErr.ErrInvalidInput, line.Quantity, line.SKU, shipped-returned)
+	}
+	return nil
+}
+
+func (r *ReturnsPostgresRepository) GetReturns(ctx context.Context, filter domain.ReturnFilter) ([]*domain.Return, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+	if filter.OrderID != nil {
+		conditions = append(conditions, fmt.Sprintf("order_id = $%d", argIndex))
+		args = append(args, *filter.OrderID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM returns %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count returns error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total returns error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Return{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`%s %s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		returnColumns, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query returns error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	returns := make([]*domain.Return, 0, filter.Limit)
+	for rows.Next() {
+		ret, err := scanReturn(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan return error: %v", customErr.ErrDatabase, err)
+		}
+		returns = append(returns, ret)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	return returns, total, nil
+}
+
+// GetReturnByID returns the return with its lines and their dispositions.
+func (r *ReturnsPostgresRepository) GetReturnByID(ctx context.Context, id int64) (*domain.Return, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, returnColumns+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	ret, err := scanReturn(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrReturnNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT l.id, l.return_id, l.item_id, l.sku, i.name, l.quantity, l.received_quantity,
+		       l.location_id, COALESCE(loc.path, ''), l.serials
+		FROM return_lines l
+		JOIN items i ON i.id = l.item_id
+		LEFT JOIN locations loc ON loc.id = l.location_id
+		WHERE l.return_id = $1
+		ORDER BY l.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select return lines error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	ret.Lines = make([]*domain.ReturnLine, 0)
+	byID := make(map[int64]*domain.ReturnLine)
+	for rows.Next() {
+		l := &domain.ReturnLine{}
+		var locationID sql.NullInt64
+		if err := rows.Scan(&l.ID, &l.ReturnID, &l.ItemID, &l.SKU, &l.ItemName, &l.Quantity, &l.ReceivedQuantity,
+			&locationID, &l.LocationPath, pq.Array(&l.Serials)); err != nil {
+			return nil, fmt.Errorf("%w: scan return line error: %v", customErr.ErrDatabase, err)
+		}
+		if locationID.Valid {
+			l.LocationID = &locationID.Int64
+		}
+		ret.Lines = append(ret.Lines, l)
+		byID[l.ID] = l
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	dispositionRows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT d.id, d.line_id, d.disposition, d.quantity, d.serials, d.supplier_id, d.created_by, d.created_at
+		FROM return_dispositions d
+		JOIN return_lines l ON l.id = d.line_id
+		WHERE l.return_id = $1
+		ORDER BY d.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select return dispositions error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = dispositionRows.Close() }()
+
+	for dispositionRows.Next() {
+		d, err := scanDisposition(dispositionRows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan return disposition error: %v", customErr.ErrDatabase, err)
+		}
+		if l, ok := byID[d.LineID]; ok {
+			l.Dispositions = append(l.Dispositions, d)
+		}
+	}
+	if err := dispositionRows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return ret, nil
+}
+
+// ReceiveReturn books the goods that arrived into quarantine: each receipt is
+// a quarantine receipt movement, so the stock is on hand but not available.
+// Lines without a receipt are taken to have arrived empty. It returns the
+// ids of the received items.
+func (r *ReturnsPostgresRepository) ReceiveReturn(ctx context.Context, id int64, receipts []*domain.ReturnReceipt, username string) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return nil, err
+	}
+
+	number, err := r.lockReturn(ctx, tx, id, domain.ReturnOpen)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := r.lockLines(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rc := range receipts {
+		line, ok := lines[rc.LineID]
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d does not belong to return %s", customErr.ErrInvalidInput, rc.LineID, number)
+		}
+		if rc.Quantity > line.Quantity {
+			return nil, fmt.Errorf("%w: %d of %s received, only %d authorised", customErr.ErrInvalidInput, rc.Quantity, line.SKU, line.Quantity)
+		}
+	}
+
+	// Movements lock their item, so receipts are booked in item order.
+	sort.Slice(receipts, func(i, j int) bool {
+		return lines[receipts[i].LineID].ItemID < lines[receipts[j].LineID].ItemID
+	})
+
+	var received []int64
+	for _, rc := range receipts {
+		line := lines[rc.LineID]
+		m := &domain.StockMovement{
+			ItemID:       line.ItemID,
+			Type:         domain.MovementReceipt,
+			Quantity:     rc.Quantity,
+			ReasonCode:   "return",
+			Reference:    number,
+			ToLocationID: rc.LocationID,
+			Serials:      rc.Serials,
+			StockStatus:  domain.StockQuarantine,
+		}
+		if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE return_lines SET received_quantity = $1, location_id = $2, serials = $3 WHERE id = $4`,
+			rc.Quantity, rc.LocationID, pq.Array(rc.Serials), line.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to update return line: %v", customErr.ErrDatabase, err)
+		}
+		received = append(received, line.ItemID)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE returns SET status = 'received', received_at = NOW(), updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update return: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return received, nil
+}
+
+// DispositionReturn takes quarantined goods out of quarantine. Restocked goods
+// become available where they are; scrapped goods and goods returned to the
+// vendor leave stock through a quarantine issue movement. The return closes
+// once nothing is left in quarantine. It returns the ids of the affected
+// items.
+func (r *ReturnsPostgresRepository) DispositionReturn(ctx context.Context, id int64, dispositions []*domain.ReturnDisposition, username string) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.setAuditUser(ctx, tx, username); err != nil {
+		return nil, err
+	}
+
+	number, err := r.lockReturn(ctx, tx, id, domain.ReturnReceived)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := r.lockLines(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range dispositions {
+		line, ok := lines[d.LineID]
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d does not belong to return %s", customErr.ErrInvalidInput, d.LineID, number)
+		}
+		if err := takeFromQuarantine(line, d); err != nil {
+			return nil, err
+		}
+		if d.SupplierID != nil {
+			var exists bool
+			err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM suppliers WHERE id = $1)`, *d.SupplierID).Scan(&exists)
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to check supplier: %v", customErr.ErrDatabase, err)
+			}
+			if !exists {
+				return nil, customErr.ErrSupplierNotFound
+			}
+		}
+	}
+
+	sort.Slice(dispositions, func(i, j int) bool {
+		return lines[dispositions[i].LineID].ItemID < lines[dispositions[j].LineID].ItemID
+	})
+
+	var affected []int64
+	for _, d := range dispositions {
+		line := lines[d.LineID]
+		if d.Disposition == domain.DispositionRestock {
+			res, err := tx.ExecContext(ctx, `
+				UPDATE items SET quarantine_quantity = quarantine_quantity - $1, updated_at = NOW()
+				WHERE id = $2 AND quarantine_quantity >= $1`, d.Quantity, line.ItemID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to release quarantine: %v", customErr.ErrDatabase, err)
+			}
+			rows, err := res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to release quarantine: %v", customErr.ErrDatabase, err)
+			}
+			if rows == 0 {
+				return nil, customErr.ErrInsufficientStock
+			}
+		} else {
+			m := &domain.StockMovement{
+				ItemID:         line.ItemID,
+				Type:           domain.MovementIssue,
+				Quantity:       d.Quantity,
+				ReasonCode:     string(d.Disposition),
+				Reference:      number,
+				FromLocationID: line.LocationID,
+				Serials:        d.Serials,
+				StockStatus:    domain.StockQuarantine,
+			}
+			if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+				return nil, err
+			}
+		}
+
+		d.CreatedBy = username
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO return_dispositions (line_id, disposition, quantity, serials, supplier_id, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+			d.LineID, d.Disposition, d.Quantity, pq.Array(d.Serials), d.SupplierID, d.CreatedBy,
+		).Scan(&d.ID, &d.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to insert return disposition: %v", customErr.ErrDatabase, err)
+		}
+		if len(affected) == 0 || affected[len(affected)-1] != line.ItemID {
+			affected = append(affected, line.ItemID)
+		}
+	}
+
+	closed := true
+	for _, line := range lines {
+		if line.Quarantined() > 0 {
+			closed = false
+			break
+		}
+	}
+	if closed {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE returns SET status = 'closed', closed_at = NOW(), updated_at = NOW() WHERE id = $1`, id)
+	} else {
+		_, err = tx.ExecContext(ctx, `UPDATE returns SET updated_at = NOW() WHERE id = $1`, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to update return: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return affected, nil
+}
+
+// takeFromQuarantine checks a disposition against what is left of the line in
+// quarantine and records it on the line. Serialized goods are dispositioned
+// by naming serials that were received on the line and are still
+// quarantined.
+func takeFromQuarantine(line *domain.ReturnLine, d *domain.ReturnDisposition) error {
+	if d.Quantity > line.Quarantined() {
+		return fmt.Errorf("%w: %d of %s dispositioned, only %d in quarantine", customErr.ErrInvalidInput, d.Quantity, line.SKU, line.Quarantined())
+	}
+	if len(line.Serials) == 0 {
+		if len(d.Serials) > 0 {
+			return fmt.Errorf("%w: %s was received without serial numbers", customErr.ErrInvalidInput, line.SKU)
+		}
+	} else {
+		if len(d.Serials) != d.Quantity {
+			return fmt.Errorf("%w: serialized item requires %d serial numbers, got %d", customErr.ErrInvalidInput, d.Quantity, len(d.Serials))
+		}
+		for _, sn := range d.Serials {
+			if !slices.Contains(line.Serials, sn) {
+				return fmt.Errorf("%w: %s was not received on the return", customErr.ErrInvalidInput, sn)
+			}
+			for _, prev := range line.Dispositions {
+				if slices.Contains(prev.Serials, sn) {
+					return fmt.Errorf("%w: %s has already been dispositioned", customErr.ErrInvalidInput, sn)
+				}
+			}
+		}
+	}
+	line.Dispositions = append(line.Dispositions, d)
+	return nil
+}
+
+// CancelReturn cancels a return whose goods have not arrived yet.
+func (r *ReturnsPostgresRepository) CancelReturn(ctx context.Context, id int64) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `
+		UPDATE returns SET status = 'cancelled', updated_at = NOW(), closed_at = NOW()
+		WHERE id = $1 AND status = 'open'`, id)
+	if err != nil {
+		return fmt.Errorf("%w: cancel failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: cancel failed: %v", customErr.ErrDatabase, err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `SELECT EXISTS (SELECT 1 FROM returns WHERE id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if !exists {
+		return customErr.ErrReturnNotFound
+	}
+	return customErr.ErrReturnClosed
+}
+
+// lockReturn locks the return and checks that it is in the given status. It
+// returns the return number.
+func (r *ReturnsPostgresRepository) lockReturn(ctx context.Context, tx *sql.Tx, id int64, status domain.ReturnStatus) (string, error) {
+	var number string
+	var current domain.ReturnStatus
+	err := tx.QueryRowContext(ctx, `SELECT number, status FROM returns WHERE id = $1 FOR UPDATE`, id).Scan(&number, &current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", customErr.ErrReturnNotFound
+		}
+		return "", fmt.Errorf("%w: failed to lock return: %v", customErr.ErrDatabase, err)
+	}
+	if current != status {
+		return "", customErr.ErrReturnClosed
+	}
+	return number, nil
+}
+
+// lockLines reads the lines of a locked return, with their dispositions so
+// far, keyed by line id.
+func (r *ReturnsPostgresRepository) lockLines(ctx context.Context, tx *sql.Tx, id int64) (map[int64]*domain.ReturnLine, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, item_id, sku, quantity, received_quantity, location_id, serials
+		FROM return_lines WHERE return_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select return lines error: %v", customErr.ErrDatabase, err)
+	}
+	lines := make(map[int64]*domain.ReturnLine)
+	for rows.Next() {
+		l := &domain.ReturnLine{ReturnID: id}
+		var locationID sql.NullInt64
+		if err := rows.Scan(&l.ID, &l.ItemID, &l.SKU, &l.Quantity, &l.ReceivedQuantity, &locationID, pq.Array(&l.Serials)); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("%w: scan return line error: %v", customErr.ErrDatabase, err)
+		}
+		if locationID.Valid {
+			l.LocationID = &locationID.Int64
+		}
+		lines[l.ID] = l
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT d.id, d.line_id, d.disposition, d.quantity, d.serials, d.supplier_id, d.created_by, d.created_at
+		FROM return_dispositions d
+		JOIN return_lines l ON l.id = d.line_id
+		WHERE l.return_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select return dispositions error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		d, err := scanDisposition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan return disposition error: %v", customErr.ErrDatabase, err)
+		}
+		if l, ok := lines[d.LineID]; ok {
+			l.Dispositions = append(l.Dispositions, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return lines, nil
+}
+
+func (r *ReturnsPostgresRepository) setAuditUser(ctx context.Context, tx *sql.Tx, username string) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config('warehouse_control.changed_by', $1, true)", username)
+	if err != nil {
+		return fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReturn(s scanner) (*domain.Return, error) {
+	ret := &domain.Return{}
+	var orderID sql.NullInt64
+	var receivedAt, closedAt sql.NullTime
+	err := s.Scan(&ret.ID, &ret.Number, &orderID, &ret.Customer, &ret.Reason, &ret.Status,
+		&ret.CreatedBy, &ret.CreatedAt, &ret.UpdatedAt, &receivedAt, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	if orderID.Valid {
+		ret.OrderID = &orderID.Int64
+	}
+	if receivedAt.Valid {
+		ret.ReceivedAt = &receivedAt.Time
+	}
+	if closedAt.Valid {
+		ret.ClosedAt = &closedAt.Time
+	}
+	return ret, nil
+}
+
+func scanDisposition(s scanner) (*domain.ReturnDisposition, error) {
+	d := &domain.ReturnDisposition{}
+	var supplierID sql.NullInt64
+	err := s.Scan(&d.ID, &d.LineID, &d.Disposition, &d.Quantity, pq.Array(&d.Serials), &supplierID, &d.CreatedBy, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if supplierID.Valid {
+		d.SupplierID = &supplierID.Int64
+	}
+	return d, nil
+}
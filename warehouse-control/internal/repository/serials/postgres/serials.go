@@ -0,0 +1,90 @@
+package serials_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+type SerialsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *SerialsPostgresRepository {
+	return &SerialsPostgresRepository{db: db, retries: retries}
+}
+
+func (r *SerialsPostgresRepository) GetSerialByNumber(ctx context.Context, serialNumber string) (*domain.Serial, error) {
+	query := `
+		SELECT s.id, s.item_id, s.serial_number, s.status, s.location_id, COALESCE(l.path, ''), s.created_at, s.updated_at
+		FROM serials s
+		LEFT JOIN locations l ON l.id = s.location_id
+		WHERE s.serial_number = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, serialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	s := &domain.Serial{}
+	var locationID sql.NullInt64
+	err = row.Scan(&s.ID, &s.ItemID, &s.SerialNumber, &s.Status, &locationID, &s.LocationPath, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrSerialNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if locationID.Valid {
+		s.LocationID = &locationID.Int64
+	}
+	return s, nil
+}
+
+// GetSerialMovements returns the ledger entries that moved the serial,
+// oldest first.
+func (r *SerialsPostgresRepository) GetSerialMovements(ctx context.Context, serialID int64) ([]*domain.StockMovement, error) {
+	query := `
+		SELECT m.id, m.item_id, m.movement_type, m.quantity, m.reason_code, m.reference,
+		       m.from_location_id, m.to_location_id, m.from_location, m.to_location, m.created_by, m.created_at
+		FROM stock_movements m
+		JOIN movement_serials ms ON ms.movement_id = m.id
+		WHERE ms.serial_id = $1
+		ORDER BY m.created_at, m.id`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, serialID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select serial movements error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	movements := make([]*domain.StockMovement, 0)
+	for rows.Next() {
+		m := &domain.StockMovement{}
+		var fromID, toID sql.NullInt64
+		err := rows.Scan(&m.ID, &m.ItemID, &m.Type, &m.Quantity, &m.ReasonCode, &m.Reference,
+			&fromID, &toID, &m.FromLocation, &m.ToLocation, &m.CreatedBy, &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan serial movement error: %v", customErr.ErrDatabase, err)
+		}
+		if fromID.Valid {
+			m.FromLocationID = &fromID.Int64
+		}
+		if toID.Valid {
+			m.ToLocationID = &toID.Int64
+		}
+		movements = append(movements, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: serial movements rows error: %v", customErr.ErrDatabase, err)
+	}
+	return movements, nil
+}
@@ -0,0 +1,421 @@
+package stocktakes_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// linesQuery reads session lines with the live system quantity: the bin
+// stock for bin lines, the item's on-hand quantity for item lines.
+const linesQuery = `
+	SELECT l.id, l.stocktake_id, l.item_id, i.name, i.sku, l.location_id, COALESCE(loc.path, ''),
+	       CASE WHEN l.location_id IS NULL THEN i.quantity ELSE COALESCE(s.quantity, 0) END,
+	       l.counted_quantity, COALESCE(l.counted_by, ''), l.counted_at
+	FROM stocktake_lines l
+	JOIN items i ON i.id = l.item_id
+	LEFT JOIN locations loc ON loc.id = l.location_id
+	LEFT JOIN item_stock s ON s.item_id = l.item_id AND s.location_id = l.location_id
+	WHERE l.stocktake_id = $1`
+
+// movementApplier books stock movements inside a caller's transaction.
+type movementApplier interface {
+	ApplyMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, username string) error
+}
+
+type StocktakesPostgresRepository struct {
+	db        *dbpg.DB
+	retries   retry.Strategy
+	movements movementApplier
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, movements movementApplier) *StocktakesPostgresRepository {
+	return &StocktakesPostgresRepository{db: db, retries: retries, movements: movements}
+}
+
+// CreateStocktake opens a session and generates its lines: every item held
+// in a bin below the given locations, or every item of the given categories
+// when no locations are named. Serialized items are counted by serial and are
+// left out.
+func (r *StocktakesPostgresRepository) CreateStocktake(ctx context.Context, st *domain.Stocktake) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if len(st.LocationIDs) > 0 {
+		var found int
+		err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM locations WHERE id = ANY($1)`, pq.Array(st.LocationIDs),
+		).Scan(&found)
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to check locations: %v", customErr.ErrDatabase, err)
+		}
+		if found != len(st.LocationIDs) {
+			return 0, customErr.ErrLocationNotFound
+		}
+	}
+
+	st.Status = domain.StocktakeCounting
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stocktakes (status, blind, location_ids, categories, created_by)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		st.Status, st.Blind, pq.Array(st.LocationIDs), pq.Array(st.Categories), st.CreatedBy,
+	).Scan(&st.ID, &st.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to insert stocktake: %v", customErr.ErrDatabase, err)
+	}
+
+	var res sql.Result
+	if len(st.LocationIDs) > 0 {
+		res, err = tx.ExecContext(ctx, `
+			INSERT INTO stocktake_lines (stocktake_id, item_id, location_id)
+			SELECT $1, s.item_id, s.location_id
+			FROM item_stock s
+			JOIN items i ON i.id = s.item_id
+			JOIN locations bin ON bin.id = s.location_id
+			WHERE NOT i.serialized
+			  AND (cardinality($3::TEXT[]) = 0 OR i.category = ANY($3))
+			  AND EXISTS (SELECT 1 FROM locations scope WHERE scope.id = ANY($2)
+			              AND (bin.path = scope.path OR bin.path LIKE scope.path || '/%'))`,
+			st.ID, pq.Array(st.LocationIDs), pq.Array(st.Categories))
+	} else {
+		res, err = tx.ExecContext(ctx, `
+			INSERT INTO stocktake_lines (stocktake_id, item_id)
+			SELECT $1, id FROM items WHERE NOT serialized AND category = ANY($2)`,
+			st.ID, pq.Array(st.Categories))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to insert stocktake lines: %v", customErr.ErrDatabase, err)
+	}
+	lines, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to insert stocktake lines: %v", customErr.ErrDatabase, err)
+	}
+	if lines == 0 {
+		return 0, fmt.Errorf("%w: nothing to count in the given locations and categories", customErr.ErrInvalidInput)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return st.ID, nil
+}
+
+func (r *StocktakesPostgresRepository) GetStocktakes(ctx context.Context, filter domain.StocktakeFilter) ([]*domain.Stocktake, int, error) {
+	whereClause := ""
+	var args []interface{}
+	argIndex := 1
+	if filter.Status != nil {
+		whereClause = "WHERE status = $1"
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM stocktakes %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count stocktakes error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total stocktakes error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Stocktake{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, status, blind, location_ids, categories, created_by, created_at, COALESCE(posted_by, ''), posted_at
+		FROM stocktakes %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: select stocktakes error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	stocktakes := make([]*domain.Stocktake, 0, filter.Limit)
+	for rows.Next() {
+		st, err := scanStocktake(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: scan stocktake error: %v", customErr.ErrDatabase, err)
+		}
+		stocktakes = append(stocktakes, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: stocktakes rows error: %v", customErr.ErrDatabase, err)
+	}
+
+	return stocktakes, total, nil
+}
+
+func (r *StocktakesPostgresRepository) GetStocktakeByID(ctx context.Context, id int64) (*domain.Stocktake, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `
+		SELECT id, status, blind, location_ids, categories, created_by, created_at, COALESCE(posted_by, ''), posted_at
+		FROM stocktakes WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	st, err := scanStocktake(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrStocktakeNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, linesQuery+` ORDER BY loc.path NULLS FIRST, i.name, l.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select stocktake lines error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if st.Lines, err = scanLines(rows); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// SubmitCounts records counted quantities. Counters work concurrently; the
+// session row is only share-locked so posting waits for in-flight counts.
+func (r *StocktakesPostgresRepository) SubmitCounts(ctx context.Context, id int64, counts []*domain.StocktakeCount, username string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var status domain.StocktakeStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM stocktakes WHERE id = $1 FOR SHARE`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrStocktakeNotFound
+		}
+		return fmt.Errorf("%w: failed to lock stocktake: %v", customErr.ErrDatabase, err)
+	}
+	if status != domain.StocktakeCounting {
+		return customErr.ErrStocktakeClosed
+	}
+
+	for _, cnt := range counts {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE stocktake_lines SET counted_quantity = $1, counted_by = $2, counted_at = NOW()
+			WHERE stocktake_id = $3 AND item_id = $4 AND location_id IS NOT DISTINCT FROM $5`,
+			cnt.Quantity, username, id, cnt.ItemID, cnt.LocationID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to record count: %v", customErr.ErrDatabase, err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%w: failed to record count: %v", customErr.ErrDatabase, err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("%w: item %d is not counted at that location in this session", customErr.ErrInvalidInput, cnt.ItemID)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetStocktakeStatus moves a session to status when it is currently in one
+// of from.
+func (r *StocktakesPostgresRepository) SetStocktakeStatus(ctx context.Context, id int64, from []domain.StocktakeStatus, status domain.StocktakeStatus) error {
+	allowed := make([]string, len(from))
+	for i, s := range from {
+		allowed[i] = string(s)
+	}
+	res, err := r.db.ExecWithRetry(ctx, r.retries,
+		`UPDATE stocktakes SET status = $1 WHERE id = $2 AND status = ANY($3)`, status, id, pq.Array(allowed))
+	if err != nil {
+		return fmt.Errorf("%w: status update failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: status update failed: %v", customErr.ErrDatabase, err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `SELECT EXISTS (SELECT 1 FROM stocktakes WHERE id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&exists); err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if !exists {
+		return customErr.ErrStocktakeNotFound
+	}
+	return customErr.ErrStocktakeClosed
+}
+
+// PostStocktake books every counted variance as an adjustment in a single
+// transaction. The session id is exposed to the items history trigger, so
+// each resulting items_history row carries it. Uncounted lines are left as
+// they are. It returns the ids of the adjusted items.
+func (r *StocktakesPostgresRepository) PostStocktake(ctx context.Context, id int64, username string) ([]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		SELECT set_config('warehouse_control.changed_by', $1, true),
+		       set_config('warehouse_control.stocktake_id', $2, true)`, username, strconv.FormatInt(id, 10))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to set audit user: %v", customErr.ErrDatabase, err)
+	}
+
+	var status domain.StocktakeStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM stocktakes WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrStocktakeNotFound
+		}
+		return nil, fmt.Errorf("%w: failed to lock stocktake: %v", customErr.ErrDatabase, err)
+	}
+	if !status.IsOpen() {
+		return nil, customErr.ErrStocktakeClosed
+	}
+
+	// Lock the items in id order before reading system quantities, so the
+	// variances cannot change underneath us and concurrent posts cannot
+	// deadlock.
+	if _, err := tx.ExecContext(ctx, `
+		SELECT id FROM items
+		WHERE id IN (SELECT item_id FROM stocktake_lines WHERE stocktake_id = $1 AND counted_quantity IS NOT NULL)
+		ORDER BY id FOR UPDATE`, id); err != nil {
+		return nil, fmt.Errorf("%w: failed to lock items: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, linesQuery+` AND l.counted_quantity IS NOT NULL ORDER BY l.item_id, l.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: select stocktake lines error: %v", customErr.ErrDatabase, err)
+	}
+	lines, err := scanLines(rows)
+	_ = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var adjusted []int64
+	for _, line := range lines {
+		variance, _ := line.Variance()
+		if variance == 0 {
+			continue
+		}
+		if err := r.applyVariance(ctx, tx, id, line, variance, username); err != nil {
+			return nil, err
+		}
+		if len(adjusted) == 0 || adjusted[len(adjusted)-1] != line.ItemID {
+			adjusted = append(adjusted, line.ItemID)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE stocktakes SET status = 'posted', posted_by = $1, posted_at = NOW() WHERE id = $2`, username, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to close stocktake: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return adjusted, nil
+}
+
+// applyVariance books a variance as an adjustment, with the checks of any
+// other movement: a shortfall cannot eat into reserved stock, stock held in
+// another status or stock held in other bins or lots. A bin line adjusts its
+// bin to the counted quantity, an item line the stock not held in any bin.
+func (r *StocktakesPostgresRepository) applyVariance(ctx context.Context, tx *sql.Tx, id int64, line *domain.StocktakeLine, variance int, username string) error {
+	m := &domain.StockMovement{
+		ItemID:     line.ItemID,
+		Type:       domain.MovementAdjustment,
+		Quantity:   variance,
+		ReasonCode: "stocktake",
+		Reference:  fmt.Sprintf("stocktake:%d", id),
+	}
+	if variance > 0 {
+		m.ToLocationID = line.LocationID
+	} else {
+		m.FromLocationID = line.LocationID
+	}
+	if err := r.movements.ApplyMovement(ctx, tx, m, username); err != nil {
+		if errors.Is(err, customErr.ErrInsufficientStock) {
+			return fmt.Errorf("%w: shortfall of %s cannot be posted, stock is reserved or held in bins, lots or another status",
+				customErr.ErrInsufficientStock, line.ItemSKU)
+		}
+		return err
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStocktake(s scanner) (*domain.Stocktake, error) {
+	st := &domain.Stocktake{}
+	var postedAt sql.NullTime
+	err := s.Scan(&st.ID, &st.Status, &st.Blind, pq.Array(&st.LocationIDs), pq.Array(&st.Categories),
+		&st.CreatedBy, &st.CreatedAt, &st.PostedBy, &postedAt)
+	if err != nil {
+		return nil, err
+	}
+	if postedAt.Valid {
+		st.PostedAt = &postedAt.Time
+	}
+	return st, nil
+}
+
+func scanLines(rows *sql.Rows) ([]*domain.StocktakeLine, error) {
+	lines := make([]*domain.StocktakeLine, 0)
+	for rows.Next() {
+		l := &domain.StocktakeLine{}
+		var locationID, counted sql.NullInt64
+		var countedAt sql.NullTime
+		err := rows.Scan(&l.ID, &l.StocktakeID, &l.ItemID, &l.ItemName, &l.ItemSKU, &locationID, &l.LocationPath,
+			&l.SystemQuantity, &counted, &l.CountedBy, &countedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scan stocktake line error: %v", customErr.ErrDatabase, err)
+		}
+		if locationID.Valid {
+			l.LocationID = &locationID.Int64
+		}
+		if counted.Valid {
+			q := int(counted.Int64)
+			l.CountedQuantity = &q
+		}
+		if countedAt.Valid {
+			l.CountedAt = &countedAt.Time
+		}
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: stocktake lines rows error: %v", customErr.ErrDatabase, err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,127 @@
+package suppliers_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const uniqueViolation = "23505"
+
+type SuppliersPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *SuppliersPostgresRepository {
+	return &SuppliersPostgresRepository{db: db, retries: retries}
+}
+
+func (r *SuppliersPostgresRepository) CreateSupplier(ctx context.Context, s *domain.Supplier) (int64, error) {
+	query := `INSERT INTO suppliers (code, name, email, phone) VALUES ($1, $2, $3, $4) RETURNING id`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, s.Code, s.Name, s.Email, s.Phone)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to insert supplier: %v", customErr.ErrDatabase, err)
+	}
+	var id int64
+	if err := row.Scan(&id); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, customErr.ErrSupplierExists
+		}
+		return 0, fmt.Errorf("%w: failed to insert supplier: %v", customErr.ErrDatabase, err)
+	}
+	return id, nil
+}
+
+func (r *SuppliersPostgresRepository) GetSuppliers(ctx context.Context, filter domain.SupplierFilter) ([]*domain.Supplier, int, error) {
+	whereClause := ""
+	var args []interface{}
+	argIndex := 1
+	if filter.Search != "" {
+		whereClause = "WHERE name ILIKE $1 OR code ILIKE $1"
+		args = append(args, "%"+filter.Search+"%")
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM suppliers %s", whereClause)
+	var total int
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count suppliers error: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: scan total suppliers error: %v", customErr.ErrDatabase, err)
+	}
+
+	if total == 0 {
+		return []*domain.Supplier{}, 0, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, code, name, email, phone, created_at, updated_at
+		FROM suppliers %s
+		ORDER BY name, id
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	finalArgs := append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, finalArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: query suppliers error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	suppliers := make([]*domain.Supplier, 0, filter.Limit)
+	for rows.Next() {
+		s := &domain.Supplier{}
+		if err := rows.Scan(&s.ID, &s.Code, &s.Name, &s.Email, &s.Phone, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("%w: scan supplier error: %v", customErr.ErrDatabase, err)
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	return suppliers, total, nil
+}
+
+func (r *SuppliersPostgresRepository) GetSupplierByID(ctx context.Context, id int64) (*domain.Supplier, error) {
+	query := `SELECT id, code, name, email, phone, created_at, updated_at FROM suppliers WHERE id = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	s := &domain.Supplier{}
+	if err := row.Scan(&s.ID, &s.Code, &s.Name, &s.Email, &s.Phone, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return s, nil
+}
+
+func (r *SuppliersPostgresRepository) UpdateSupplier(ctx context.Context, id int64, s *domain.Supplier) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries,
+		`UPDATE suppliers SET name=$1, email=$2, phone=$3, updated_at=NOW() WHERE id=$4`, s.Name, s.Email, s.Phone, id)
+	if err != nil {
+		return fmt.Errorf("%w: update failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return customErr.ErrSupplierNotFound
+	}
+	return nil
+}
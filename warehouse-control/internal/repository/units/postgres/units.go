@@ -0,0 +1,209 @@
+package units_postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const uniqueViolation = "23505"
+
+// itemUnitQuery resolves a unit of an item identified by $1 in the WHERE
+// clause appended to it: the base unit has a factor of one, other units
+// need a conversion.
+const itemUnitQuery = `
+	SELECT i.id, u.code, CASE WHEN u.code = i.base_unit THEN 1 ELSE c.factor END, u.discrete, i.base_unit
+	FROM items i
+	LEFT JOIN units u ON u.code = $2
+	LEFT JOIN item_units c ON c.item_id = i.id AND c.unit_code = u.code`
+
+type UnitsPostgresRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy) *UnitsPostgresRepository {
+	return &UnitsPostgresRepository{db: db, retries: retries}
+}
+
+func (r *UnitsPostgresRepository) CreateUnit(ctx context.Context, u *domain.Unit) error {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries,
+		`INSERT INTO units (code, name, discrete) VALUES ($1, $2, $3) RETURNING created_at`, u.Code, u.Name, u.Discrete)
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert unit: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&u.CreatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return customErr.ErrUnitExists
+		}
+		return fmt.Errorf("%w: failed to insert unit: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+func (r *UnitsPostgresRepository) GetUnits(ctx context.Context) ([]*domain.Unit, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `SELECT code, name, discrete, created_at FROM units ORDER BY code`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query units error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	units := make([]*domain.Unit, 0)
+	for rows.Next() {
+		u := &domain.Unit{}
+		if err := rows.Scan(&u.Code, &u.Name, &u.Discrete, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%w: scan unit error: %v", customErr.ErrDatabase, err)
+		}
+		units = append(units, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return units, nil
+}
+
+func (r *UnitsPostgresRepository) GetItemUnits(ctx context.Context, itemID int64) (*domain.ItemUnits, error) {
+	units := &domain.ItemUnits{ItemID: itemID, Conversions: []*domain.ItemUnit{}}
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, `SELECT base_unit FROM items WHERE id = $1`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if err := row.Scan(&units.BaseUnit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, `
+		SELECT c.unit_code, c.factor, u.discrete
+		FROM item_units c
+		JOIN units u ON u.code = c.unit_code
+		WHERE c.item_id = $1
+		ORDER BY c.factor, c.unit_code`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query item units error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		c := &domain.ItemUnit{ItemID: itemID, BaseUnit: units.BaseUnit}
+		if err := rows.Scan(&c.Unit, &c.Factor, &c.Discrete); err != nil {
+			return nil, fmt.Errorf("%w: scan item unit error: %v", customErr.ErrDatabase, err)
+		}
+		units.Conversions = append(units.Conversions, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return units, nil
+}
+
+// GetItemUnit resolves a unit of the item for conversion into its base unit.
+func (r *UnitsPostgresRepository) GetItemUnit(ctx context.Context, itemID int64, unit string) (*domain.ItemUnit, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, itemUnitQuery+` WHERE i.id = $1`, itemID, unit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return scanItemUnit(row, unit)
+}
+
+// GetItemUnitBySKU resolves a unit of the item with the given SKU.
+func (r *UnitsPostgresRepository) GetItemUnitBySKU(ctx context.Context, sku, unit string) (*domain.ItemUnit, error) {
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, itemUnitQuery+` WHERE i.sku = $1`, sku, unit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	return scanItemUnit(row, unit)
+}
+
+// SetItemUnit inserts the conversion of a unit for an item or replaces its
+// factor.
+func (r *UnitsPostgresRepository) SetItemUnit(ctx context.Context, c *domain.ItemUnit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRowContext(ctx, `SELECT base_unit FROM items WHERE id = $1 FOR UPDATE`, c.ItemID).Scan(&c.BaseUnit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrItemNotFound
+		}
+		return fmt.Errorf("%w: failed to lock item: %v", customErr.ErrDatabase, err)
+	}
+	if c.Unit == c.BaseUnit {
+		return fmt.Errorf("%w: %s is the base unit of the item", customErr.ErrInvalidInput, c.Unit)
+	}
+	err = tx.QueryRowContext(ctx, `SELECT discrete FROM units WHERE code = $1`, c.Unit).Scan(&c.Discrete)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customErr.ErrUnitNotFound
+		}
+		return fmt.Errorf("%w: failed to get unit: %v", customErr.ErrDatabase, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO item_units (item_id, unit_code, factor) VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, unit_code) DO UPDATE SET factor = EXCLUDED.factor`, c.ItemID, c.Unit, c.Factor)
+	if err != nil {
+		return fmt.Errorf("%w: failed to set item unit: %v", customErr.ErrDatabase, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *UnitsPostgresRepository) DeleteItemUnit(ctx context.Context, itemID int64, unit string) error {
+	res, err := r.db.ExecWithRetry(ctx, r.retries, `DELETE FROM item_units WHERE item_id = $1 AND unit_code = $2`, itemID, unit)
+	if err != nil {
+		return fmt.Errorf("%w: delete failed: %v", customErr.ErrDatabase, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return customErr.ErrUnitNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanItemUnit(s scanner, unit string) (*domain.ItemUnit, error) {
+	c := &domain.ItemUnit{Unit: unit}
+	var code sql.NullString
+	var factor *decimal.Decimal
+	var discrete sql.NullBool
+	err := s.Scan(&c.ItemID, &code, &factor, &discrete, &c.BaseUnit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customErr.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrDatabase, err)
+	}
+	if !code.Valid {
+		return nil, fmt.Errorf("%w: unknown unit %q", customErr.ErrInvalidInput, unit)
+	}
+	if factor == nil {
+		return nil, fmt.Errorf("%w: no conversion from %s to %s is configured for the item", customErr.ErrInvalidInput, unit, c.BaseUnit)
+	}
+	c.Factor, c.Discrete = *factor, discrete.Bool
+	return c, nil
+}
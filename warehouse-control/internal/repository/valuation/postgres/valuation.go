@@ -0,0 +1,255 @@
+package valuation_postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// valuationEntries splits every movement up to $1 into the value it added to
+// its destination and took from its source; a transfer does both, so it only
+// shifts value between bins.
+const valuationEntries = `
+	WITH entries AS (
+		SELECT item_id, to_location_id AS location_id, ABS(quantity) AS quantity, total_cost AS value
+		FROM stock_movements
+		WHERE created_at <= $1
+		  AND (movement_type IN ('receipt', 'transfer') OR (movement_type = 'adjustment' AND quantity > 0))
+		UNION ALL
+		SELECT item_id, from_location_id, -ABS(quantity), -total_cost
+		FROM stock_movements
+		WHERE created_at <= $1
+		  AND (movement_type IN ('issue', 'transfer') OR (movement_type = 'adjustment' AND quantity < 0))
+	)`
+
+type ValuationPostgresRepository struct {
+	db            *dbpg.DB
+	retries       retry.Strategy
+	defaultMethod domain.CostMethod
+}
+
+func NewPostgresRepository(db *dbpg.DB, retries retry.Strategy, defaultMethod domain.CostMethod) *ValuationPostgresRepository {
+	return &ValuationPostgresRepository{db: db, retries: retries, defaultMethod: defaultMethod}
+}
+
+// ApplyCost books the cost of a movement that has just been inserted within
+// tx, while the caller holds the item lock. Incoming stock opens a cost layer
+// and outgoing stock consumes layers oldest first. Items costed at moving
+// average keep a single open layer, re-averaged on every receipt, so the same
+// consumption yields their average cost. The movement row records the unit
+// and total cost it was booked at.
+func (r *ValuationPostgresRepository) ApplyCost(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error {
+	var override sql.NullString
+	err := tx.QueryRowContext(ctx, `SELECT cost_method FROM items WHERE id = $1`, m.ItemID).Scan(&override)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read cost method: %v", customErr.ErrDatabase, err)
+	}
+	method := r.defaultMethod
+	if override.Valid {
+		method = domain.CostMethod(override.String)
+	}
+
+	onHand, value, current, err := r.currentCost(ctx, tx, m.ItemID)
+	if err != nil {
+		return err
+	}
+
+	delta := m.Delta()
+	switch {
+	case delta > 0:
+		unitCost := current
+		if m.UnitCost != nil {
+			unitCost = *m.UnitCost
+		}
+		m.UnitCost = &unitCost
+		m.TotalCost = unitCost.MulInt(int64(delta))
+		if method == domain.CostAverage && onHand > 0 {
+			_, err := tx.ExecContext(ctx, `UPDATE cost_layers SET remaining = 0 WHERE item_id = $1 AND remaining > 0`, m.ItemID)
+			if err != nil {
+				return fmt.Errorf("%w: failed to close cost layers: %v", customErr.ErrDatabase, err)
+			}
+			average := value.Add(m.TotalCost).Div(decimal.FromInt(int64(onHand+delta)), domain.CostPlaces)
+			err = r.insertLayer(ctx, tx, m, onHand+delta, average)
+			if err != nil {
+				return err
+			}
+		} else if err := r.insertLayer(ctx, tx, m, delta, unitCost); err != nil {
+			return err
+		}
+	case delta < 0:
+		m.TotalCost, err = r.consumeLayers(ctx, tx, m.ItemID, -delta, current)
+		if err != nil {
+			return err
+		}
+		unitCost := m.TotalCost.Div(decimal.FromInt(int64(-delta)), domain.CostPlaces)
+		m.UnitCost = &unitCost
+	default:
+		// Transfers move stock at the current average cost.
+		m.UnitCost = &current
+		m.TotalCost = current.MulInt(int64(m.Quantity))
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE stock_movements SET unit_cost = $1, total_cost = $2 WHERE id = $3`,
+		*m.UnitCost, m.TotalCost, m.ID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to book movement cost: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+// currentCost returns the quantity and value of the open cost layers of an
+// item with their average unit cost. Without stock the unit cost is that of
+// the latest layer, so receipts without a cost keep the last known one.
+func (r *ValuationPostgresRepository) currentCost(ctx context.Context, tx *sql.Tx, itemID int64) (int, decimal.Decimal, decimal.Decimal, error) {
+	var onHand int
+	var value, last decimal.Decimal
+	err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(remaining), 0), COALESCE(SUM(remaining * unit_cost), 0),
+		       COALESCE((SELECT unit_cost FROM cost_layers WHERE item_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1), 0)
+		FROM cost_layers WHERE item_id = $1 AND remaining > 0`, itemID,
+	).Scan(&onHand, &value, &last)
+	if err != nil {
+		return 0, decimal.Zero, decimal.Zero, fmt.Errorf("%w: failed to read cost layers: %v", customErr.ErrDatabase, err)
+	}
+	if onHand == 0 {
+		return 0, value, last, nil
+	}
+	return onHand, value, value.Div(decimal.FromInt(int64(onHand)), domain.CostPlaces), nil
+}
+
+func (r *ValuationPostgresRepository) insertLayer(ctx context.Context, tx *sql.Tx, m *domain.StockMovement, quantity int, unitCost decimal.Decimal) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO cost_layers (item_id, movement_id, quantity, remaining, unit_cost) VALUES ($1, $2, $3, $3, $4)`,
+		m.ItemID, m.ID, quantity, unitCost)
+	if err != nil {
+		return fmt.Errorf("%w: failed to insert cost layer: %v", customErr.ErrDatabase, err)
+	}
+	return nil
+}
+
+// consumeLayers takes quantity out of the open layers of an item, oldest
+// first, and returns its cost. Stock beyond the layers is costed at
+// fallback.
+func (r *ValuationPostgresRepository) consumeLayers(ctx context.Context, tx *sql.Tx, itemID int64, quantity int, fallback decimal.Decimal) (decimal.Decimal, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, remaining, unit_cost FROM cost_layers
+		WHERE item_id = $1 AND remaining > 0
+		ORDER BY created_at, id
+		FOR UPDATE`, itemID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: failed to lock cost layers: %v", customErr.ErrDatabase, err)
+	}
+	var layers []*domain.CostLayer
+	for rows.Next() {
+		l := &domain.CostLayer{ItemID: itemID}
+		if err := rows.Scan(&l.ID, &l.Remaining, &l.UnitCost); err != nil {
+			_ = rows.Close()
+			return decimal.Zero, fmt.Errorf("%w: scan cost layer error: %v", customErr.ErrDatabase, err)
+		}
+		layers = append(layers, l)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return decimal.Zero, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	total := decimal.Zero
+	for _, l := range layers {
+		if quantity == 0 {
+			break
+		}
+		take := min(quantity, l.Remaining)
+		_, err := tx.ExecContext(ctx, `UPDATE cost_layers SET remaining = remaining - $1 WHERE id = $2`, take, l.ID)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("%w: failed to consume cost layer: %v", customErr.ErrDatabase, err)
+		}
+		total = total.Add(l.UnitCost.MulInt(int64(take)))
+		quantity -= take
+	}
+	return total.Add(fallback.MulInt(int64(quantity))), nil
+}
+
+// GetValuation values the inventory as of the given time by category and by
+// bin, from the costs booked on the movements up to then. An item counts
+// towards the category it had at that time: the one in its latest history
+// snapshot up to then, or, without one, in the snapshot its next change
+// replaced. Items with no history either way count under their current
+// category.
+func (r *ValuationPostgresRepository) GetValuation(ctx context.Context, asOf time.Time) (*domain.Valuation, error) {
+	v := &domain.Valuation{AsOf: asOf, ByCategory: []*domain.CategoryValuation{}, ByLocation: []*domain.LocationValuation{}}
+
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, valuationEntries+`
+		SELECT COALESCE(CASE
+		           WHEN past.data IS NOT NULL THEN past.data->>'category'
+		           WHEN later.data IS NOT NULL THEN later.data->>'category'
+		           ELSE i.category
+		       END, ''), SUM(e.quantity), SUM(e.value)
+		FROM (SELECT item_id, SUM(quantity) AS quantity, SUM(value) AS value FROM entries GROUP BY item_id) e
+		JOIN items i ON i.id = e.item_id
+		LEFT JOIN LATERAL (
+			SELECT h.new_data AS data FROM items_history h
+			WHERE h.item_id = e.item_id AND h.changed_at <= $1 AND h.new_data IS NOT NULL
+			ORDER BY h.changed_at DESC, h.id DESC LIMIT 1
+		) past ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT h.old_data AS data FROM items_history h
+			WHERE h.item_id = e.item_id AND h.changed_at > $1 AND h.old_data IS NOT NULL
+			ORDER BY h.changed_at, h.id LIMIT 1
+		) later ON TRUE
+		GROUP BY 1
+		HAVING SUM(e.quantity) <> 0 OR SUM(e.value) <> 0
+		ORDER BY 1`, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query valuation by category error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		c := &domain.CategoryValuation{}
+		if err := rows.Scan(&c.Category, &c.Quantity, &c.Value); err != nil {
+			return nil, fmt.Errorf("%w: scan valuation error: %v", customErr.ErrDatabase, err)
+		}
+		v.Quantity += c.Quantity
+		v.Value = v.Value.Add(c.Value)
+		v.ByCategory = append(v.ByCategory, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+
+	locationRows, err := r.db.QueryWithRetry(ctx, r.retries, valuationEntries+`
+		SELECT e.location_id, COALESCE(l.path, ''), SUM(e.quantity), SUM(e.value)
+		FROM entries e
+		LEFT JOIN locations l ON l.id = e.location_id
+		GROUP BY e.location_id, l.path
+		HAVING SUM(e.quantity) <> 0 OR SUM(e.value) <> 0
+		ORDER BY l.path NULLS LAST`, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query valuation by location error: %v", customErr.ErrDatabase, err)
+	}
+	defer func() { _ = locationRows.Close() }()
+
+	for locationRows.Next() {
+		l := &domain.LocationValuation{}
+		var locationID sql.NullInt64
+		if err := locationRows.Scan(&locationID, &l.LocationPath, &l.Quantity, &l.Value); err != nil {
+			return nil, fmt.Errorf("%w: scan valuation error: %v", customErr.ErrDatabase, err)
+		}
+		if locationID.Valid {
+			l.LocationID = &locationID.Int64
+		}
+		v.ByLocation = append(v.ByLocation, l)
+	}
+	if err := locationRows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: rows iteration error: %v", customErr.ErrDatabase, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,117 @@
+package alerts_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+// pendingBuffer bounds the queue of items waiting for evaluation. When it is
+// full further notifications are dropped; the periodic full evaluation picks
+// those items up.
+const pendingBuffer = 1024
+
+type AlertsUsecase struct {
+	repo    alertsRepository
+	pending chan int64
+	logger  *zlog.Zerolog
+}
+
+func NewService(repo alertsRepository, logger *zlog.Zerolog) *AlertsUsecase {
+	return &AlertsUsecase{
+		repo:    repo,
+		pending: make(chan int64, pendingBuffer),
+		logger:  logger,
+	}
+}
+
+// NotifyStockChanged queues the item for evaluation by the running
+// evaluator. It never blocks the caller.
+func (s *AlertsUsecase) NotifyStockChanged(itemID int64) {
+	select {
+	case s.pending <- itemID:
+	default:
+		s.logger.Warn().Int64("item_id", itemID).Msg("Alert evaluation queue is full, deferring to the next sweep")
+	}
+}
+
+// RunEvaluator evaluates queued items as they arrive and every item once per
+// interval until ctx is done.
+func (s *AlertsUsecase) RunEvaluator(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.evaluate(ctx, nil)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case itemID := <-s.pending:
+			s.evaluate(ctx, &itemID)
+		case <-ticker.C:
+			s.evaluate(ctx, nil)
+		}
+	}
+}
+
+func (s *AlertsUsecase) evaluate(ctx context.Context, itemID *int64) {
+	raised, resolved, err := s.repo.EvaluateAlerts(ctx, itemID)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error().Err(err).Msg("Failed to evaluate low-stock alerts")
+		}
+		return
+	}
+	if raised > 0 || resolved > 0 {
+		s.logger.Info().Int64("raised", raised).Int64("resolved", resolved).Msg("Low-stock alerts evaluated")
+	}
+}
+
+func (s *AlertsUsecase) GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, int, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown alert status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting alerts")
+	alerts, total, err := s.repo.GetAlerts(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get alerts")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(alerts)).Msg("Alerts retrieved")
+	return alerts, total, nil
+}
+
+func (s *AlertsUsecase) AcknowledgeAlert(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Acknowledging alert")
+	if err := s.repo.AcknowledgeAlert(ctx, id, username); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to acknowledge alert")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Alert acknowledged")
+	return nil
+}
+
+func (s *AlertsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrAlertNotFound):
+		return customErr.ErrAlertNotFound
+	case errors.Is(err, customErr.ErrAlertNotOpen):
+		return customErr.ErrAlertNotOpen
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
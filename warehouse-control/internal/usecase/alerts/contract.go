@@ -0,0 +1,13 @@
+package alerts_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type alertsRepository interface {
+	EvaluateAlerts(ctx context.Context, itemID *int64) (raised, resolved int64, err error)
+	GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, int, error)
+	AcknowledgeAlert(ctx context.Context, id int64, username string) error
+}
@@ -0,0 +1,212 @@
+package attachments_usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type AttachmentsUsecase struct {
+	repo          attachmentsRepository
+	storage       fileStorage
+	maxSize       int64
+	thumbnailSize int
+	logger        *zlog.Zerolog
+}
+
+// NewService accepts files of up to maxSize bytes and makes thumbnails of
+// images that fit a thumbnailSize×thumbnailSize box.
+func NewService(repo attachmentsRepository, storage fileStorage, maxSize int64, thumbnailSize int, logger *zlog.Zerolog) *AttachmentsUsecase {
+	return &AttachmentsUsecase{
+		repo:          repo,
+		storage:       storage,
+		maxSize:       maxSize,
+		thumbnailSize: thumbnailSize,
+		logger:        logger,
+	}
+}
+
+// UploadAttachment stores the file and attaches it to the item. The content
+// type is sniffed from the content rather than trusted from the client.
+func (s *AttachmentsUsecase) UploadAttachment(ctx context.Context, itemID int64, fileName string, r io.Reader, username string) (*domain.Attachment, error) {
+	if itemID <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read upload: %v", customErr.ErrInvalidInput, err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", customErr.ErrAttachmentTooLarge, s.maxSize)
+	}
+	contentType := http.DetectContentType(data)
+	if !domain.IsAllowedAttachmentType(contentType) {
+		return nil, fmt.Errorf("%w: %s", customErr.ErrUnsupportedMediaType, domain.MediaType(contentType))
+	}
+
+	key, err := newStorageKey(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	a := &domain.Attachment{
+		ItemID:      itemID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  key,
+		UploadedBy:  username,
+	}
+
+	s.logger.Info().Int64("item_id", itemID).Str("file", a.FileName).Str("type", contentType).Str("user", username).Msg("Uploading attachment")
+	if err := s.storage.Save(ctx, a.StorageKey, bytes.NewReader(data)); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to store attachment")
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	// A thumbnail is a convenience: an image that cannot be scaled is kept
+	// without one.
+	if strings.HasPrefix(contentType, "image/") {
+		thumb, ok, err := makeThumbnail(data, s.thumbnailSize)
+		switch {
+		case err != nil:
+			s.logger.Warn().Err(err).Int64("item_id", itemID).Str("file", a.FileName).Msg("Failed to make thumbnail")
+		case ok:
+			thumbKey := a.StorageKey + "_thumb.jpg"
+			if err := s.storage.Save(ctx, thumbKey, bytes.NewReader(thumb)); err != nil {
+				s.logger.Warn().Err(err).Int64("item_id", itemID).Msg("Failed to store thumbnail")
+			} else {
+				a.ThumbnailKey = thumbKey
+			}
+		}
+	}
+
+	if err := s.repo.CreateAttachment(ctx, a); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to create attachment")
+		s.removeFiles(ctx, a)
+		return nil, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", a.ID).Int64("item_id", itemID).Msg("Attachment uploaded")
+	return a, nil
+}
+
+func (s *AttachmentsUsecase) GetAttachments(ctx context.Context, itemID int64) ([]*domain.Attachment, error) {
+	if itemID <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	attachments, err := s.repo.GetAttachments(ctx, itemID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to get attachments")
+		return nil, s.mapError(err)
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns the attachment with its content, or with the
+// content of its thumbnail. The caller closes the reader.
+func (s *AttachmentsUsecase) OpenAttachment(ctx context.Context, itemID, id int64, thumbnail bool) (*domain.Attachment, io.ReadCloser, error) {
+	if itemID <= 0 || id <= 0 {
+		return nil, nil, customErr.ErrInvalidInput
+	}
+	a, err := s.repo.GetAttachment(ctx, itemID, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Int64("id", id).Msg("Failed to get attachment")
+		return nil, nil, s.mapError(err)
+	}
+	key := a.StorageKey
+	if thumbnail {
+		if a.ThumbnailKey == "" {
+			return nil, nil, fmt.Errorf("%w: the attachment has no thumbnail", customErr.ErrAttachmentNotFound)
+		}
+		key = a.ThumbnailKey
+	}
+	rc, err := s.storage.Open(ctx, key)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Str("key", key).Msg("Failed to open attachment")
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%w: the file is missing from storage", customErr.ErrAttachmentNotFound)
+		}
+		return nil, nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	return a, rc, nil
+}
+
+// DeleteAttachment detaches the file from the item and removes it from
+// storage.
+func (s *AttachmentsUsecase) DeleteAttachment(ctx context.Context, itemID, id int64, username string) error {
+	if itemID <= 0 || id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("item_id", itemID).Int64("id", id).Str("user", username).Msg("Deleting attachment")
+	a, err := s.repo.DeleteAttachment(ctx, itemID, id, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Int64("id", id).Msg("Failed to delete attachment")
+		return s.mapError(err)
+	}
+	s.removeFiles(ctx, a)
+	return nil
+}
+
+// RemoveFiles removes the stored files of attachments deleted along with
+// their item. Files that cannot be removed are logged and left behind.
+func (s *AttachmentsUsecase) RemoveFiles(ctx context.Context, attachments []*domain.Attachment) {
+	for _, a := range attachments {
+		s.removeFiles(ctx, a)
+	}
+}
+
+// removeFiles deletes the stored content of an attachment. Failures are
+// only logged: the record is what makes a file part of an item.
+func (s *AttachmentsUsecase) removeFiles(ctx context.Context, a *domain.Attachment) {
+	for _, key := range []string{a.StorageKey, a.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Warn().Err(err).Str("key", key).Msg("Failed to remove stored file")
+		}
+	}
+}
+
+// newStorageKey returns a fresh key under the item's prefix. Keys are random
+// so that file names chosen by clients never reach the storage.
+func newStorageKey(itemID int64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate storage key: %w", err)
+	}
+	return fmt.Sprintf("items/%d/%s", itemID, hex.EncodeToString(b)), nil
+}
+
+// cleanFileName keeps the base name of an uploaded file as the client sent
+// it, for display and downloads.
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+func (s *AttachmentsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrAttachmentNotFound):
+		return customErr.ErrAttachmentNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,22 @@
+package attachments_usecase
+
+import (
+	"context"
+	"io"
+
+	"warehouse-control/internal/domain"
+)
+
+type attachmentsRepository interface {
+	CreateAttachment(ctx context.Context, a *domain.Attachment) error
+	GetAttachments(ctx context.Context, itemID int64) ([]*domain.Attachment, error)
+	GetAttachment(ctx context.Context, itemID, id int64) (*domain.Attachment, error)
+	DeleteAttachment(ctx context.Context, itemID, id int64, username string) (*domain.Attachment, error)
+}
+
+// fileStorage keeps the content of attachments under opaque keys.
+type fileStorage interface {
+	Save(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,101 @@
+package attachments_usecase
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	thumbnailQuality = 80
+	// maxThumbnailPixels bounds the images decoded for a thumbnail. A small
+	// compressed file can declare a huge image, and decoding it would take
+	// gigabytes.
+	maxThumbnailPixels = 40_000_000
+	// maxSamples bounds the source pixels averaged per thumbnail pixel along
+	// each axis.
+	maxSamples = 4
+)
+
+// makeThumbnail scales an image down to fit a size×size box, keeping its
+// aspect ratio, and encodes it as JPEG over a white background. It reports
+// false for images of a format that cannot be decoded and for images larger
+// than maxThumbnailPixels.
+func makeThumbnail(data []byte, size int) ([]byte, bool, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if err == image.ErrFormat {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("decode image config: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxThumbnailPixels {
+		return nil, false, nil
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		if err == image.ErrFormat {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("decode image: %w", err)
+	}
+	thumb := scaleDown(src, size)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, false, fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// scaleDown fits the image into a size×size box by averaging the source
+// pixels each thumbnail pixel covers, at most maxSamples×maxSamples of them
+// spread evenly over the area, so the work depends on the thumbnail size
+// only. Images that already fit keep their size. Transparent areas are
+// flattened onto white.
+func scaleDown(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dw, dh := sw, sh
+	if sw > size || sh > size {
+		if sw >= sh {
+			dw, dh = size, max(1, sh*size/sw)
+		} else {
+			dw, dh = max(1, sw*size/sh), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		ystep := max(1, (y1-y0)/maxSamples)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+			xstep := max(1, (x1-x0)/maxSamples)
+			var sum [4]uint32
+			var n uint32
+			for sy := y0; sy < y1; sy += ystep {
+				for sx := x0; sx < x1; sx += xstep {
+					r, g, bl, a := src.At(b.Min.X+sx, b.Min.Y+sy).RGBA()
+					sum[0] += r
+					sum[1] += g
+					sum[2] += bl
+					sum[3] += a
+					n++
+				}
+			}
+			// Colours are premultiplied, so adding the missing alpha blends
+			// them over white.
+			white := 0xffff - sum[3]/n
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8((sum[0]/n + white) >> 8)
+			dst.Pix[i+1] = uint8((sum[1]/n + white) >> 8)
+			dst.Pix[i+2] = uint8((sum[2]/n + white) >> 8)
+			dst.Pix[i+3] = 255
+		}
+	}
+	return dst
+}
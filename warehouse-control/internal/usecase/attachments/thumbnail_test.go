@@ -0,0 +1,66 @@
+package attachments_usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleDown(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.RGBA{A: 255}
+			if x < 200 {
+				c.R = 255
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	thumb := scaleDown(src, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, thumb.RGBAAt(10, 10))
+	assert.Equal(t, color.RGBA{A: 255}, thumb.RGBAAt(90, 40))
+
+	small := scaleDown(image.NewRGBA(image.Rect(0, 0, 30, 60)), 100)
+	assert.Equal(t, image.Rect(0, 0, 30, 60), small.Bounds())
+	// Fully transparent pixels end up white.
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, small.RGBAAt(0, 0))
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 120, 480))))
+
+	data, ok, err := makeThumbnail(buf.Bytes(), 64)
+	require.NoError(t, err)
+	require.True(t, ok)
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 16, cfg.Width)
+	assert.Equal(t, 64, cfg.Height)
+
+	_, ok, err = makeThumbnail([]byte("%PDF-1.7"), 64)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// A header declaring a huge image is not decoded at all.
+	buf.Reset()
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	huge := buf.Bytes()
+	binary.BigEndian.PutUint32(huge[16:], 30000) // IHDR width
+	binary.BigEndian.PutUint32(huge[20:], 30000) // IHDR height
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+	_, ok, err = makeThumbnail(huge, 64)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,155 @@
+package barcodes_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+// contentsLimit bounds the items listed for a scanned location.
+const contentsLimit = 50
+
+type BarcodesUsecase struct {
+	repo          barcodesRepository
+	itemsRepo     itemsRepository
+	locationsRepo locationsRepository
+	lotsRepo      lotsRepository
+	logger        *zlog.Zerolog
+}
+
+func NewService(repo barcodesRepository, itemsRepo itemsRepository, locationsRepo locationsRepository, lotsRepo lotsRepository, logger *zlog.Zerolog) *BarcodesUsecase {
+	return &BarcodesUsecase{
+		repo:          repo,
+		itemsRepo:     itemsRepo,
+		locationsRepo: locationsRepo,
+		lotsRepo:      lotsRepo,
+		logger:        logger,
+	}
+}
+
+func (s *BarcodesUsecase) AddBarcode(ctx context.Context, b *domain.ItemBarcode) error {
+	if b.ItemID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := b.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	}
+	if err := s.repo.CreateBarcode(ctx, b); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", b.ItemID).Str("code", b.Code).Msg("Failed to add barcode")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", b.ID).Int64("item_id", b.ItemID).Str("code", b.Code).Msg("Barcode added")
+	return nil
+}
+
+func (s *BarcodesUsecase) GetBarcodes(ctx context.Context, itemID int64) ([]*domain.ItemBarcode, error) {
+	if itemID <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	barcodes, err := s.repo.GetBarcodes(ctx, itemID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to get barcodes")
+		return nil, s.mapError(err)
+	}
+	return barcodes, nil
+}
+
+func (s *BarcodesUsecase) DeleteBarcode(ctx context.Context, itemID, id int64) error {
+	if itemID <= 0 || id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := s.repo.DeleteBarcode(ctx, itemID, id); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Int64("id", id).Msg("Failed to delete barcode")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Int64("item_id", itemID).Msg("Barcode deleted")
+	return nil
+}
+
+// Scan resolves a scanned code. An item barcode or SKU is tried first, then
+// a location path, then a lot number.
+func (s *BarcodesUsecase) Scan(ctx context.Context, code string) (*domain.ScanResult, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, customErr.ErrInvalidInput
+	}
+	result := &domain.ScanResult{Code: code}
+
+	itemID, err := s.repo.FindItemID(ctx, code, domain.GTINKey(code))
+	switch {
+	case err == nil:
+		result.Target = domain.ScanItem
+		if result.Item, err = s.itemsRepo.GetItemByID(ctx, itemID); err != nil {
+			return nil, s.scanError(code, err)
+		}
+		return result, nil
+	case !errors.Is(err, customErr.ErrItemNotFound):
+		return nil, s.scanError(code, err)
+	}
+
+	locationID, err := s.repo.FindLocationID(ctx, code)
+	switch {
+	case err == nil:
+		result.Target = domain.ScanLocation
+		if result.Location, err = s.locationsRepo.GetLocationByID(ctx, locationID); err != nil {
+			return nil, s.scanError(code, err)
+		}
+		if result.Contents, result.ContentsTotal, err = s.repo.GetLocationContents(ctx, result.Location.Path, contentsLimit); err != nil {
+			return nil, s.scanError(code, err)
+		}
+		return result, nil
+	case !errors.Is(err, customErr.ErrLocationNotFound):
+		return nil, s.scanError(code, err)
+	}
+
+	lotIDs, err := s.repo.FindLotIDs(ctx, code)
+	if err != nil {
+		return nil, s.scanError(code, err)
+	}
+	switch len(lotIDs) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", customErr.ErrCodeNotRecognized, code)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: %d items have lot %q, scan the item first", customErr.ErrAmbiguousCode, len(lotIDs), code)
+	}
+	result.Target = domain.ScanLot
+	if result.Lot, err = s.lotsRepo.GetLotByID(ctx, lotIDs[0]); err != nil {
+		return nil, s.scanError(code, err)
+	}
+	if result.Item, err = s.itemsRepo.GetItemByID(ctx, result.Lot.ItemID); err != nil {
+		return nil, s.scanError(code, err)
+	}
+	return result, nil
+}
+
+func (s *BarcodesUsecase) scanError(code string, err error) error {
+	s.logger.Error().Err(err).Str("code", code).Msg("Failed to resolve scanned code")
+	return s.mapError(err)
+}
+
+func (s *BarcodesUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrSupplierNotFound):
+		return customErr.ErrSupplierNotFound
+	case errors.Is(err, customErr.ErrBarcodeNotFound):
+		return customErr.ErrBarcodeNotFound
+	case errors.Is(err, customErr.ErrBarcodeExists):
+		return customErr.ErrBarcodeExists
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrLotNotFound):
+		return customErr.ErrLotNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,29 @@
+package barcodes_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type barcodesRepository interface {
+	CreateBarcode(ctx context.Context, b *domain.ItemBarcode) error
+	GetBarcodes(ctx context.Context, itemID int64) ([]*domain.ItemBarcode, error)
+	DeleteBarcode(ctx context.Context, itemID, id int64) error
+	FindItemID(ctx context.Context, code, gtinKey string) (int64, error)
+	FindLocationID(ctx context.Context, path string) (int64, error)
+	GetLocationContents(ctx context.Context, path string, limit int) ([]*domain.LocationContent, int, error)
+	FindLotIDs(ctx context.Context, lotNumber string) ([]int64, error)
+}
+
+type itemsRepository interface {
+	GetItemByID(ctx context.Context, id int64) (*domain.Item, error)
+}
+
+type locationsRepository interface {
+	GetLocationByID(ctx context.Context, id int64) (*domain.Location, error)
+}
+
+type lotsRepository interface {
+	GetLotByID(ctx context.Context, id int64) (*domain.Lot, error)
+}
@@ -0,0 +1,233 @@
+package categories_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type CategoriesUsecase struct {
+	repo   categoriesRepository
+	logger *zlog.Zerolog
+}
+
+func NewService(repo categoriesRepository, logger *zlog.Zerolog) *CategoriesUsecase {
+	return &CategoriesUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreateCategory adds a category under ParentID, or a root one when it is
+// nil, deriving its path from the parent's.
+func (s *CategoriesUsecase) CreateCategory(ctx context.Context, c *domain.Category) (int64, error) {
+	if err := validateName(c); err != nil {
+		return 0, err
+	}
+	c.Path = c.Name
+	if c.ParentID != nil {
+		parent, err := s.repo.GetCategoryByID(ctx, *c.ParentID)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("parent_id", *c.ParentID).Msg("Failed to get parent category")
+			if errors.Is(err, customErr.ErrCategoryNotFound) {
+				return 0, fmt.Errorf("%w: parent category not found", customErr.ErrInvalidInput)
+			}
+			return 0, s.mapError(err)
+		}
+		c.Path = parent.Path + domain.CategorySeparator + c.Name
+	}
+
+	s.logger.Info().Str("path", c.Path).Msg("Creating category")
+	id, err := s.repo.CreateCategory(ctx, c)
+	if err != nil {
+		s.logger.Error().Err(err).Str("path", c.Path).Msg("Failed to create category")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("path", c.Path).Msg("Category created")
+	return id, nil
+}
+
+// GetCategoryTree returns the root categories with their subcategories.
+func (s *CategoriesUsecase) GetCategoryTree(ctx context.Context) ([]*domain.Category, error) {
+	categories, err := s.repo.GetCategories(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get categories")
+		return nil, s.mapError(err)
+	}
+	return domain.BuildCategoryTree(categories), nil
+}
+
+// GetCategoryByID returns the category with its subcategories.
+func (s *CategoriesUsecase) GetCategoryByID(ctx context.Context, id int64) (*domain.Category, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	categories, err := s.repo.GetCategories(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get category")
+		return nil, s.mapError(err)
+	}
+	domain.BuildCategoryTree(categories)
+	for _, c := range categories {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, customErr.ErrCategoryNotFound
+}
+
+// UpdateCategory renames the category and moves it under ParentID, or to the
+// root when it is nil. Subcategories, items and products move along with it.
+func (s *CategoriesUsecase) UpdateCategory(ctx context.Context, c *domain.Category, username string) error {
+	if c.ID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := validateName(c); err != nil {
+		return err
+	}
+	if c.ParentID != nil && *c.ParentID == c.ID {
+		return fmt.Errorf("%w: a category cannot be its own parent", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Int64("id", c.ID).Str("user", username).Msg("Updating category")
+	if err := s.repo.UpdateCategory(ctx, c, username); err != nil {
+		s.logger.Error().Err(err).Int64("id", c.ID).Msg("Failed to update category")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", c.ID).Str("path", c.Path).Msg("Category updated")
+	return nil
+}
+
+// DeleteCategory removes a leaf category that no item or product uses.
+func (s *CategoriesUsecase) DeleteCategory(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Deleting category")
+	if err := s.repo.DeleteCategory(ctx, id); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to delete category")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Msg("Category deleted")
+	return nil
+}
+
+// GetAttributeSchema returns the custom attributes items of the category
+// with the given path have. Items without a category have none.
+func (s *CategoriesUsecase) GetAttributeSchema(ctx context.Context, path string) (domain.AttributeSchema, error) {
+	if path == "" {
+		return nil, nil
+	}
+	schema, err := s.repo.GetAttributeSchema(ctx, path)
+	if err != nil {
+		s.logger.Error().Err(err).Str("path", path).Msg("Failed to get attribute schema")
+		return nil, s.mapError(err)
+	}
+	return schema, nil
+}
+
+// GetCategoryAttributes returns the custom attributes of the category,
+// inherited ones included.
+func (s *CategoriesUsecase) GetCategoryAttributes(ctx context.Context, id int64) (domain.AttributeSchema, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	c, err := s.repo.GetCategoryByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get category")
+		return nil, s.mapError(err)
+	}
+	return s.GetAttributeSchema(ctx, c.Path)
+}
+
+// SetAttribute defines a custom attribute on the category, or redefines it.
+func (s *CategoriesUsecase) SetAttribute(ctx context.Context, d *domain.AttributeDefinition, username string) error {
+	if d.CategoryID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := validateAttribute(d); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("category_id", d.CategoryID).Str("attribute", d.Name).Str("user", username).Msg("Setting attribute")
+	if err := s.repo.SetAttribute(ctx, d); err != nil {
+		s.logger.Error().Err(err).Int64("category_id", d.CategoryID).Str("attribute", d.Name).Msg("Failed to set attribute")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *CategoriesUsecase) DeleteAttribute(ctx context.Context, categoryID int64, name, username string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if categoryID <= 0 || name == "" {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("category_id", categoryID).Str("attribute", name).Str("user", username).Msg("Deleting attribute")
+	if err := s.repo.DeleteAttribute(ctx, categoryID, name); err != nil {
+		s.logger.Error().Err(err).Int64("category_id", categoryID).Str("attribute", name).Msg("Failed to delete attribute")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+// validateAttribute normalizes the attribute name and requires the options
+// of an enum, and only of an enum.
+func validateAttribute(d *domain.AttributeDefinition) error {
+	d.Name = strings.ToLower(strings.TrimSpace(d.Name))
+	if d.Name == "" {
+		return fmt.Errorf("%w: attribute name is required", customErr.ErrInvalidInput)
+	}
+	if !d.Type.IsValid() {
+		return fmt.Errorf("%w: unknown attribute type %q", customErr.ErrInvalidInput, d.Type)
+	}
+	if d.Type != domain.AttributeEnum {
+		if len(d.Options) > 0 {
+			return fmt.Errorf("%w: only enum attributes have options", customErr.ErrInvalidInput)
+		}
+		d.Options = []string{}
+		return nil
+	}
+	if len(d.Options) == 0 {
+		return fmt.Errorf("%w: an enum attribute needs options", customErr.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(d.Options))
+	for i, o := range d.Options {
+		o = strings.TrimSpace(o)
+		if o == "" || seen[o] {
+			return fmt.Errorf("%w: enum options must be non-empty and distinct", customErr.ErrInvalidInput)
+		}
+		seen[o] = true
+		d.Options[i] = o
+	}
+	return nil
+}
+
+func validateName(c *domain.Category) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" || strings.Contains(c.Name, domain.CategorySeparator) {
+		return fmt.Errorf("%w: name must be non-empty and must not contain %q", customErr.ErrInvalidInput, domain.CategorySeparator)
+	}
+	return nil
+}
+
+func (s *CategoriesUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrCategoryNotFound):
+		return customErr.ErrCategoryNotFound
+	case errors.Is(err, customErr.ErrCategoryExists):
+		return customErr.ErrCategoryExists
+	case errors.Is(err, customErr.ErrCategoryInUse):
+		return customErr.ErrCategoryInUse
+	case errors.Is(err, customErr.ErrAttributeNotFound):
+		return customErr.ErrAttributeNotFound
+	case errors.Is(err, customErr.ErrInvalidInput):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
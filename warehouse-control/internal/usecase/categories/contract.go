@@ -0,0 +1,18 @@
+package categories_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type categoriesRepository interface {
+	CreateCategory(ctx context.Context, c *domain.Category) (int64, error)
+	GetCategories(ctx context.Context) ([]*domain.Category, error)
+	GetCategoryByID(ctx context.Context, id int64) (*domain.Category, error)
+	UpdateCategory(ctx context.Context, c *domain.Category, username string) error
+	DeleteCategory(ctx context.Context, id int64) error
+	GetAttributeSchema(ctx context.Context, path string) (domain.AttributeSchema, error)
+	SetAttribute(ctx context.Context, d *domain.AttributeDefinition) error
+	DeleteAttribute(ctx context.Context, categoryID int64, name string) error
+}
@@ -0,0 +1,13 @@
+package exchange_rates_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type exchangeRatesRepository interface {
+	GetRates(ctx context.Context) ([]*domain.ExchangeRate, error)
+	SetRate(ctx context.Context, rate *domain.ExchangeRate) error
+	DeleteRate(ctx context.Context, currency string) error
+}
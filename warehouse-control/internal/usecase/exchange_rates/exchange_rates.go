@@ -0,0 +1,99 @@
+package exchange_rates_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+	"warehouse-control/internal/domain/decimal"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ExchangeRatesUsecase struct {
+	repo   exchangeRatesRepository
+	base   string
+	logger *zlog.Zerolog
+}
+
+// NewService keeps rates against the given base currency.
+func NewService(repo exchangeRatesRepository, base string, logger *zlog.Zerolog) *ExchangeRatesUsecase {
+	return &ExchangeRatesUsecase{
+		repo:   repo,
+		base:   base,
+		logger: logger,
+	}
+}
+
+func (s *ExchangeRatesUsecase) BaseCurrency() string {
+	return s.base
+}
+
+func (s *ExchangeRatesUsecase) GetRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	rates, err := s.repo.GetRates(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get exchange rates")
+		return nil, s.mapError(err)
+	}
+	return rates, nil
+}
+
+// GetExchangeRates returns the current rates as a converter.
+func (s *ExchangeRatesUsecase) GetExchangeRates(ctx context.Context) (*domain.ExchangeRates, error) {
+	rates, err := s.GetRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	converter := &domain.ExchangeRates{Base: s.base, Rates: make(map[string]decimal.Decimal, len(rates))}
+	for _, rate := range rates {
+		converter.Rates[rate.Currency] = rate.Rate
+	}
+	return converter, nil
+}
+
+func (s *ExchangeRatesUsecase) SetRate(ctx context.Context, rate *domain.ExchangeRate) error {
+	rate.Currency = strings.ToUpper(strings.TrimSpace(rate.Currency))
+	if !domain.IsCurrencyCode(rate.Currency) {
+		return fmt.Errorf("%w: %q is not an ISO 4217 currency code", customErr.ErrInvalidInput, rate.Currency)
+	}
+	if rate.Currency == s.base {
+		return fmt.Errorf("%w: %s is the base currency", customErr.ErrInvalidInput, s.base)
+	}
+	rate.Rate = rate.Rate.Round(domain.RatePlaces)
+	if rate.Rate.Sign() <= 0 {
+		return fmt.Errorf("%w: exchange rate must be positive", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Str("currency", rate.Currency).Str("rate", rate.Rate.String()).Str("user", rate.UpdatedBy).Msg("Setting exchange rate")
+	if err := s.repo.SetRate(ctx, rate); err != nil {
+		s.logger.Error().Err(err).Str("currency", rate.Currency).Msg("Failed to set exchange rate")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *ExchangeRatesUsecase) DeleteRate(ctx context.Context, currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !domain.IsCurrencyCode(currency) {
+		return customErr.ErrInvalidInput
+	}
+	if err := s.repo.DeleteRate(ctx, currency); err != nil {
+		s.logger.Error().Err(err).Str("currency", currency).Msg("Failed to delete exchange rate")
+		return s.mapError(err)
+	}
+	s.logger.Info().Str("currency", currency).Msg("Exchange rate deleted")
+	return nil
+}
+
+func (s *ExchangeRatesUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrExchangeRateNotFound):
+		return customErr.ErrExchangeRateNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
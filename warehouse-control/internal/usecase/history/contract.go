@@ -9,4 +9,5 @@ import (
 type historyRepository interface {
 	GetHistory(ctx context.Context, filter domain.HistoryFilter) ([]*domain.HistoryRecord, int, error)
 	GetHistoryByItemID(ctx context.Context, itemID int64, limit, offset int) ([]*domain.HistoryRecord, error)
+	ExportHistory(ctx context.Context, filter domain.HistoryFilter, fn func(*domain.HistoryRecord) error) error
 }
@@ -41,6 +41,30 @@ func (s *HistoryUsecase) GetHistory(ctx context.Context, filter domain.HistoryFi
 	return records, nil
 }
 
+// ExportHistory calls fn for every record matching the filter; a zero limit
+// exports all of them. An error from fn stops the export and is returned as
+// is.
+func (s *HistoryUsecase) ExportHistory(ctx context.Context, filter domain.HistoryFilter, fn func(*domain.HistoryRecord) error) error {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Msg("Exporting history")
+	count := 0
+	err := s.repo.ExportHistory(ctx, filter, func(rec *domain.HistoryRecord) error {
+		count++
+		return fn(rec)
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Int("count", count).Msg("Failed to export history")
+		if errors.Is(err, customErr.ErrDatabase) {
+			return customErr.ErrDatabase
+		}
+		return err
+	}
+	s.logger.Info().Int("count", count).Msg("History exported")
+	return nil
+}
+
 func (s *HistoryUsecase) GetHistoryByItemID(ctx context.Context, itemID int64) ([]*domain.HistoryRecord, error) {
 	if itemID <= 0 {
 		return nil, customErr.ErrInvalidInput
@@ -0,0 +1,35 @@
+package imports_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type importsRepository interface {
+	CreateImportJob(ctx context.Context, j *domain.ImportJob) error
+	UpdateImportJob(ctx context.Context, j *domain.ImportJob) error
+	GetImportJob(ctx context.Context, id int64) (*domain.ImportJob, error)
+	FailUnfinishedImportJobs(ctx context.Context, message string) (int64, error)
+}
+
+// itemsService creates and updates items with the checks of the items API.
+type itemsService interface {
+	ValidateNewItem(ctx context.Context, item *domain.Item) error
+	ValidateItem(ctx context.Context, item *domain.Item) error
+	CreateItem(ctx context.Context, item *domain.Item, username string) (int64, error)
+	GetItemByID(ctx context.Context, id int64) (*domain.Item, error)
+	UpdateItemWith(ctx context.Context, id int64, update func(item *domain.Item) error, username string) error
+}
+
+type itemsRepository interface {
+	GetItemIDsBySKU(ctx context.Context, skus []string) (map[string]int64, error)
+}
+
+type unitsRepository interface {
+	GetUnits(ctx context.Context) ([]*domain.Unit, error)
+}
+
+type categoriesRepository interface {
+	GetCategories(ctx context.Context) ([]*domain.Category, error)
+}
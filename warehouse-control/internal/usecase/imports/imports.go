@@ -0,0 +1,352 @@
+package imports_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+// progressEvery is the number of rows between saves of a job's progress.
+const progressEvery = 100
+
+type ImportsUsecase struct {
+	repo           importsRepository
+	items          itemsService
+	itemsRepo      itemsRepository
+	unitsRepo      unitsRepository
+	categoriesRepo categoriesRepository
+	maxSize        int64
+	maxRows        int
+	syncRows       int
+	queue          chan *importTask
+	logger         *zlog.Zerolog
+}
+
+// importTask is a job with the rows of its file, which are kept in memory
+// only.
+type importTask struct {
+	job     *domain.ImportJob
+	columns domain.ImportColumns
+	records [][]string
+}
+
+// NewService accepts files of up to maxSize bytes and maxRows rows. Files of
+// up to syncRows rows are imported while the request waits; larger ones are
+// queued for RunWorker, at most queueSize at a time.
+func NewService(repo importsRepository, items itemsService, itemsRepo itemsRepository, unitsRepo unitsRepository, categoriesRepo categoriesRepository,
+	maxSize int64, maxRows, syncRows, queueSize int, logger *zlog.Zerolog) *ImportsUsecase {
+	return &ImportsUsecase{
+		repo:           repo,
+		items:          items,
+		itemsRepo:      itemsRepo,
+		unitsRepo:      unitsRepo,
+		categoriesRepo: categoriesRepo,
+		maxSize:        maxSize,
+		maxRows:        maxRows,
+		syncRows:       syncRows,
+		queue:          make(chan *importTask, queueSize),
+		logger:         logger,
+	}
+}
+
+// StartImport reads an item file and imports it, or queues the import when
+// the file is large. The returned job is finished in the first case and
+// queued in the second.
+func (s *ImportsUsecase) StartImport(ctx context.Context, req domain.ImportRequest, r io.Reader, username string) (*domain.ImportJob, error) {
+	if req.Mode == "" {
+		req.Mode = domain.ImportCreate
+	}
+	if !req.Mode.IsValid() {
+		return nil, fmt.Errorf("%w: unknown import mode %q", customErr.ErrInvalidInput, req.Mode)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read upload: %v", customErr.ErrInvalidInput, err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", customErr.ErrImportTooLarge, s.maxSize)
+	}
+	format := detectFormat(req.FileName, data)
+	records, err := readRecords(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", customErr.ErrInvalidInput)
+	}
+	columns, err := domain.MapImportColumns(records[0], req.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	}
+	records = records[1:]
+	if len(records) > s.maxRows {
+		return nil, fmt.Errorf("%w: the file has %d rows, the limit is %d", customErr.ErrInvalidInput, len(records), s.maxRows)
+	}
+
+	job := &domain.ImportJob{
+		FileName:  req.FileName,
+		Format:    format,
+		Mode:      req.Mode,
+		DryRun:    req.DryRun,
+		Status:    domain.ImportQueued,
+		TotalRows: len(records),
+		CreatedBy: username,
+	}
+	if err := s.repo.CreateImportJob(ctx, job); err != nil {
+		s.logger.Error().Err(err).Str("file", req.FileName).Msg("Failed to create import job")
+		return nil, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", job.ID).Str("file", job.FileName).Int("rows", job.TotalRows).
+		Str("mode", string(job.Mode)).Bool("dry_run", job.DryRun).Str("user", username).Msg("Import started")
+
+	task := &importTask{job: job, columns: columns, records: records}
+	if len(records) <= s.syncRows {
+		// The import goes on if the client stops waiting for it.
+		s.process(context.WithoutCancel(ctx), task)
+		return job, nil
+	}
+	select {
+	case s.queue <- task:
+		return job, nil
+	default:
+		s.finish(ctx, job, domain.ImportFailed, customErr.ErrImportQueueFull.Error())
+		return nil, customErr.ErrImportQueueFull
+	}
+}
+
+func (s *ImportsUsecase) GetImportJob(ctx context.Context, id int64) (*domain.ImportJob, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	job, err := s.repo.GetImportJob(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get import job")
+		return nil, s.mapError(err)
+	}
+	return job, nil
+}
+
+// RunWorker imports the queued files one at a time until ctx is done. Jobs
+// a previous process left unfinished are failed first.
+func (s *ImportsUsecase) RunWorker(ctx context.Context) {
+	n, err := s.repo.FailUnfinishedImportJobs(ctx, "interrupted by a restart")
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to fail unfinished import jobs")
+	} else if n > 0 {
+		s.logger.Warn().Int64("count", n).Msg("Unfinished import jobs failed")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-s.queue:
+			s.process(ctx, task)
+		}
+	}
+}
+
+// process imports the rows of a task and records the outcome of each. A
+// failed row does not stop the import.
+func (s *ImportsUsecase) process(ctx context.Context, task *importTask) {
+	job := task.job
+	started := time.Now()
+	job.Status = domain.ImportRunning
+	job.StartedAt = &started
+	s.save(ctx, job)
+
+	rows := make([]*domain.ImportRow, len(task.records))
+	skus := make([]string, 0, len(task.records))
+	for i, record := range task.records {
+		// Line 1 is the header.
+		if rows[i] = task.columns.ParseRow(i+2, record); rows[i] != nil && rows[i].SKU != "" {
+			skus = append(skus, rows[i].SKU)
+		}
+	}
+	refs, err := s.loadReferences(ctx, skus)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", job.ID).Msg("Failed to load import references")
+		s.finish(ctx, job, domain.ImportFailed, s.mapError(err).Error())
+		return
+	}
+
+	seen := make(map[string]int, len(skus))
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			s.finish(ctx, job, domain.ImportFailed, "interrupted by shutdown")
+			return
+		}
+		if row != nil {
+			s.importRow(ctx, job, refs, seen, row)
+		}
+		job.ProcessedRows++
+		if job.ProcessedRows%progressEvery == 0 {
+			s.save(ctx, job)
+		}
+	}
+	s.finish(ctx, job, domain.ImportDone, "")
+	s.logger.Info().Int64("id", job.ID).Int("created", job.Created).Int("updated", job.Updated).
+		Int("failed", job.Failed).Bool("dry_run", job.DryRun).Msg("Import finished")
+}
+
+// importRow checks a row the way the items API checks a request and, unless
+// the job is a dry run, creates or updates the item.
+func (s *ImportsUsecase) importRow(ctx context.Context, job *domain.ImportJob, refs *references, seen map[string]int, row *domain.ImportRow) {
+	if row.SKU == "" {
+		job.AddError(row.Line, "", errors.New("sku is required"))
+		return
+	}
+	if first, ok := seen[row.SKU]; ok {
+		job.AddError(row.Line, row.SKU, fmt.Errorf("the SKU is already on row %d", first))
+		return
+	}
+	seen[row.SKU] = row.Line
+
+	id, exists := refs.items[row.SKU]
+	if exists && job.Mode == domain.ImportCreate {
+		job.AddError(row.Line, row.SKU, errors.New("an item with the SKU already exists"))
+		return
+	}
+	if exists && !job.DryRun {
+		// The row is applied to the item as it stands when it is written, so
+		// the columns the file leaves out keep any change made meanwhile.
+		err := s.items.UpdateItemWith(ctx, id, func(item *domain.Item) error {
+			if err := row.Apply(item); err != nil {
+				return err
+			}
+			return refs.check(item)
+		}, job.CreatedBy)
+		if err != nil {
+			job.AddError(row.Line, row.SKU, err)
+			return
+		}
+		job.Updated++
+		return
+	}
+
+	item := &domain.Item{}
+	if exists {
+		var err error
+		if item, err = s.items.GetItemByID(ctx, id); err != nil {
+			job.AddError(row.Line, row.SKU, err)
+			return
+		}
+	}
+	if err := row.Apply(item); err != nil {
+		job.AddError(row.Line, row.SKU, err)
+		return
+	}
+	if err := s.validate(ctx, item, exists, refs); err != nil {
+		job.AddError(row.Line, row.SKU, err)
+		return
+	}
+
+	switch {
+	case job.DryRun && exists:
+		job.Updated++
+	case job.DryRun:
+		job.Created++
+	default:
+		if _, err := s.items.CreateItem(ctx, item, job.CreatedBy); err != nil {
+			job.AddError(row.Line, row.SKU, err)
+			return
+		}
+		job.Created++
+	}
+}
+
+// validate runs the checks of CreateItem or UpdateItem, and those the
+// database would make on writing: that the unit and category exist.
+func (s *ImportsUsecase) validate(ctx context.Context, item *domain.Item, exists bool, refs *references) error {
+	if !exists && item.Name == "" {
+		return errors.New("name is required")
+	}
+	validate := s.items.ValidateNewItem
+	if exists {
+		validate = s.items.ValidateItem
+	}
+	if err := validate(ctx, item); err != nil {
+		return err
+	}
+	return refs.check(item)
+}
+
+// references are what the rows of a file are checked against.
+type references struct {
+	items      map[string]int64
+	units      map[string]bool
+	categories map[string]bool
+}
+
+// check makes the checks the database would make on writing the item: that
+// its unit and category exist.
+func (refs *references) check(item *domain.Item) error {
+	if !refs.units[item.BaseUnit] {
+		return fmt.Errorf("%w: unknown unit %q", customErr.ErrInvalidInput, item.BaseUnit)
+	}
+	if item.Category != "" && !refs.categories[item.Category] {
+		return fmt.Errorf("%w: unknown category %q", customErr.ErrInvalidInput, item.Category)
+	}
+	return nil
+}
+
+func (s *ImportsUsecase) loadReferences(ctx context.Context, skus []string) (*references, error) {
+	items, err := s.itemsRepo.GetItemIDsBySKU(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	units, err := s.unitsRepo.GetUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoriesRepo.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := &references{
+		items:      items,
+		units:      make(map[string]bool, len(units)),
+		categories: make(map[string]bool, len(categories)),
+	}
+	for _, u := range units {
+		refs.units[u.Code] = true
+	}
+	for _, c := range categories {
+		refs.categories[c.Path] = true
+	}
+	return refs, nil
+}
+
+// finish records the end of a job. It is saved even when ctx is done, so
+// that a shutdown does not leave the job running.
+func (s *ImportsUsecase) finish(ctx context.Context, job *domain.ImportJob, status domain.ImportStatus, message string) {
+	finished := time.Now()
+	job.Status = status
+	job.Message = message
+	job.FinishedAt = &finished
+	s.save(context.WithoutCancel(ctx), job)
+}
+
+// save stores the progress of a job. Failures are only logged: the import
+// goes on, and the job is saved again later.
+func (s *ImportsUsecase) save(ctx context.Context, job *domain.ImportJob) {
+	if err := s.repo.UpdateImportJob(ctx, job); err != nil {
+		s.logger.Error().Err(err).Int64("id", job.ID).Msg("Failed to save import job")
+	}
+}
+
+func (s *ImportsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrImportNotFound):
+		return customErr.ErrImportNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
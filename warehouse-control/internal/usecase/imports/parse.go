@@ -0,0 +1,80 @@
+package imports_usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// detectFormat tells an XLSX workbook, which is a zip archive, from a CSV
+// file by its content, falling back on the file name for empty files.
+func detectFormat(fileName string, data []byte) domain.ImportFormat {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return domain.ImportXLSX
+	}
+	if len(data) == 0 && strings.EqualFold(path.Ext(fileName), ".xlsx") {
+		return domain.ImportXLSX
+	}
+	return domain.ImportCSV
+}
+
+// readRecords returns the rows of the file, the header first. An XLSX
+// workbook is read from its first sheet, as raw cell values rather than as
+// formatted for display.
+func readRecords(format domain.ImportFormat, data []byte) ([][]string, error) {
+	if format == domain.ImportXLSX {
+		return readXLSX(data)
+	}
+	return readCSV(data)
+}
+
+// readCSV accepts the comma, semicolon and tab separated files spreadsheets
+// save, telling the separator from the header line.
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = detectSeparator(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	return records, nil
+}
+
+func detectSeparator(data []byte) rune {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	best, count := ',', bytes.Count(header, []byte(","))
+	for _, sep := range []rune{';', '\t'} {
+		if n := bytes.Count(header, []byte(string(sep))); n > count {
+			best, count = sep, n
+		}
+	}
+	return best
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open workbook: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("the workbook has no sheets")
+	}
+	rows, err := f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("read sheet %q: %w", sheets[0], err)
+	}
+	return rows, nil
+}
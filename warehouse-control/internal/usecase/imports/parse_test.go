@@ -0,0 +1,48 @@
+package imports_usecase
+
+import (
+	"bytes"
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := append([]byte{0xEF, 0xBB, 0xBF}, "sku;name;price\nA-1;\"Болт; М8\";12,50\nA-2;Гайка\n"...)
+
+	assert.Equal(t, domain.ImportCSV, detectFormat("items.csv", data))
+	records, err := readRecords(domain.ImportCSV, data)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"sku", "name", "price"},
+		{"A-1", "Болт; М8", "12,50"},
+		{"A-2", "Гайка"},
+	}, records)
+}
+
+func TestReadXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"sku", "quantity", "price"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{"A-1", 10, 1234.5}))
+	require.NoError(t, f.SetCellStyle("Sheet1", "C2", "C2", mustStyle(t, f)))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	data := buf.Bytes()
+	assert.Equal(t, domain.ImportXLSX, detectFormat("items.bin", data))
+	records, err := readRecords(domain.ImportXLSX, data)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"sku", "quantity", "price"}, {"A-1", "10", "1234.5"}}, records,
+		"values are read raw, not as formatted")
+}
+
+func mustStyle(t *testing.T, f *excelize.File) int {
+	format := "#,##0.00 ₽"
+	style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	require.NoError(t, err)
+	return style
+}
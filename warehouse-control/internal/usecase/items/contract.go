@@ -8,9 +8,45 @@ import (
 
 type itemsRepository interface {
 	CreateItem(ctx context.Context, item *domain.Item, username string) (int64, error)
-	GetItems(ctx context.Context, limit, offset int, search string) ([]*domain.Item, int, error)
+	CreateItems(ctx context.Context, items []*domain.Item, username string) ([]int64, error)
+	GetItems(ctx context.Context, filter domain.ItemFilter) ([]*domain.Item, int, error)
+	GetStockTotals(ctx context.Context, filter domain.ItemFilter) (*domain.StockTotals, error)
+	ExportItems(ctx context.Context, filter domain.ItemFilter, fn func(*domain.Item) error) error
 	GetItemByID(ctx context.Context, id int64) (*domain.Item, error)
-	UpdateItem(ctx context.Context, id int64, item *domain.Item, username string) error
-	DeleteItem(ctx context.Context, id int64, username string) error
-	BulkDeleteItems(ctx context.Context, ids []int64, username string) error
+	UpdateItem(ctx context.Context, id int64, patch func(item *domain.Item) error, username string) error
+	UpdateItems(ctx context.Context, ids []int64, patch func(i int, item *domain.Item) error, username string) error
+	ChangeStockStatus(ctx context.Context, change *domain.StockStatusChange, username string) error
+	DeleteItem(ctx context.Context, id int64, username string) ([]*domain.Attachment, error)
+	BulkDeleteItems(ctx context.Context, ids []int64, username string) ([]*domain.Attachment, error)
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
+
+// exchangeRates converts item prices into a display currency.
+type exchangeRates interface {
+	GetExchangeRates(ctx context.Context) (*domain.ExchangeRates, error)
+}
+
+// kitReader reads the bill of materials of a kit item.
+type kitReader interface {
+	GetKit(ctx context.Context, itemID int64) (*domain.Kit, error)
+}
+
+// productReader reads the parent products of variant items.
+type productReader interface {
+	GetProductsByIDs(ctx context.Context, ids []int64) ([]*domain.Product, error)
+}
+
+// attributeSchemas resolves the custom attributes of a category path.
+type attributeSchemas interface {
+	GetAttributeSchema(ctx context.Context, path string) (domain.AttributeSchema, error)
+}
+
+// attachmentFiles removes the stored files of attachments deleted with their
+// items.
+type attachmentFiles interface {
+	RemoveFiles(ctx context.Context, attachments []*domain.Attachment)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"warehouse-control/internal/domain"
 
@@ -15,42 +16,61 @@ import (
 
 type ItemsUsecase struct {
 	repo     itemsRepository
-	logger   *zlog.Zerolog
-	validate *validator.Validate
+	watcher  stockWatcher
+	rates    exchangeRates
+	kits     kitReader
+	products productReader
+	schemas  attributeSchemas
+	files    attachmentFiles
+	currency string
+	// bulkLimit caps the items of a bulk create or update.
+	bulkLimit int
+	logger    *zlog.Zerolog
+	validate  *validator.Validate
 }
 
-func NewService(repo itemsRepository, logger *zlog.Zerolog) *ItemsUsecase {
+// NewService prices items without a currency in the given default one and
+// accepts up to bulkLimit items in a bulk create or update.
+func NewService(repo itemsRepository, watcher stockWatcher, rates exchangeRates, kits kitReader, products productReader, schemas attributeSchemas, files attachmentFiles, currency string, bulkLimit int, logger *zlog.Zerolog) *ItemsUsecase {
 	return &ItemsUsecase{
-		repo:     repo,
-		logger:   logger,
-		validate: validator.New(),
+		repo:      repo,
+		watcher:   watcher,
+		rates:     rates,
+		kits:      kits,
+		products:  products,
+		schemas:   schemas,
+		files:     files,
+		currency:  currency,
+		bulkLimit: bulkLimit,
+		logger:    logger,
+		validate:  validator.New(),
 	}
 }
 
 func (s *ItemsUsecase) CreateItem(ctx context.Context, item *domain.Item, username string) (int64, error) {
-	if err := s.validate.Struct(item); err != nil {
-		s.logger.Error().Err(err).Msg("Validation failed")
-		return 0, fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	if err := s.ValidateNewItem(ctx, item); err != nil {
+		return 0, err
 	}
 	s.logger.Info().Str("user", username).Msg("Creating item")
 	id, err := s.repo.CreateItem(ctx, item, username)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to create item")
-		if errors.Is(err, customErr.ErrDatabase) {
-			return 0, customErr.ErrDatabase
-		}
-		return 0, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+		return 0, createError(err)
 	}
+	s.watcher.NotifyStockChanged(id)
 	s.logger.Info().Int64("id", id).Str("user", username).Msg("Item created")
 	return id, nil
 }
 
-func (s *ItemsUsecase) GetItems(ctx context.Context, limit, offset int, search string) ([]*domain.Item, int, error) {
-	if limit <= 0 {
-		limit = 100
+func (s *ItemsUsecase) GetItems(ctx context.Context, filter domain.ItemFilter) ([]*domain.Item, int, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown stock status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
 	}
 	s.logger.Info().Msg("Getting items")
-	items, total, err := s.repo.GetItems(ctx, limit, offset, search)
+	items, total, err := s.repo.GetItems(ctx, filter)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to get items")
 		if errors.Is(err, customErr.ErrDatabase) {
@@ -62,6 +82,45 @@ func (s *ItemsUsecase) GetItems(ctx context.Context, limit, offset int, search s
 	return items, total, nil
 }
 
+// ExportItems calls fn for every item matching the filter, whatever its limit
+// and offset. An error from fn stops the export and is returned as is.
+func (s *ItemsUsecase) ExportItems(ctx context.Context, filter domain.ItemFilter, fn func(*domain.Item) error) error {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return fmt.Errorf("%w: unknown stock status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	s.logger.Info().Msg("Exporting items")
+	count := 0
+	err := s.repo.ExportItems(ctx, filter, func(item *domain.Item) error {
+		count++
+		return fn(item)
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Int("count", count).Msg("Failed to export items")
+		if errors.Is(err, customErr.ErrDatabase) {
+			return customErr.ErrDatabase
+		}
+		return err
+	}
+	s.logger.Info().Int("count", count).Msg("Items exported")
+	return nil
+}
+
+// GetStockTotals sums the stock of the items matching the filter by status.
+func (s *ItemsUsecase) GetStockTotals(ctx context.Context, filter domain.ItemFilter) (*domain.StockTotals, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown stock status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	totals, err := s.repo.GetStockTotals(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get stock totals")
+		if errors.Is(err, customErr.ErrDatabase) {
+			return nil, customErr.ErrDatabase
+		}
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	return totals, nil
+}
+
 func (s *ItemsUsecase) GetItemByID(ctx context.Context, id int64) (*domain.Item, error) {
 	if id <= 0 {
 		return nil, customErr.ErrInvalidInput
@@ -78,31 +137,291 @@ func (s *ItemsUsecase) GetItemByID(ctx context.Context, id int64) (*domain.Item,
 		}
 		return nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
 	}
+	// The availability of a kit follows its components, so it is read with
+	// the item rather than stored.
+	kit, err := s.kits.GetKit(ctx, id)
+	switch {
+	case err == nil:
+		item.Kit = kit
+	case !errors.Is(err, customErr.ErrKitNotFound):
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get kit")
+		return nil, customErr.ErrDatabase
+	}
 	s.logger.Info().Int64("id", id).Msg("Item retrieved")
 	return item, nil
 }
 
-func (s *ItemsUsecase) UpdateItem(ctx context.Context, id int64, item *domain.Item, username string) error {
+// ConvertPrices sets the display price of the items in the given currency.
+func (s *ItemsUsecase) ConvertPrices(ctx context.Context, items []*domain.Item, currency string) error {
+	currency = strings.ToUpper(currency)
+	if !domain.IsCurrencyCode(currency) {
+		return fmt.Errorf("%w: %q is not an ISO 4217 currency code", customErr.ErrInvalidInput, currency)
+	}
+	rates, err := s.rates.GetExchangeRates(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		price, ok := rates.Convert(item.Price, currency)
+		if !ok {
+			return fmt.Errorf("%w: no exchange rate between %s and %s", customErr.ErrInvalidInput, item.Price.Currency, currency)
+		}
+		item.DisplayPrice = &price
+	}
+	return nil
+}
+
+// GroupByProduct splits items into standalone items and the products whose
+// variants are among them, each product carrying those variants.
+func (s *ItemsUsecase) GroupByProduct(ctx context.Context, items []*domain.Item) ([]*domain.Item, []*domain.Product, error) {
+	standalone, groups := domain.GroupVariants(items)
+	if len(groups) == 0 {
+		return standalone, groups, nil
+	}
+	ids := make([]int64, len(groups))
+	for i, g := range groups {
+		ids[i] = g.ID
+	}
+	products, err := s.products.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int64]*domain.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	for i, g := range groups {
+		if p, ok := byID[g.ID]; ok {
+			p.Variants = g.Variants
+			groups[i] = p
+		}
+	}
+	return standalone, groups, nil
+}
+
+// UpdateItem applies a partial update to an item.
+func (s *ItemsUsecase) UpdateItem(ctx context.Context, id int64, patch *domain.ItemPatch, username string) error {
+	return s.UpdateItemWith(ctx, id, func(item *domain.Item) error {
+		patch.Apply(item)
+		return nil
+	}, username)
+}
+
+// UpdateItemWith updates an item with update, which changes the item as read
+// under lock in the transaction that writes it, so that concurrent changes to
+// the fields it leaves alone are kept. The item is validated before it is
+// written; errors of update and of the validation are returned as they are.
+func (s *ItemsUsecase) UpdateItemWith(ctx context.Context, id int64, update func(item *domain.Item) error, username string) error {
 	if id <= 0 {
 		return customErr.ErrInvalidInput
 	}
-	if err := s.validate.Struct(item); err != nil {
-		s.logger.Error().Err(err).Msg("Validation failed")
-		return fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
-	}
 	s.logger.Info().Int64("id", id).Str("user", username).Msg("Updating item")
-	err := s.repo.UpdateItem(ctx, id, item, username)
+	var invalid error
+	err := s.repo.UpdateItem(ctx, id, func(item *domain.Item) error {
+		if invalid = update(item); invalid == nil {
+			invalid = s.ValidateItem(ctx, item)
+		}
+		return invalid
+	}, username)
+	if invalid != nil {
+		return invalid
+	}
 	if err != nil {
 		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to update item")
+		return updateError(err)
+	}
+	s.watcher.NotifyStockChanged(id)
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Item updated")
+	return nil
+}
+
+// CreateItems creates a batch of items and returns a result per item, in the
+// order given. In atomic mode the batch is written in a single transaction,
+// and when an item fails the others report ErrBulkAborted.
+func (s *ItemsUsecase) CreateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode, username string) ([]*domain.BulkItemResult, error) {
+	if err := s.checkBatch(len(items), mode); err != nil {
+		return nil, err
+	}
+	results := newBulkResults(len(items))
+	s.logger.Info().Int("count", len(items)).Str("mode", string(mode)).Str("user", username).Msg("Bulk creating items")
+
+	if mode == domain.BulkBestEffort {
+		for i, item := range items {
+			results[i].ID, results[i].Err = s.CreateItem(ctx, item, username)
+		}
+		return results, nil
+	}
+
+	valid := true
+	for i, item := range items {
+		if err := s.ValidateNewItem(ctx, item); err != nil {
+			results[i].Err = err
+			valid = false
+		}
+	}
+	if !valid {
+		return abortBatch(results), nil
+	}
+	ids, err := s.repo.CreateItems(ctx, items, username)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to bulk create items")
+		var itemErr *domain.BulkItemError
+		if !errors.As(err, &itemErr) {
+			return nil, createError(err)
+		}
+		results[itemErr.Index].Err = createError(itemErr.Err)
+		return abortBatch(results), nil
+	}
+	for i, id := range ids {
+		results[i].ID = id
+		s.watcher.NotifyStockChanged(id)
+	}
+	s.logger.Info().Int("count", len(ids)).Str("user", username).Msg("Items bulk created")
+	return results, nil
+}
+
+// UpdateItems applies a batch of partial updates and returns a result per
+// item, in the order given. In atomic mode the batch is written in a single
+// transaction, and when an item fails the others report ErrBulkAborted.
+func (s *ItemsUsecase) UpdateItems(ctx context.Context, updates []*domain.ItemUpdate, mode domain.BulkMode, username string) ([]*domain.BulkItemResult, error) {
+	if err := s.checkBatch(len(updates), mode); err != nil {
+		return nil, err
+	}
+	results := newBulkResults(len(updates))
+	s.logger.Info().Int("count", len(updates)).Str("mode", string(mode)).Str("user", username).Msg("Bulk updating items")
+
+	seen := make(map[int64]bool, len(updates))
+	valid := true
+	for i, u := range updates {
+		results[i].ID = u.ID
+		if u.ID <= 0 || u.Patch == nil {
+			results[i].Err = customErr.ErrInvalidInput
+		} else if seen[u.ID] {
+			results[i].Err = fmt.Errorf("%w: item %d is updated more than once", customErr.ErrInvalidInput, u.ID)
+		}
+		seen[u.ID] = true
+		if results[i].Err != nil {
+			valid = false
+		}
+	}
+
+	if mode == domain.BulkBestEffort {
+		for i, u := range updates {
+			if results[i].Err == nil {
+				results[i].Err = s.UpdateItem(ctx, u.ID, u.Patch, username)
+			}
+		}
+		return results, nil
+	}
+
+	if !valid {
+		return abortBatch(results), nil
+	}
+	if err := s.patchItems(ctx, updates, results, username); err != nil {
+		var itemErr *domain.BulkItemError
+		if !errors.As(err, &itemErr) {
+			return nil, err
+		}
+		return abortBatch(results), nil
+	}
+	s.logger.Info().Int("count", len(updates)).Str("user", username).Msg("Items bulk updated")
+	return results, nil
+}
+
+// patchItems applies the patches in a single transaction. Each item is read
+// and validated after the repository has locked it, so concurrent changes to
+// fields the patch leaves alone are kept. The error of the item that failed
+// is also set on its result.
+func (s *ItemsUsecase) patchItems(ctx context.Context, updates []*domain.ItemUpdate, results []*domain.BulkItemResult, username string) error {
+	ids := make([]int64, len(updates))
+	for i, u := range updates {
+		ids[i] = u.ID
+	}
+	err := s.repo.UpdateItems(ctx, ids, func(i int, item *domain.Item) error {
+		updates[i].Patch.Apply(item)
+		if err := s.ValidateItem(ctx, item); err != nil {
+			results[i].Err = err
+			return err
+		}
+		return nil
+	}, username)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to bulk update items")
+		var itemErr *domain.BulkItemError
+		if !errors.As(err, &itemErr) {
+			return updateError(err)
+		}
+		if results[itemErr.Index].Err == nil {
+			results[itemErr.Index].Err = updateError(itemErr.Err)
+		}
+		return err
+	}
+	for _, id := range ids {
+		s.watcher.NotifyStockChanged(id)
+	}
+	return nil
+}
+
+func (s *ItemsUsecase) checkBatch(n int, mode domain.BulkMode) error {
+	if !mode.IsValid() {
+		return fmt.Errorf("%w: unknown bulk mode %q", customErr.ErrInvalidInput, mode)
+	}
+	if n == 0 || n > s.bulkLimit {
+		return fmt.Errorf("%w: a batch holds 1 to %d items", customErr.ErrInvalidInput, s.bulkLimit)
+	}
+	return nil
+}
+
+func newBulkResults(n int) []*domain.BulkItemResult {
+	results := make([]*domain.BulkItemResult, n)
+	for i := range results {
+		results[i] = &domain.BulkItemResult{}
+	}
+	return results
+}
+
+// abortBatch marks the items of a failed atomic batch that did not fail
+// themselves. Nothing of the batch was written, so no result keeps the ID of
+// a created item.
+func abortBatch(results []*domain.BulkItemResult) []*domain.BulkItemResult {
+	for _, r := range results {
+		if r.Err == nil {
+			r.Err = customErr.ErrBulkAborted
+		}
+	}
+	return results
+}
+
+// ChangeStockStatus moves stock of an item between statuses, e.g. marks
+// goods as damaged or puts them on a QA hold. Quarantine is left to returns.
+func (s *ItemsUsecase) ChangeStockStatus(ctx context.Context, change *domain.StockStatusChange, username string) error {
+	if change.ItemID <= 0 || change.Quantity <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if !change.From.IsValid() || !change.To.IsValid() || change.From == change.To {
+		return fmt.Errorf("%w: stock moves between two different statuses", customErr.ErrInvalidInput)
+	}
+	if change.From == domain.StockQuarantine || change.To == domain.StockQuarantine {
+		return fmt.Errorf("%w: quarantined stock is released through returns", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Int64("id", change.ItemID).Str("from", string(change.From)).Str("to", string(change.To)).
+		Int("quantity", change.Quantity).Str("user", username).Msg("Changing stock status")
+	err := s.repo.ChangeStockStatus(ctx, change, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", change.ItemID).Msg("Failed to change stock status")
 		if errors.Is(err, customErr.ErrItemNotFound) {
 			return customErr.ErrItemNotFound
 		}
+		if errors.Is(err, customErr.ErrInsufficientStock) {
+			return err
+		}
 		if errors.Is(err, customErr.ErrDatabase) {
 			return customErr.ErrDatabase
 		}
 		return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
 	}
-	s.logger.Info().Int64("id", id).Str("user", username).Msg("Item updated")
+	s.watcher.NotifyStockChanged(change.ItemID)
+	s.logger.Info().Int64("id", change.ItemID).Str("user", username).Msg("Stock status changed")
 	return nil
 }
 
@@ -111,31 +430,153 @@ func (s *ItemsUsecase) DeleteItem(ctx context.Context, id int64, username string
 		return customErr.ErrInvalidInput
 	}
 	s.logger.Info().Int64("id", id).Str("user", username).Msg("Deleting item")
-	err := s.repo.DeleteItem(ctx, id, username)
+	attachments, err := s.repo.DeleteItem(ctx, id, username)
 	if err != nil {
 		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to delete item")
 		if errors.Is(err, customErr.ErrItemNotFound) {
 			return customErr.ErrItemNotFound
 		}
+		if errors.Is(err, customErr.ErrInvalidInput) {
+			return err
+		}
 		if errors.Is(err, customErr.ErrDatabase) {
 			return customErr.ErrDatabase
 		}
 		return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
 	}
+	s.files.RemoveFiles(ctx, attachments)
 	s.logger.Info().Int64("id", id).Str("user", username).Msg("Item deleted")
 	return nil
 }
 
 func (s *ItemsUsecase) BulkDeleteItems(ctx context.Context, ids []int64, username string) error {
 	s.logger.Info().Str("user", username).Msg("Bulk deleting items")
-	err := s.repo.BulkDeleteItems(ctx, ids, username)
+	attachments, err := s.repo.BulkDeleteItems(ctx, ids, username)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to bulk delete items")
+		if errors.Is(err, customErr.ErrInvalidInput) {
+			return err
+		}
 		if errors.Is(err, customErr.ErrDatabase) {
 			return customErr.ErrDatabase
 		}
 		return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
 	}
+	s.files.RemoveFiles(ctx, attachments)
 	s.logger.Info().Str("user", username).Msg("Items bulk deleted")
 	return nil
 }
+
+func createError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidInput):
+		return err
+	case errors.Is(err, customErr.ErrItemExists):
+		return customErr.ErrItemExists
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
+
+func updateError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock):
+		return customErr.ErrInsufficientStock
+	case errors.Is(err, customErr.ErrItemExists):
+		return customErr.ErrItemExists
+	}
+	return createError(err)
+}
+
+// ValidateNewItem checks an item about to be created and normalizes it the
+// way CreateItem does, without writing anything.
+func (s *ItemsUsecase) ValidateNewItem(ctx context.Context, item *domain.Item) error {
+	if item.Serialized && item.Quantity != 0 {
+		return fmt.Errorf("%w: stock of a serialized item is received with its serial numbers", customErr.ErrInvalidInput)
+	}
+	return s.ValidateItem(ctx, item)
+}
+
+// ValidateItem checks the fields of an item and normalizes them the way
+// UpdateItem does, without writing anything.
+func (s *ItemsUsecase) ValidateItem(ctx context.Context, item *domain.Item) error {
+	if err := s.validate.Struct(item); err != nil {
+		s.logger.Error().Err(err).Msg("Validation failed")
+		return fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	}
+	if err := validateReorder(item); err != nil {
+		return err
+	}
+	if err := s.validatePrice(item); err != nil {
+		return err
+	}
+	if err := validateCost(item); err != nil {
+		return err
+	}
+	normalizeBaseUnit(item)
+	item.Category = domain.NormalizeCategoryPath(item.Category)
+	return s.validateAttributes(ctx, item)
+}
+
+func validateReorder(item *domain.Item) error {
+	if item.ReorderLevel < 0 || item.ReorderQuantity < 0 {
+		return fmt.Errorf("%w: reorder level and quantity must not be negative", customErr.ErrInvalidInput)
+	}
+	return nil
+}
+
+// validatePrice defaults the currency of the item's price and keeps the
+// amount to MoneyPlaces.
+func (s *ItemsUsecase) validatePrice(item *domain.Item) error {
+	item.Price.Currency = strings.ToUpper(strings.TrimSpace(item.Price.Currency))
+	if item.Price.Currency == "" {
+		item.Price.Currency = s.currency
+	}
+	if !domain.IsCurrencyCode(item.Price.Currency) {
+		return fmt.Errorf("%w: %q is not an ISO 4217 currency code", customErr.ErrInvalidInput, item.Price.Currency)
+	}
+	if item.Price.Amount.Sign() < 0 {
+		return fmt.Errorf("%w: price must not be negative", customErr.ErrInvalidInput)
+	}
+	item.Price.Amount = item.Price.Amount.Round(domain.MoneyPlaces)
+	return nil
+}
+
+// validateCost checks the item's costing method and the cost its initial
+// stock is received at.
+func validateCost(item *domain.Item) error {
+	if item.CostMethod != nil && !item.CostMethod.IsValid() {
+		return fmt.Errorf("%w: unknown cost method %q", customErr.ErrInvalidInput, *item.CostMethod)
+	}
+	if item.UnitCost.Sign() < 0 {
+		return fmt.Errorf("%w: unit cost must not be negative", customErr.ErrInvalidInput)
+	}
+	item.UnitCost = item.UnitCost.Round(domain.CostPlaces)
+	return nil
+}
+
+// validateAttributes checks the custom attributes of the item against the
+// schema of its category and normalizes their values.
+func (s *ItemsUsecase) validateAttributes(ctx context.Context, item *domain.Item) error {
+	schema, err := s.schemas.GetAttributeSchema(ctx, item.Category)
+	if err != nil {
+		return err
+	}
+	attrs, err := schema.Validate(item.Attributes)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	}
+	item.Attributes = attrs
+	return nil
+}
+
+// normalizeBaseUnit defaults the base unit of the item to DefaultUnit.
+func normalizeBaseUnit(item *domain.Item) {
+	item.BaseUnit = strings.ToLower(strings.TrimSpace(item.BaseUnit))
+	if item.BaseUnit == "" {
+		item.BaseUnit = domain.DefaultUnit
+	}
+}
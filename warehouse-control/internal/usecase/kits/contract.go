@@ -0,0 +1,19 @@
+package kits_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type kitsRepository interface {
+	GetKit(ctx context.Context, itemID int64) (*domain.Kit, error)
+	SetKit(ctx context.Context, kit *domain.Kit) error
+	DeleteKit(ctx context.Context, itemID int64) error
+	AssembleKit(ctx context.Context, a *domain.KitAssembly, username string) error
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
@@ -0,0 +1,121 @@
+package kits_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type KitsUsecase struct {
+	repo    kitsRepository
+	watcher stockWatcher
+	logger  *zlog.Zerolog
+}
+
+func NewService(repo kitsRepository, watcher stockWatcher, logger *zlog.Zerolog) *KitsUsecase {
+	return &KitsUsecase{
+		repo:    repo,
+		watcher: watcher,
+		logger:  logger,
+	}
+}
+
+func (s *KitsUsecase) GetKit(ctx context.Context, itemID int64) (*domain.Kit, error) {
+	if itemID <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	kit, err := s.repo.GetKit(ctx, itemID)
+	if err != nil {
+		if !errors.Is(err, customErr.ErrKitNotFound) {
+			s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to get kit")
+		}
+		return nil, s.mapError(err)
+	}
+	return kit, nil
+}
+
+// SetKit replaces the bill of materials of an item.
+func (s *KitsUsecase) SetKit(ctx context.Context, kit *domain.Kit, username string) error {
+	if kit.ItemID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if len(kit.Components) == 0 {
+		return fmt.Errorf("%w: a kit needs at least one component", customErr.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(kit.Components))
+	for _, c := range kit.Components {
+		c.SKU = strings.TrimSpace(c.SKU)
+		if c.SKU == "" || c.Quantity <= 0 {
+			return fmt.Errorf("%w: each component needs a SKU and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if seen[c.SKU] {
+			return fmt.Errorf("%w: SKU %s appears on more than one component", customErr.ErrInvalidInput, c.SKU)
+		}
+		seen[c.SKU] = true
+	}
+	s.logger.Info().Int64("item_id", kit.ItemID).Int("components", len(kit.Components)).Str("user", username).Msg("Setting kit")
+	if err := s.repo.SetKit(ctx, kit); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", kit.ItemID).Msg("Failed to set kit")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *KitsUsecase) DeleteKit(ctx context.Context, itemID int64, username string) error {
+	if itemID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("item_id", itemID).Str("user", username).Msg("Deleting kit")
+	if err := s.repo.DeleteKit(ctx, itemID); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to delete kit")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+// AssembleKit produces kits from their components, or breaks kits back down
+// into them, in one transaction.
+func (s *KitsUsecase) AssembleKit(ctx context.Context, a *domain.KitAssembly, username string) error {
+	if a.KitID <= 0 || a.Quantity <= 0 {
+		return fmt.Errorf("%w: a kit and a positive quantity are required", customErr.ErrInvalidInput)
+	}
+	if a.LocationID != nil && *a.LocationID <= 0 {
+		return fmt.Errorf("%w: invalid location id", customErr.ErrInvalidInput)
+	}
+	a.Reference = strings.TrimSpace(a.Reference)
+	s.logger.Info().Int64("kit_id", a.KitID).Int("quantity", a.Quantity).Bool("disassemble", a.Disassemble).
+		Str("user", username).Msg("Assembling kit")
+	if err := s.repo.AssembleKit(ctx, a, username); err != nil {
+		s.logger.Error().Err(err).Int64("kit_id", a.KitID).Msg("Failed to assemble kit")
+		return s.mapError(err)
+	}
+	for _, m := range a.Movements {
+		s.watcher.NotifyStockChanged(m.ItemID)
+	}
+	s.logger.Info().Int64("kit_id", a.KitID).Str("user", username).Msg("Kit assembled")
+	return nil
+}
+
+func (s *KitsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrKitNotFound):
+		return customErr.ErrKitNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock):
+		return customErr.ErrInsufficientStock
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrInvalidInput),
+		errors.Is(err, customErr.ErrLotNotFound), errors.Is(err, customErr.ErrSerialNotFound):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
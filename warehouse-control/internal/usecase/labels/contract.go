@@ -0,0 +1,15 @@
+package labels_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type itemsRepository interface {
+	GetItemByID(ctx context.Context, id int64) (*domain.Item, error)
+}
+
+type locationsRepository interface {
+	GetLocationByID(ctx context.Context, id int64) (*domain.Location, error)
+}
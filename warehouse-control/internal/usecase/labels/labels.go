@@ -0,0 +1,158 @@
+package labels_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+// MaxScale bounds the size a single label is rendered at.
+const MaxScale = 10
+
+// maxSheetLabels bounds the number of labels printed on sheets at once.
+const maxSheetLabels = 1000
+
+type LabelsUsecase struct {
+	itemsRepo     itemsRepository
+	locationsRepo locationsRepository
+	logger        *zlog.Zerolog
+}
+
+func NewService(itemsRepo itemsRepository, locationsRepo locationsRepository, logger *zlog.Zerolog) *LabelsUsecase {
+	return &LabelsUsecase{
+		itemsRepo:     itemsRepo,
+		locationsRepo: locationsRepo,
+		logger:        logger,
+	}
+}
+
+// ItemLabel renders the label of an item: its SKU in the barcode and its
+// name above it. scale multiplies the size of the barcode modules.
+func (s *LabelsUsecase) ItemLabel(ctx context.Context, id int64, symbology domain.Symbology, format domain.LabelFormat, scale int) ([]byte, error) {
+	if id <= 0 || !symbology.IsValid() || !format.IsValid() {
+		return nil, customErr.ErrInvalidInput
+	}
+	if scale < 1 || scale > MaxScale {
+		return nil, fmt.Errorf("%w: scale must be between 1 and %d", customErr.ErrInvalidInput, MaxScale)
+	}
+	item, err := s.itemsRepo.GetItemByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get item")
+		return nil, s.mapError(err)
+	}
+	label, err := prepareLabel(domain.Label{Code: item.SKU, Title: item.Name}, symbology)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == domain.LabelZPL {
+		return renderZPL(label, symbology, scale), nil
+	}
+	bc, err := encode(label.Code, symbology)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot encode %q as %s: %v", customErr.ErrInvalidInput, label.Code, symbology, err)
+	}
+	grid := modules(bc)
+	if format == domain.LabelSVG {
+		return renderSVG(label, grid, scale), nil
+	}
+	data, err := renderPNG(label, grid, scale)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to render label")
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	return data, nil
+}
+
+// LabelSheet renders a PDF of A4 sheets with a label for every selected
+// item and bin, items first, in the order they were requested. Bin labels
+// carry the location path.
+func (s *LabelsUsecase) LabelSheet(ctx context.Context, req domain.LabelSheetRequest) ([]byte, error) {
+	if req.Symbology == "" {
+		req.Symbology = domain.SymbologyCode128
+	}
+	if !req.Symbology.IsValid() {
+		return nil, fmt.Errorf("%w: unknown symbology %q", customErr.ErrInvalidInput, req.Symbology)
+	}
+	total := len(req.ItemIDs) + len(req.LocationIDs)
+	if total == 0 {
+		return nil, fmt.Errorf("%w: select items or bins to print", customErr.ErrInvalidInput)
+	}
+	if total > maxSheetLabels {
+		return nil, fmt.Errorf("%w: at most %d labels can be printed at once", customErr.ErrInvalidInput, maxSheetLabels)
+	}
+
+	labels := make([]domain.Label, 0, total)
+	for _, id := range req.ItemIDs {
+		if id <= 0 {
+			return nil, customErr.ErrInvalidInput
+		}
+		item, err := s.itemsRepo.GetItemByID(ctx, id)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("item_id", id).Msg("Failed to get item")
+			return nil, s.mapError(err)
+		}
+		labels = append(labels, domain.Label{Code: item.SKU, Title: item.Name})
+	}
+	for _, id := range req.LocationIDs {
+		if id <= 0 {
+			return nil, customErr.ErrInvalidInput
+		}
+		loc, err := s.locationsRepo.GetLocationByID(ctx, id)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("location_id", id).Msg("Failed to get location")
+			return nil, s.mapError(err)
+		}
+		labels = append(labels, domain.Label{Code: loc.Path, Title: loc.Name})
+	}
+	for i := range labels {
+		label, err := prepareLabel(labels[i], req.Symbology)
+		if err != nil {
+			return nil, err
+		}
+		labels[i] = label
+	}
+
+	s.logger.Info().Int("labels", len(labels)).Str("symbology", string(req.Symbology)).Msg("Rendering label sheet")
+	data, err := renderSheet(labels, req.Symbology)
+	if err != nil {
+		if errors.Is(err, customErr.ErrInvalidInput) {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Msg("Failed to render label sheet")
+		return nil, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	return data, nil
+}
+
+// prepareLabel checks that the code can be printed in the symbology.
+func prepareLabel(label domain.Label, symbology domain.Symbology) (domain.Label, error) {
+	if label.Code == "" {
+		return label, fmt.Errorf("%w: %q has no code to print", customErr.ErrInvalidInput, label.Title)
+	}
+	if symbology == domain.SymbologyEAN13 {
+		code, err := domain.NormalizeEAN13(label.Code)
+		if err != nil {
+			return label, fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+		}
+		label.Code = code
+	}
+	return label, nil
+}
+
+func (s *LabelsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,226 @@
+package labels_usecase
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Label geometry, in modules (the narrowest bar or a QR cell) of the code.
+const (
+	quietModules  = 10 // blank margin around 1D codes
+	barHeight     = 50 // height of 1D bars
+	qrQuietCells  = 4
+	qrCellModules = 4 // QR cells are drawn bigger than 1D modules
+)
+
+var labelFont = mustParseFont()
+
+func mustParseFont() *opentype.Font {
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		panic(fmt.Sprintf("parse label font: %v", err))
+	}
+	return f
+}
+
+// encode turns the label code into a barcode. EAN-13 codes must already be
+// normalized.
+func encode(code string, s domain.Symbology) (barcode.Barcode, error) {
+	switch s {
+	case domain.SymbologyEAN13:
+		return ean.Encode(code)
+	case domain.SymbologyQR:
+		return qr.Encode(code, qr.M, qr.Auto)
+	}
+	return code128.Encode(code)
+}
+
+// modules returns the barcode as a grid of dark modules. A 1D code is a
+// single row.
+func modules(bc barcode.Barcode) [][]bool {
+	b := bc.Bounds()
+	rows := 1
+	if bc.Metadata().Dimensions == 2 {
+		rows = b.Dy()
+	}
+	grid := make([][]bool, rows)
+	for y := range grid {
+		grid[y] = make([]bool, b.Dx())
+		for x := range grid[y] {
+			r, _, _, _ := bc.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			grid[y][x] = r < 0x8000
+		}
+	}
+	return grid
+}
+
+// layout places the modules of a code in pixels: each module is cell×height
+// pixels, with quiet margins around.
+type layout struct {
+	cell, height, quiet int
+	width               int
+}
+
+func newLayout(grid [][]bool, scale int) layout {
+	l := layout{cell: scale, height: barHeight * scale, quiet: quietModules * scale}
+	if len(grid) > 1 {
+		l.cell = qrCellModules * scale
+		l.height = l.cell
+		l.quiet = qrQuietCells * l.cell
+	}
+	l.width = len(grid[0])*l.cell + 2*l.quiet
+	return l
+}
+
+// renderPNG draws the title, the barcode and the code under it.
+func renderPNG(label domain.Label, grid [][]bool, scale int) ([]byte, error) {
+	l := newLayout(grid, scale)
+	face, err := opentype.NewFace(labelFont, &opentype.FaceOptions{Size: float64(6 * scale), DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("load font: %w", err)
+	}
+	defer func() { _ = face.Close() }()
+	lineHeight := face.Metrics().Height.Ceil()
+
+	codeHeight := len(grid) * l.height
+	height := l.quiet/2 + lineHeight + codeHeight + lineHeight + l.quiet/2
+	img := image.NewGray(image.Rect(0, 0, l.width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	top := l.quiet / 2
+	drawText(img, face, fitText(face, label.Title, l.width-scale*4), top)
+	top += lineHeight
+	for y, row := range grid {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			r := image.Rect(l.quiet+x*l.cell, top+y*l.height, l.quiet+(x+1)*l.cell, top+(y+1)*l.height)
+			draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+		}
+	}
+	top += codeHeight
+	drawText(img, face, fitText(face, label.Code, l.width-scale*4), top)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawText centres a line of text whose top is at y.
+func drawText(img draw.Image, face font.Face, text string, y int) {
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(color.Black), Face: face}
+	width := d.MeasureString(text).Ceil()
+	d.Dot = fixed.P((img.Bounds().Dx()-width)/2, y+face.Metrics().Ascent.Ceil())
+	d.DrawString(text)
+}
+
+// fitText shortens text with an ellipsis until it fits the width in pixels.
+func fitText(face font.Face, text string, width int) string {
+	if font.MeasureString(face, text).Ceil() <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if s := string(runes) + "…"; font.MeasureString(face, s).Ceil() <= width {
+			return s
+		}
+	}
+	return ""
+}
+
+// renderSVG draws the label as vector graphics; runs of dark modules become
+// single rectangles.
+func renderSVG(label domain.Label, grid [][]bool, scale int) []byte {
+	l := newLayout(grid, scale)
+	fontSize := 6 * scale
+	lineHeight := fontSize * 3 / 2
+	codeHeight := len(grid) * l.height
+	height := l.quiet/2 + lineHeight + codeHeight + lineHeight + l.quiet/2
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, l.width, height, l.width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#fff"/>`)
+	textAt := func(text string, top int) {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" text-anchor="middle">%s</text>`,
+			l.width/2, top+fontSize, fontSize, svgEscape(text))
+	}
+
+	top := l.quiet / 2
+	textAt(label.Title, top)
+	top += lineHeight
+	b.WriteString(`<g fill="#000">`)
+	for y, row := range grid {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d"/>`,
+				l.quiet+start*l.cell, top+y*l.height, (x-start)*l.cell, l.height)
+		}
+	}
+	b.WriteString(`</g>`)
+	top += codeHeight
+	textAt(label.Code, top)
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+var svgReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func svgEscape(s string) string {
+	return svgReplacer.Replace(s)
+}
+
+// renderZPL returns printer commands for a Zebra label: the printer draws the
+// barcode itself. Field data goes through ^FH so that control characters in
+// names cannot break the format.
+func renderZPL(label domain.Label, s domain.Symbology, scale int) []byte {
+	var b strings.Builder
+	b.WriteString("^XA\n^CI28\n")
+	fmt.Fprintf(&b, "^FO20,20^A0N,28,28^FH^FD%s^FS\n", zplEscape(label.Title))
+	switch s {
+	case domain.SymbologyEAN13:
+		// The printer adds the check digit itself.
+		fmt.Fprintf(&b, "^FO20,60^BY%d^BEN,100,Y,N^FD%s^FS\n", scale, label.Code[:12])
+	case domain.SymbologyQR:
+		// The size of a QR code depends on its content, so there is no
+		// plain text line under it.
+		fmt.Fprintf(&b, "^FO20,60^BQN,2,%d^FH^FDQA,%s^FS\n", 2*scale+1, zplEscape(label.Code))
+	default:
+		fmt.Fprintf(&b, "^FO20,60^BY%d^BCN,100,Y,N,N^FH^FD%s^FS\n", scale, zplEscape(label.Code))
+	}
+	b.WriteString("^XZ\n")
+	return []byte(b.String())
+}
+
+// zplEscape hex-encodes the characters ^FH treats specially.
+var zplReplacer = strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E")
+
+func zplEscape(s string) string {
+	return zplReplacer.Replace(s)
+}
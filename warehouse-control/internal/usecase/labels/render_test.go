@@ -0,0 +1,42 @@
+package labels_usecase
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderZPLEscapesFieldData(t *testing.T) {
+	zpl := string(renderZPL(domain.Label{Code: "A_1^2", Title: "Болт ~M8"}, domain.SymbologyCode128, 2))
+
+	assert.True(t, strings.HasPrefix(zpl, "^XA\n^CI28\n"))
+	assert.Contains(t, zpl, "^FDБолт _7EM8^FS")
+	assert.Contains(t, zpl, "^BY2^BCN,100,Y,N,N^FH^FDA_5F1_5E2^FS")
+	assert.True(t, strings.HasSuffix(zpl, "^XZ\n"))
+}
+
+func TestRenderSVGMergesBars(t *testing.T) {
+	grid := [][]bool{{true, true, false, true}}
+	svg := string(renderSVG(domain.Label{Code: "<1>", Title: "a&b"}, grid, 1))
+
+	assert.Equal(t, 3, strings.Count(svg, "<rect"), "background and two bars")
+	assert.Contains(t, svg, `<rect x="10" y="14" width="2" height="50"/>`)
+	assert.Contains(t, svg, "&lt;1&gt;")
+	assert.Contains(t, svg, "a&amp;b")
+}
+
+func TestRenderSheetStartsNewPages(t *testing.T) {
+	labels := make([]domain.Label, sheetColumns*sheetRows+1)
+	for i := range labels {
+		labels[i] = domain.Label{Code: "SKU-1", Title: "Товар"}
+	}
+	pdf, err := renderSheet(labels, domain.SymbologyCode128)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+	assert.Equal(t, 2, bytes.Count(pdf, []byte("/Type /Page\n")))
+}
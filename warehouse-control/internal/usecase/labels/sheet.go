@@ -0,0 +1,127 @@
+package labels_usecase
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	"warehouse-control/internal/domain"
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Sheet geometry in millimetres: A4 with 3×8 labels of 70×37.125, the common
+// adhesive label sheet.
+const (
+	sheetColumns = 3
+	sheetRows    = 8
+	labelWidth   = 210.0 / sheetColumns
+	labelHeight  = 297.0 / sheetRows
+	labelPadding = 3.0
+	textHeight   = 4.0
+	fontSize     = 8.0
+	sheetFont    = "goregular"
+)
+
+// renderSheet lays the labels out row by row, starting a new page every
+// sheetColumns×sheetRows labels.
+func renderSheet(labels []domain.Label, symbology domain.Symbology) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes(sheetFont, "", goregular.TTF)
+	pdf.SetFont(sheetFont, "", fontSize)
+
+	perPage := sheetColumns * sheetRows
+	for i, label := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+		x := float64(i%sheetColumns) * labelWidth
+		y := float64(i%perPage/sheetColumns) * labelHeight
+		if err := drawSheetLabel(pdf, label, symbology, strconv.Itoa(i), x, y); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("write pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawSheetLabel draws one label with its top left corner at x, y: the
+// title, the barcode stretched over the space left and the code under it.
+func drawSheetLabel(pdf *fpdf.Fpdf, label domain.Label, symbology domain.Symbology, name string, x, y float64) error {
+	bc, err := encode(label.Code, symbology)
+	if err != nil {
+		return fmt.Errorf("%w: cannot encode %q as %s: %v", customErr.ErrInvalidInput, label.Code, symbology, err)
+	}
+	grid := modules(bc)
+	img, err := barcodePNG(grid)
+	if err != nil {
+		return err
+	}
+	opts := fpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(img))
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("add barcode image: %w", err)
+	}
+
+	inner := labelWidth - 2*labelPadding
+	codeWidth, codeHeight := inner, labelHeight-2*labelPadding-2*textHeight
+	if len(grid) > 1 {
+		codeWidth = codeHeight // QR codes stay square
+	}
+
+	pdf.SetXY(x+labelPadding, y+labelPadding)
+	pdf.CellFormat(inner, textHeight, fitCell(pdf, label.Title, inner), "", 0, "C", false, 0, "")
+	pdf.ImageOptions(name, x+(labelWidth-codeWidth)/2, y+labelPadding+textHeight, codeWidth, codeHeight, false, opts, 0, "")
+	pdf.SetXY(x+labelPadding, y+labelHeight-labelPadding-textHeight)
+	pdf.CellFormat(inner, textHeight, fitCell(pdf, label.Code, inner), "", 0, "C", false, 0, "")
+	return nil
+}
+
+// barcodePNG draws the modules one pixel each, with the quiet zone the
+// symbology needs around them; the PDF scales the image to the label.
+func barcodePNG(grid [][]bool) ([]byte, error) {
+	qx, qy := quietModules, 0
+	if len(grid) > 1 {
+		qx, qy = qrQuietCells, qrQuietCells
+	}
+	img := image.NewGray(image.Rect(0, 0, len(grid[0])+2*qx, len(grid)+2*qy))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for y, row := range grid {
+		for x, dark := range row {
+			if dark {
+				img.Pix[(y+qy)*img.Stride+x+qx] = 0
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode barcode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fitCell shortens text with an ellipsis until it fits the width.
+func fitCell(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if s := string(runes) + "…"; pdf.GetStringWidth(s) <= width {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,15 @@
+package locations_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type locationsRepository interface {
+	CreateLocation(ctx context.Context, loc *domain.Location) (int64, error)
+	GetLocations(ctx context.Context, filter domain.LocationFilter) ([]*domain.Location, int, error)
+	GetLocationByID(ctx context.Context, id int64) (*domain.Location, error)
+	UpdateLocation(ctx context.Context, id int64, name string) error
+	DeleteLocation(ctx context.Context, id int64) error
+}
@@ -0,0 +1,148 @@
+package locations_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+const pathSeparator = "/"
+
+type LocationsUsecase struct {
+	repo   locationsRepository
+	logger *zlog.Zerolog
+}
+
+func NewService(repo locationsRepository, logger *zlog.Zerolog) *LocationsUsecase {
+	return &LocationsUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreateLocation checks that the location hangs under the right level of the
+// warehouse → zone → aisle → bin hierarchy and derives its path and warehouse.
+func (s *LocationsUsecase) CreateLocation(ctx context.Context, loc *domain.Location) (int64, error) {
+	loc.Code = strings.TrimSpace(loc.Code)
+	if !loc.Type.IsValid() {
+		return 0, fmt.Errorf("%w: unknown location type %q", customErr.ErrInvalidInput, loc.Type)
+	}
+	if loc.Code == "" || strings.Contains(loc.Code, pathSeparator) {
+		return 0, fmt.Errorf("%w: code must be non-empty and must not contain %q", customErr.ErrInvalidInput, pathSeparator)
+	}
+
+	if loc.Type == domain.LocationWarehouse {
+		if loc.ParentID != nil {
+			return 0, fmt.Errorf("%w: warehouse cannot have a parent", customErr.ErrInvalidInput)
+		}
+		loc.Path = loc.Code
+		loc.WarehouseID = 0
+	} else {
+		if loc.ParentID == nil {
+			return 0, fmt.Errorf("%w: %s requires a parent %s", customErr.ErrInvalidInput, loc.Type, loc.Type.ParentType())
+		}
+		parent, err := s.repo.GetLocationByID(ctx, *loc.ParentID)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("parent_id", *loc.ParentID).Msg("Failed to get parent location")
+			if errors.Is(err, customErr.ErrLocationNotFound) {
+				return 0, fmt.Errorf("%w: parent location not found", customErr.ErrInvalidInput)
+			}
+			return 0, s.mapError(err)
+		}
+		if parent.Type != loc.Type.ParentType() {
+			return 0, fmt.Errorf("%w: %s must be placed under a %s, got %s", customErr.ErrInvalidInput, loc.Type, loc.Type.ParentType(), parent.Type)
+		}
+		loc.Path = parent.Path + pathSeparator + loc.Code
+		loc.WarehouseID = parent.WarehouseID
+	}
+	if loc.Name == "" {
+		loc.Name = loc.Code
+	}
+
+	s.logger.Info().Str("path", loc.Path).Msg("Creating location")
+	id, err := s.repo.CreateLocation(ctx, loc)
+	if err != nil {
+		s.logger.Error().Err(err).Str("path", loc.Path).Msg("Failed to create location")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("path", loc.Path).Msg("Location created")
+	return id, nil
+}
+
+func (s *LocationsUsecase) GetLocations(ctx context.Context, filter domain.LocationFilter) ([]*domain.Location, int, error) {
+	if filter.Type != nil && !filter.Type.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown location type %q", customErr.ErrInvalidInput, *filter.Type)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting locations")
+	locations, total, err := s.repo.GetLocations(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get locations")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(locations)).Msg("Locations retrieved")
+	return locations, total, nil
+}
+
+func (s *LocationsUsecase) GetLocationByID(ctx context.Context, id int64) (*domain.Location, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	loc, err := s.repo.GetLocationByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get location")
+		return nil, s.mapError(err)
+	}
+	return loc, nil
+}
+
+// UpdateLocation only renames a location; codes are part of the stored path
+// of every descendant and are therefore immutable.
+func (s *LocationsUsecase) UpdateLocation(ctx context.Context, id int64, name string) error {
+	if id <= 0 || strings.TrimSpace(name) == "" {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Msg("Updating location")
+	if err := s.repo.UpdateLocation(ctx, id, name); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to update location")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Msg("Location updated")
+	return nil
+}
+
+func (s *LocationsUsecase) DeleteLocation(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Msg("Deleting location")
+	if err := s.repo.DeleteLocation(ctx, id); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to delete location")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Msg("Location deleted")
+	return nil
+}
+
+func (s *LocationsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrLocationExists):
+		return customErr.ErrLocationExists
+	case errors.Is(err, customErr.ErrLocationInUse):
+		return customErr.ErrLocationInUse
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,15 @@
+package lots_usecase
+
+import (
+	"context"
+	"time"
+
+	"warehouse-control/internal/domain"
+)
+
+type lotsRepository interface {
+	GetLots(ctx context.Context, filter domain.LotFilter) ([]*domain.Lot, int, error)
+	GetLotByID(ctx context.Context, id int64) (*domain.Lot, error)
+	UpdateLotDates(ctx context.Context, id int64, manufacturedAt, expiresAt *time.Time, username string) error
+	GetLotHistory(ctx context.Context, lotID int64, limit, offset int) ([]*domain.LotHistoryRecord, error)
+}
@@ -0,0 +1,95 @@
+package lots_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type LotsUsecase struct {
+	repo   lotsRepository
+	logger *zlog.Zerolog
+}
+
+func NewService(repo lotsRepository, logger *zlog.Zerolog) *LotsUsecase {
+	return &LotsUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *LotsUsecase) GetLots(ctx context.Context, filter domain.LotFilter) ([]*domain.Lot, int, error) {
+	if filter.ExpiringWithinDays != nil && *filter.ExpiringWithinDays < 0 {
+		return nil, 0, fmt.Errorf("%w: days must not be negative", customErr.ErrInvalidInput)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting lots")
+	lots, total, err := s.repo.GetLots(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get lots")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(lots)).Msg("Lots retrieved")
+	return lots, total, nil
+}
+
+func (s *LotsUsecase) GetLotByID(ctx context.Context, id int64) (*domain.Lot, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	lot, err := s.repo.GetLotByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get lot")
+		return nil, s.mapError(err)
+	}
+	return lot, nil
+}
+
+func (s *LotsUsecase) UpdateLotDates(ctx context.Context, id int64, manufacturedAt, expiresAt *time.Time, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if manufacturedAt != nil && expiresAt != nil && manufacturedAt.After(*expiresAt) {
+		return fmt.Errorf("%w: manufactured_at is after expires_at", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Updating lot dates")
+	if err := s.repo.UpdateLotDates(ctx, id, manufacturedAt, expiresAt, username); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to update lot")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Lot updated")
+	return nil
+}
+
+func (s *LotsUsecase) GetLotHistory(ctx context.Context, lotID int64) ([]*domain.LotHistoryRecord, error) {
+	if lotID <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("lot_id", lotID).Msg("Getting lot history")
+	records, err := s.repo.GetLotHistory(ctx, lotID, 100, 0)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("lot_id", lotID).Msg("Failed to get lot history")
+		return nil, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(records)).Msg("Lot history retrieved")
+	return records, nil
+}
+
+func (s *LotsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrLotNotFound):
+		return customErr.ErrLotNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
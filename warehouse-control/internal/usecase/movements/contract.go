@@ -0,0 +1,23 @@
+package movements_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type movementsRepository interface {
+	CreateMovement(ctx context.Context, m *domain.StockMovement, username string) (int64, error)
+	GetMovements(ctx context.Context, filter domain.MovementFilter) ([]*domain.StockMovement, int, error)
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
+
+// unitConverter normalises quantities entered in any unit of an item into
+// its base unit.
+type unitConverter interface {
+	ToBase(ctx context.Context, itemID int64, q domain.UnitQuantity) (int, error)
+}
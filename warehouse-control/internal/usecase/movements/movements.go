@@ -0,0 +1,224 @@
+package movements_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type MovementsUsecase struct {
+	repo    movementsRepository
+	watcher stockWatcher
+	units   unitConverter
+	logger  *zlog.Zerolog
+}
+
+func NewService(repo movementsRepository, watcher stockWatcher, units unitConverter, logger *zlog.Zerolog) *MovementsUsecase {
+	return &MovementsUsecase{
+		repo:    repo,
+		watcher: watcher,
+		units:   units,
+		logger:  logger,
+	}
+}
+
+func (s *MovementsUsecase) CreateMovement(ctx context.Context, m *domain.StockMovement, username string) (int64, error) {
+	if m.Requested != nil {
+		quantity, err := s.units.ToBase(ctx, m.ItemID, *m.Requested)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("item_id", m.ItemID).Msg("Failed to convert quantity")
+			return 0, err
+		}
+		m.Quantity = quantity
+	}
+	if err := validateMovement(m); err != nil {
+		s.logger.Error().Err(err).Msg("Validation failed")
+		return 0, err
+	}
+	s.logger.Info().Int64("item_id", m.ItemID).Str("type", string(m.Type)).Str("user", username).Msg("Creating stock movement")
+	id, err := s.repo.CreateMovement(ctx, m, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", m.ItemID).Msg("Failed to create stock movement")
+		switch {
+		case errors.Is(err, customErr.ErrItemNotFound):
+			return 0, customErr.ErrItemNotFound
+		case errors.Is(err, customErr.ErrInsufficientStock):
+			return 0, customErr.ErrInsufficientStock
+		case errors.Is(err, customErr.ErrLocationNotFound):
+			return 0, customErr.ErrLocationNotFound
+		case errors.Is(err, customErr.ErrReservationNotFound):
+			return 0, customErr.ErrReservationNotFound
+		case errors.Is(err, customErr.ErrReservationClosed):
+			return 0, customErr.ErrReservationClosed
+		case errors.Is(err, customErr.ErrLotNotFound):
+			return 0, customErr.ErrLotNotFound
+		case errors.Is(err, customErr.ErrSerialNotFound), errors.Is(err, customErr.ErrSerialConflict):
+			return 0, err
+		case errors.Is(err, customErr.ErrInvalidInput):
+			return 0, err
+		case errors.Is(err, customErr.ErrDatabase):
+			return 0, customErr.ErrDatabase
+		}
+		return 0, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	s.watcher.NotifyStockChanged(m.ItemID)
+	s.logger.Info().Int64("id", id).Int64("item_id", m.ItemID).Str("user", username).Msg("Stock movement created")
+	return id, nil
+}
+
+func (s *MovementsUsecase) GetMovements(ctx context.Context, filter domain.MovementFilter) ([]*domain.StockMovement, int, error) {
+	if filter.ItemID <= 0 {
+		return nil, 0, customErr.ErrInvalidInput
+	}
+	if filter.Type != nil && !filter.Type.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown movement type %q", customErr.ErrInvalidInput, *filter.Type)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Int64("item_id", filter.ItemID).Msg("Getting stock movements")
+	movements, total, err := s.repo.GetMovements(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", filter.ItemID).Msg("Failed to get stock movements")
+		if errors.Is(err, customErr.ErrDatabase) {
+			return nil, 0, customErr.ErrDatabase
+		}
+		return nil, 0, fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+	}
+	s.logger.Info().Int("count", len(movements)).Msg("Stock movements retrieved")
+	return movements, total, nil
+}
+
+func validateMovement(m *domain.StockMovement) error {
+	if m.ItemID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if !m.Type.IsValid() {
+		return fmt.Errorf("%w: unknown movement type %q", customErr.ErrInvalidInput, m.Type)
+	}
+	if m.ReservationID != nil && m.Type != domain.MovementIssue {
+		return fmt.Errorf("%w: only issues can fulfil a reservation", customErr.ErrInvalidInput)
+	}
+	if m.StockStatus == "" {
+		m.StockStatus = domain.StockAvailable
+	}
+	if !m.StockStatus.IsValid() {
+		return fmt.Errorf("%w: unknown stock status %q", customErr.ErrInvalidInput, m.StockStatus)
+	}
+	if m.StockStatus == domain.StockQuarantine {
+		return fmt.Errorf("%w: quarantined stock moves through returns", customErr.ErrInvalidInput)
+	}
+	if err := validateLot(m); err != nil {
+		return err
+	}
+	if err := validateSerials(m); err != nil {
+		return err
+	}
+	if err := validateCost(m); err != nil {
+		return err
+	}
+	switch m.Type {
+	case domain.MovementAdjustment:
+		if m.Quantity == 0 {
+			return fmt.Errorf("%w: adjustment quantity must not be zero", customErr.ErrInvalidInput)
+		}
+		if m.ReasonCode == "" {
+			return fmt.Errorf("%w: adjustment requires a reason code", customErr.ErrInvalidInput)
+		}
+		if m.FromLocationID != nil && m.ToLocationID != nil {
+			return fmt.Errorf("%w: adjustment applies to a single bin", customErr.ErrInvalidInput)
+		}
+		// Adjustments name their bin on either side; normalise by sign.
+		if m.Quantity < 0 && m.FromLocationID == nil {
+			m.FromLocationID, m.ToLocationID = m.ToLocationID, nil
+		}
+		if m.Quantity > 0 && m.ToLocationID == nil {
+			m.ToLocationID, m.FromLocationID = m.FromLocationID, nil
+		}
+	case domain.MovementTransfer:
+		if m.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be positive", customErr.ErrInvalidInput)
+		}
+		if m.FromLocationID == nil && m.ToLocationID == nil && m.ToLocation == "" {
+			return fmt.Errorf("%w: transfer requires a destination location", customErr.ErrInvalidInput)
+		}
+		if m.FromLocationID != nil && m.ToLocationID != nil && *m.FromLocationID == *m.ToLocationID {
+			return fmt.Errorf("%w: source and destination bins are the same", customErr.ErrInvalidInput)
+		}
+	case domain.MovementReceipt:
+		if m.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be positive", customErr.ErrInvalidInput)
+		}
+		if m.FromLocationID != nil {
+			return fmt.Errorf("%w: receipt has no source bin", customErr.ErrInvalidInput)
+		}
+	case domain.MovementIssue:
+		if m.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be positive", customErr.ErrInvalidInput)
+		}
+		if m.ToLocationID != nil {
+			return fmt.Errorf("%w: issue has no destination bin", customErr.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// validateCost lets incoming stock carry its unit cost; outgoing stock is
+// costed by the item's costing method.
+func validateCost(m *domain.StockMovement) error {
+	if m.UnitCost == nil {
+		return nil
+	}
+	if m.Delta() <= 0 {
+		return fmt.Errorf("%w: only receipts and positive adjustments carry a unit cost", customErr.ErrInvalidInput)
+	}
+	if m.UnitCost.Sign() < 0 {
+		return fmt.Errorf("%w: unit cost must not be negative", customErr.ErrInvalidInput)
+	}
+	unitCost := m.UnitCost.Round(domain.CostPlaces)
+	m.UnitCost = &unitCost
+	return nil
+}
+
+func validateLot(m *domain.StockMovement) error {
+	hasLot := m.LotID != nil || m.LotNumber != ""
+	if m.Type == domain.MovementTransfer && hasLot {
+		return fmt.Errorf("%w: lots are not tracked per bin, transfers cannot name a lot", customErr.ErrInvalidInput)
+	}
+	if m.LotID != nil && m.LotNumber != "" {
+		return fmt.Errorf("%w: specify either lot_id or lot_number", customErr.ErrInvalidInput)
+	}
+	if m.Delta() < 0 && m.LotNumber != "" {
+		return fmt.Errorf("%w: outgoing movements select a lot by lot_id", customErr.ErrInvalidInput)
+	}
+	if (m.ManufacturedAt != nil || m.ExpiresAt != nil) && m.LotNumber == "" {
+		return fmt.Errorf("%w: lot dates require a lot_number", customErr.ErrInvalidInput)
+	}
+	if m.ManufacturedAt != nil && m.ExpiresAt != nil && m.ManufacturedAt.After(*m.ExpiresAt) {
+		return fmt.Errorf("%w: manufactured_at is after expires_at", customErr.ErrInvalidInput)
+	}
+	return nil
+}
+
+func validateSerials(m *domain.StockMovement) error {
+	seen := make(map[string]struct{}, len(m.Serials))
+	for i, sn := range m.Serials {
+		sn = strings.TrimSpace(sn)
+		if sn == "" {
+			return fmt.Errorf("%w: empty serial number", customErr.ErrInvalidInput)
+		}
+		if _, ok := seen[sn]; ok {
+			return fmt.Errorf("%w: duplicate serial number %s", customErr.ErrInvalidInput, sn)
+		}
+		seen[sn] = struct{}{}
+		m.Serials[i] = sn
+	}
+	return nil
+}
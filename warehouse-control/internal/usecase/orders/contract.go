@@ -0,0 +1,31 @@
+package orders_usecase
+
+import (
+	"context"
+	"time"
+
+	"warehouse-control/internal/domain"
+)
+
+type ordersRepository interface {
+	CreateOrder(ctx context.Context, o *domain.Order) (int64, error)
+	GetOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, int, error)
+	GetOrderByID(ctx context.Context, id int64) (*domain.Order, error)
+	GetOrderHistory(ctx context.Context, id int64) ([]*domain.OrderHistoryRecord, error)
+	GetPickStock(ctx context.Context, id int64) ([]*domain.ItemStock, error)
+	AllocateOrder(ctx context.Context, id int64, from []domain.OrderStatus, expiresAt time.Time, username string) error
+	ConfirmPicks(ctx context.Context, id int64, picks []*domain.OrderPick, complete bool, from []domain.OrderStatus, username string) error
+	ShipOrder(ctx context.Context, id int64, from []domain.OrderStatus, username string) ([]int64, error)
+	CancelOrder(ctx context.Context, id int64, from []domain.OrderStatus, note, username string) error
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
+
+// unitConverter normalises quantities entered in any unit of an item into
+// its base unit.
+type unitConverter interface {
+	ToBaseBySKU(ctx context.Context, sku string, q domain.UnitQuantity) (int, error)
+}
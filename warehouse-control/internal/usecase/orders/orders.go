@@ -0,0 +1,224 @@
+package orders_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type OrdersUsecase struct {
+	repo          ordersRepository
+	watcher       stockWatcher
+	units         unitConverter
+	allocationTTL time.Duration
+	logger        *zlog.Zerolog
+}
+
+func NewService(repo ordersRepository, watcher stockWatcher, units unitConverter, allocationTTL time.Duration, logger *zlog.Zerolog) *OrdersUsecase {
+	return &OrdersUsecase{
+		repo:          repo,
+		watcher:       watcher,
+		units:         units,
+		allocationTTL: allocationTTL,
+		logger:        logger,
+	}
+}
+
+func (s *OrdersUsecase) CreateOrder(ctx context.Context, o *domain.Order, username string) (int64, error) {
+	o.Number = strings.TrimSpace(o.Number)
+	o.Customer = strings.TrimSpace(o.Customer)
+	if o.Number == "" || o.Customer == "" {
+		return 0, fmt.Errorf("%w: order number and customer are required", customErr.ErrInvalidInput)
+	}
+	if len(o.Lines) == 0 {
+		return 0, fmt.Errorf("%w: an order needs at least one line", customErr.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(o.Lines))
+	for _, line := range o.Lines {
+		line.SKU = strings.TrimSpace(line.SKU)
+		if line.SKU != "" && line.Requested != nil {
+			quantity, err := s.units.ToBaseBySKU(ctx, line.SKU, *line.Requested)
+			if err != nil {
+				return 0, s.mapError(err)
+			}
+			line.Quantity = quantity
+		}
+		if line.SKU == "" || line.Quantity <= 0 {
+			return 0, fmt.Errorf("%w: each line needs a SKU and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if seen[line.SKU] {
+			return 0, fmt.Errorf("%w: SKU %s appears on more than one line", customErr.ErrInvalidInput, line.SKU)
+		}
+		seen[line.SKU] = true
+	}
+	o.CreatedBy = username
+
+	s.logger.Info().Str("number", o.Number).Str("user", username).Msg("Creating order")
+	id, err := s.repo.CreateOrder(ctx, o)
+	if err != nil {
+		s.logger.Error().Err(err).Str("number", o.Number).Msg("Failed to create order")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("number", o.Number).Msg("Order created")
+	return id, nil
+}
+
+func (s *OrdersUsecase) GetOrders(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, int, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting orders")
+	orders, total, err := s.repo.GetOrders(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get orders")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(orders)).Msg("Orders retrieved")
+	return orders, total, nil
+}
+
+func (s *OrdersUsecase) GetOrderByID(ctx context.Context, id int64) (*domain.Order, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	o, err := s.repo.GetOrderByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get order")
+		return nil, s.mapError(err)
+	}
+	return o, nil
+}
+
+func (s *OrdersUsecase) GetOrderHistory(ctx context.Context, id int64) ([]*domain.OrderHistoryRecord, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	records, err := s.repo.GetOrderHistory(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get order history")
+		return nil, s.mapError(err)
+	}
+	return records, nil
+}
+
+// GetPickList returns the outstanding quantities of an allocated order,
+// grouped by the bins to pick them from.
+func (s *OrdersUsecase) GetPickList(ctx context.Context, id int64) (*domain.Order, []*domain.PickStop, error) {
+	o, err := s.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Status != domain.OrderAllocated {
+		return nil, nil, fmt.Errorf("%w: order %s is %s, pick lists are only built for allocated orders",
+			customErr.ErrInvalidTransition, o.Number, o.Status)
+	}
+	stock, err := s.repo.GetPickStock(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get pick stock")
+		return nil, nil, s.mapError(err)
+	}
+	return o, domain.BuildPickList(o.Lines, stock), nil
+}
+
+func (s *OrdersUsecase) AllocateOrder(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Allocating order")
+	expiresAt := time.Now().Add(s.allocationTTL)
+	if err := s.repo.AllocateOrder(ctx, id, sourcesOf(domain.OrderAllocated), expiresAt, username); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to allocate order")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Order allocated")
+	return nil
+}
+
+// ConfirmPicks records picks for an allocated order. With complete set the
+// order becomes picked, even if some lines were picked short.
+func (s *OrdersUsecase) ConfirmPicks(ctx context.Context, id int64, picks []*domain.OrderPick, complete bool, username string) error {
+	if id <= 0 || (len(picks) == 0 && !complete) {
+		return customErr.ErrInvalidInput
+	}
+	for _, p := range picks {
+		if p.LineID <= 0 || p.Quantity <= 0 {
+			return fmt.Errorf("%w: each pick needs a line id and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if p.LocationID != nil && *p.LocationID <= 0 {
+			return fmt.Errorf("%w: invalid location id", customErr.ErrInvalidInput)
+		}
+		for i, sn := range p.Serials {
+			p.Serials[i] = strings.TrimSpace(sn)
+			if p.Serials[i] == "" {
+				return fmt.Errorf("%w: empty serial number", customErr.ErrInvalidInput)
+			}
+		}
+	}
+
+	s.logger.Info().Int64("id", id).Int("picks", len(picks)).Bool("complete", complete).Str("user", username).Msg("Confirming picks")
+	if err := s.repo.ConfirmPicks(ctx, id, picks, complete, sourcesOf(domain.OrderPicked), username); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to confirm picks")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Picks confirmed")
+	return nil
+}
+
+func (s *OrdersUsecase) ShipOrder(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Shipping order")
+	shipped, err := s.repo.ShipOrder(ctx, id, sourcesOf(domain.OrderShipped), username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to ship order")
+		return s.mapError(err)
+	}
+	for _, itemID := range shipped {
+		s.watcher.NotifyStockChanged(itemID)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Order shipped")
+	return nil
+}
+
+func (s *OrdersUsecase) CancelOrder(ctx context.Context, id int64, note, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Cancelling order")
+	if err := s.repo.CancelOrder(ctx, id, sourcesOf(domain.OrderCancelled), strings.TrimSpace(note), username); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to cancel order")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Order cancelled")
+	return nil
+}
+
+func (s *OrdersUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrOrderNotFound):
+		return customErr.ErrOrderNotFound
+	case errors.Is(err, customErr.ErrOrderExists):
+		return customErr.ErrOrderExists
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrInvalidTransition), errors.Is(err, customErr.ErrInsufficientStock),
+		errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrInvalidInput),
+		errors.Is(err, customErr.ErrSerialNotFound), errors.Is(err, customErr.ErrSerialConflict):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
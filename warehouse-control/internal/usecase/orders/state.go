@@ -0,0 +1,32 @@
+package orders_usecase
+
+import "warehouse-control/internal/domain"
+
+// transitions is the order lifecycle: the statuses an order in a given
+// status may move to. Shipped and cancelled orders are final.
+var transitions = map[domain.OrderStatus][]domain.OrderStatus{
+	domain.OrderNew:       {domain.OrderAllocated, domain.OrderCancelled},
+	domain.OrderAllocated: {domain.OrderPicked, domain.OrderCancelled},
+	domain.OrderPicked:    {domain.OrderShipped, domain.OrderCancelled},
+}
+
+func canTransition(from, to domain.OrderStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// sourcesOf returns the statuses from which an order may move to status. The
+// repository checks the order against them under its row lock.
+func sourcesOf(status domain.OrderStatus) []domain.OrderStatus {
+	var from []domain.OrderStatus
+	for _, s := range []domain.OrderStatus{domain.OrderNew, domain.OrderAllocated, domain.OrderPicked, domain.OrderShipped, domain.OrderCancelled} {
+		if canTransition(s, status) {
+			from = append(from, s)
+		}
+	}
+	return from
+}
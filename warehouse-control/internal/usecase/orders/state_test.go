@@ -0,0 +1,35 @@
+package orders_usecase
+
+import (
+	"testing"
+
+	"warehouse-control/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to domain.OrderStatus
+		want     bool
+	}{
+		{domain.OrderNew, domain.OrderAllocated, true},
+		{domain.OrderAllocated, domain.OrderPicked, true},
+		{domain.OrderPicked, domain.OrderShipped, true},
+		{domain.OrderNew, domain.OrderCancelled, true},
+		{domain.OrderPicked, domain.OrderCancelled, true},
+		{domain.OrderNew, domain.OrderShipped, false},
+		{domain.OrderAllocated, domain.OrderNew, false},
+		{domain.OrderShipped, domain.OrderCancelled, false},
+		{domain.OrderCancelled, domain.OrderAllocated, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, canTransition(tt.from, tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestSourcesOf(t *testing.T) {
+	assert.Equal(t, []domain.OrderStatus{domain.OrderNew}, sourcesOf(domain.OrderAllocated))
+	assert.Equal(t, []domain.OrderStatus{domain.OrderNew, domain.OrderAllocated, domain.OrderPicked}, sourcesOf(domain.OrderCancelled))
+	assert.Empty(t, sourcesOf(domain.OrderNew))
+}
@@ -0,0 +1,16 @@
+package products_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type productsRepository interface {
+	CreateProduct(ctx context.Context, p *domain.Product, username string) (int64, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, int, error)
+	GetProductsByIDs(ctx context.Context, ids []int64) ([]*domain.Product, error)
+	GetProductByID(ctx context.Context, id int64) (*domain.Product, error)
+	UpdateProduct(ctx context.Context, p *domain.Product, username string) error
+	DeleteProduct(ctx context.Context, id int64) error
+}
@@ -0,0 +1,186 @@
+package products_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ProductsUsecase struct {
+	repo     productsRepository
+	currency string
+	logger   *zlog.Zerolog
+}
+
+// NewService prices products without a currency in the given default one.
+func NewService(repo productsRepository, currency string, logger *zlog.Zerolog) *ProductsUsecase {
+	return &ProductsUsecase{
+		repo:     repo,
+		currency: currency,
+		logger:   logger,
+	}
+}
+
+// CreateProduct creates a product with the whole matrix of its variants.
+func (s *ProductsUsecase) CreateProduct(ctx context.Context, p *domain.Product, username string) (int64, error) {
+	if err := s.validateProduct(p); err != nil {
+		return 0, err
+	}
+	p.SKU = strings.TrimSpace(p.SKU)
+	if p.SKU == "" {
+		return 0, fmt.Errorf("%w: product SKU is required", customErr.ErrInvalidInput)
+	}
+	if err := validateAttributes(p); err != nil {
+		return 0, err
+	}
+	s.logger.Info().Str("sku", p.SKU).Int("variants", p.MatrixSize()).Str("user", username).Msg("Creating product")
+	id, err := s.repo.CreateProduct(ctx, p, username)
+	if err != nil {
+		s.logger.Error().Err(err).Str("sku", p.SKU).Msg("Failed to create product")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("sku", p.SKU).Msg("Product created")
+	return id, nil
+}
+
+func (s *ProductsUsecase) GetProducts(ctx context.Context, filter domain.ProductFilter) ([]*domain.Product, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	products, total, err := s.repo.GetProducts(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get products")
+		return nil, 0, s.mapError(err)
+	}
+	return products, total, nil
+}
+
+// GetProductsByIDs returns the products with the given ids, without their
+// variants.
+func (s *ProductsUsecase) GetProductsByIDs(ctx context.Context, ids []int64) ([]*domain.Product, error) {
+	products, err := s.repo.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get products")
+		return nil, s.mapError(err)
+	}
+	return products, nil
+}
+
+func (s *ProductsUsecase) GetProductByID(ctx context.Context, id int64) (*domain.Product, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	p, err := s.repo.GetProductByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get product")
+		return nil, s.mapError(err)
+	}
+	return p, nil
+}
+
+// UpdateProduct changes the shared fields of a product; variants that have
+// not overridden a field follow the change.
+func (s *ProductsUsecase) UpdateProduct(ctx context.Context, p *domain.Product, username string) error {
+	if p.ID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := s.validateProduct(p); err != nil {
+		return err
+	}
+	s.logger.Info().Int64("id", p.ID).Str("user", username).Msg("Updating product")
+	if err := s.repo.UpdateProduct(ctx, p, username); err != nil {
+		s.logger.Error().Err(err).Int64("id", p.ID).Msg("Failed to update product")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *ProductsUsecase) DeleteProduct(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Deleting product")
+	if err := s.repo.DeleteProduct(ctx, id); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to delete product")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+// validateProduct checks the fields variants inherit, defaulting the
+// currency of the price.
+func (s *ProductsUsecase) validateProduct(p *domain.Product) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Category = domain.NormalizeCategoryPath(p.Category)
+	if p.Name == "" {
+		return fmt.Errorf("%w: product name is required", customErr.ErrInvalidInput)
+	}
+	p.Price.Currency = strings.ToUpper(strings.TrimSpace(p.Price.Currency))
+	if p.Price.Currency == "" {
+		p.Price.Currency = s.currency
+	}
+	if !domain.IsCurrencyCode(p.Price.Currency) {
+		return fmt.Errorf("%w: %q is not an ISO 4217 currency code", customErr.ErrInvalidInput, p.Price.Currency)
+	}
+	if p.Price.Amount.Sign() < 0 {
+		return fmt.Errorf("%w: price must not be negative", customErr.ErrInvalidInput)
+	}
+	p.Price.Amount = p.Price.Amount.Round(domain.MoneyPlaces)
+	return nil
+}
+
+// validateAttributes requires named attributes with distinct values, and
+// that no two variants end up with the same SKU.
+func validateAttributes(p *domain.Product) error {
+	if len(p.Attributes) == 0 {
+		return fmt.Errorf("%w: a product needs at least one variant attribute", customErr.ErrInvalidInput)
+	}
+	names := make(map[string]bool, len(p.Attributes))
+	for i := range p.Attributes {
+		a := &p.Attributes[i]
+		a.Name = strings.ToLower(strings.TrimSpace(a.Name))
+		if a.Name == "" || len(a.Values) == 0 {
+			return fmt.Errorf("%w: each attribute needs a name and at least one value", customErr.ErrInvalidInput)
+		}
+		if names[a.Name] {
+			return fmt.Errorf("%w: attribute %s is listed twice", customErr.ErrInvalidInput, a.Name)
+		}
+		names[a.Name] = true
+		values := make(map[string]bool, len(a.Values))
+		for j, v := range a.Values {
+			v = strings.TrimSpace(v)
+			key := strings.ToUpper(strings.Join(strings.Fields(v), ""))
+			if key == "" {
+				return fmt.Errorf("%w: attribute %s has an empty value", customErr.ErrInvalidInput, a.Name)
+			}
+			if values[key] {
+				return fmt.Errorf("%w: attribute %s lists %q twice", customErr.ErrInvalidInput, a.Name, v)
+			}
+			values[key] = true
+			a.Values[j] = v
+		}
+	}
+	if size := p.MatrixSize(); size > domain.MaxVariants {
+		return fmt.Errorf("%w: %d variants exceed the limit of %d", customErr.ErrInvalidInput, size, domain.MaxVariants)
+	}
+	return nil
+}
+
+func (s *ProductsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrProductNotFound):
+		return customErr.ErrProductNotFound
+	case errors.Is(err, customErr.ErrProductExists), errors.Is(err, customErr.ErrInvalidInput):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,27 @@
+package purchase_orders_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type purchaseOrdersRepository interface {
+	CreatePurchaseOrder(ctx context.Context, po *domain.PurchaseOrder) (int64, error)
+	GetPurchaseOrders(ctx context.Context, filter domain.PurchaseOrderFilter) ([]*domain.PurchaseOrder, int, error)
+	GetPurchaseOrderByID(ctx context.Context, id int64) (*domain.PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, id int64) error
+	ReceivePurchaseOrder(ctx context.Context, id int64, receipts []*domain.PurchaseOrderReceipt, allowOver bool, username string) ([]int64, error)
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
+
+// unitConverter normalises quantities entered in any unit of an item into
+// its base unit.
+type unitConverter interface {
+	ToBase(ctx context.Context, itemID int64, q domain.UnitQuantity) (int, error)
+	ToBaseBySKU(ctx context.Context, sku string, q domain.UnitQuantity) (int, error)
+}
@@ -0,0 +1,227 @@
+package purchase_orders_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type PurchaseOrdersUsecase struct {
+	repo    purchaseOrdersRepository
+	watcher stockWatcher
+	units   unitConverter
+	logger  *zlog.Zerolog
+}
+
+func NewService(repo purchaseOrdersRepository, watcher stockWatcher, units unitConverter, logger *zlog.Zerolog) *PurchaseOrdersUsecase {
+	return &PurchaseOrdersUsecase{
+		repo:    repo,
+		watcher: watcher,
+		units:   units,
+		logger:  logger,
+	}
+}
+
+func (s *PurchaseOrdersUsecase) CreatePurchaseOrder(ctx context.Context, po *domain.PurchaseOrder, username string) (int64, error) {
+	po.Number = strings.TrimSpace(po.Number)
+	if po.Number == "" {
+		return 0, fmt.Errorf("%w: purchase order number is required", customErr.ErrInvalidInput)
+	}
+	if po.SupplierID <= 0 {
+		return 0, fmt.Errorf("%w: invalid supplier id", customErr.ErrInvalidInput)
+	}
+	if len(po.Lines) == 0 {
+		return 0, fmt.Errorf("%w: a purchase order needs at least one line", customErr.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(po.Lines))
+	for _, line := range po.Lines {
+		line.SKU = strings.TrimSpace(line.SKU)
+		if line.SKU != "" && line.Requested != nil {
+			quantity, err := s.units.ToBaseBySKU(ctx, line.SKU, *line.Requested)
+			if err != nil {
+				return 0, s.mapError(err)
+			}
+			line.Quantity = quantity
+		}
+		if line.SKU == "" || line.Quantity <= 0 || line.UnitPrice.Sign() < 0 {
+			return 0, fmt.Errorf("%w: each line needs a SKU, a positive quantity and a non-negative price", customErr.ErrInvalidInput)
+		}
+		line.UnitPrice = line.UnitPrice.Round(domain.CostPlaces)
+		if seen[line.SKU] {
+			return 0, fmt.Errorf("%w: SKU %s appears on more than one line", customErr.ErrInvalidInput, line.SKU)
+		}
+		seen[line.SKU] = true
+	}
+	po.CreatedBy = username
+
+	s.logger.Info().Str("number", po.Number).Str("user", username).Msg("Creating purchase order")
+	id, err := s.repo.CreatePurchaseOrder(ctx, po)
+	if err != nil {
+		s.logger.Error().Err(err).Str("number", po.Number).Msg("Failed to create purchase order")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("number", po.Number).Msg("Purchase order created")
+	return id, nil
+}
+
+func (s *PurchaseOrdersUsecase) GetPurchaseOrders(ctx context.Context, filter domain.PurchaseOrderFilter) ([]*domain.PurchaseOrder, int, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting purchase orders")
+	orders, total, err := s.repo.GetPurchaseOrders(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get purchase orders")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(orders)).Msg("Purchase orders retrieved")
+	return orders, total, nil
+}
+
+func (s *PurchaseOrdersUsecase) GetPurchaseOrderByID(ctx context.Context, id int64) (*domain.PurchaseOrder, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	po, err := s.repo.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get purchase order")
+		return nil, s.mapError(err)
+	}
+	return po, nil
+}
+
+func (s *PurchaseOrdersUsecase) CancelPurchaseOrder(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Cancelling purchase order")
+	if err := s.repo.CancelPurchaseOrder(ctx, id); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to cancel purchase order")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Purchase order cancelled")
+	return nil
+}
+
+// ReceivePurchaseOrder books receipts against the order's lines. allowOver
+// lets a line be received beyond its ordered quantity; callers must restrict
+// it to admins.
+func (s *PurchaseOrdersUsecase) ReceivePurchaseOrder(ctx context.Context, id int64, receipts []*domain.PurchaseOrderReceipt, allowOver bool, username string) error {
+	if id <= 0 || len(receipts) == 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := s.normalizeReceipts(ctx, id, receipts); err != nil {
+		return err
+	}
+	seen := make(map[int64]bool, len(receipts))
+	for _, rc := range receipts {
+		if rc.LineID <= 0 || rc.Quantity <= 0 {
+			return fmt.Errorf("%w: each receipt needs a line id and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if rc.LocationID != nil && *rc.LocationID <= 0 {
+			return fmt.Errorf("%w: invalid location id", customErr.ErrInvalidInput)
+		}
+		if seen[rc.LineID] {
+			return fmt.Errorf("%w: line %d is received more than once", customErr.ErrInvalidInput, rc.LineID)
+		}
+		seen[rc.LineID] = true
+		if err := validateReceipt(rc); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info().Int64("id", id).Int("lines", len(receipts)).Bool("allow_over", allowOver).Str("user", username).Msg("Receiving purchase order")
+	received, err := s.repo.ReceivePurchaseOrder(ctx, id, receipts, allowOver, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to receive purchase order")
+		return s.mapError(err)
+	}
+	for _, itemID := range received {
+		s.watcher.NotifyStockChanged(itemID)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Purchase order received")
+	return nil
+}
+
+// normalizeReceipts converts the received quantities into the base units of
+// the lines' items. The order is only loaded when a receipt names a unit.
+func (s *PurchaseOrdersUsecase) normalizeReceipts(ctx context.Context, id int64, receipts []*domain.PurchaseOrderReceipt) error {
+	var items map[int64]int64
+	for _, rc := range receipts {
+		if rc.Requested == nil {
+			continue
+		}
+		if rc.Requested.Unit != "" && items == nil {
+			po, err := s.repo.GetPurchaseOrderByID(ctx, id)
+			if err != nil {
+				return s.mapError(err)
+			}
+			items = make(map[int64]int64, len(po.Lines))
+			for _, line := range po.Lines {
+				items[line.ID] = line.ItemID
+			}
+		}
+		itemID := items[rc.LineID]
+		if rc.Requested.Unit != "" && itemID == 0 {
+			return fmt.Errorf("%w: line %d is not on the purchase order", customErr.ErrInvalidInput, rc.LineID)
+		}
+		quantity, err := s.units.ToBase(ctx, itemID, *rc.Requested)
+		if err != nil {
+			return s.mapError(err)
+		}
+		rc.Quantity = quantity
+	}
+	return nil
+}
+
+// validateReceipt checks the lot and stock status of a receipt the way a
+// receipt movement is checked.
+func validateReceipt(rc *domain.PurchaseOrderReceipt) error {
+	rc.LotNumber = strings.TrimSpace(rc.LotNumber)
+	if (rc.ManufacturedAt != nil || rc.ExpiresAt != nil) && rc.LotNumber == "" {
+		return fmt.Errorf("%w: lot dates require a lot_number", customErr.ErrInvalidInput)
+	}
+	if rc.ManufacturedAt != nil && rc.ExpiresAt != nil && rc.ManufacturedAt.After(*rc.ExpiresAt) {
+		return fmt.Errorf("%w: manufactured_at is after expires_at", customErr.ErrInvalidInput)
+	}
+	if rc.StockStatus == "" {
+		rc.StockStatus = domain.StockAvailable
+	}
+	if !rc.StockStatus.IsValid() {
+		return fmt.Errorf("%w: unknown stock status %q", customErr.ErrInvalidInput, rc.StockStatus)
+	}
+	if rc.StockStatus == domain.StockQuarantine {
+		return fmt.Errorf("%w: quarantined stock moves through returns", customErr.ErrInvalidInput)
+	}
+	return nil
+}
+
+func (s *PurchaseOrdersUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrPurchaseOrderNotFound):
+		return customErr.ErrPurchaseOrderNotFound
+	case errors.Is(err, customErr.ErrPurchaseOrderExists):
+		return customErr.ErrPurchaseOrderExists
+	case errors.Is(err, customErr.ErrPurchaseOrderClosed):
+		return customErr.ErrPurchaseOrderClosed
+	case errors.Is(err, customErr.ErrSupplierNotFound):
+		return customErr.ErrSupplierNotFound
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrItemNotFound), errors.Is(err, customErr.ErrOverReceipt), errors.Is(err, customErr.ErrInvalidInput):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,19 @@
+package reservations_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type reservationsRepository interface {
+	CreateReservation(ctx context.Context, res *domain.Reservation) (int64, error)
+	GetReservations(ctx context.Context, filter domain.ReservationFilter) ([]*domain.Reservation, int, error)
+	ReleaseReservation(ctx context.Context, itemID, id int64) error
+	ReleaseExpired(ctx context.Context) (int64, error)
+}
+
+// stockWatcher is told about every change to the stock available of an item.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
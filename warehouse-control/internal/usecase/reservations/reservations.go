@@ -0,0 +1,128 @@
+package reservations_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ReservationsUsecase struct {
+	repo       reservationsRepository
+	watcher    stockWatcher
+	defaultTTL time.Duration
+	logger     *zlog.Zerolog
+}
+
+func NewService(repo reservationsRepository, watcher stockWatcher, defaultTTL time.Duration, logger *zlog.Zerolog) *ReservationsUsecase {
+	return &ReservationsUsecase{
+		repo:       repo,
+		watcher:    watcher,
+		defaultTTL: defaultTTL,
+		logger:     logger,
+	}
+}
+
+func (s *ReservationsUsecase) CreateReservation(ctx context.Context, res *domain.Reservation, username string) (int64, error) {
+	if res.ItemID <= 0 || res.Quantity <= 0 {
+		return 0, fmt.Errorf("%w: quantity must be positive", customErr.ErrInvalidInput)
+	}
+	now := time.Now()
+	if res.ExpiresAt.IsZero() {
+		res.ExpiresAt = now.Add(s.defaultTTL)
+	}
+	if !res.ExpiresAt.After(now) {
+		return 0, fmt.Errorf("%w: expires_at must be in the future", customErr.ErrInvalidInput)
+	}
+	res.CreatedBy = username
+
+	s.logger.Info().Int64("item_id", res.ItemID).Int("quantity", res.Quantity).Str("user", username).Msg("Creating reservation")
+	id, err := s.repo.CreateReservation(ctx, res)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", res.ItemID).Msg("Failed to create reservation")
+		return 0, s.mapError(err)
+	}
+	s.watcher.NotifyStockChanged(res.ItemID)
+	s.logger.Info().Int64("id", id).Int64("item_id", res.ItemID).Str("user", username).Msg("Reservation created")
+	return id, nil
+}
+
+func (s *ReservationsUsecase) GetReservations(ctx context.Context, filter domain.ReservationFilter) ([]*domain.Reservation, int, error) {
+	if filter.ItemID <= 0 {
+		return nil, 0, customErr.ErrInvalidInput
+	}
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown reservation status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Int64("item_id", filter.ItemID).Msg("Getting reservations")
+	reservations, total, err := s.repo.GetReservations(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", filter.ItemID).Msg("Failed to get reservations")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(reservations)).Msg("Reservations retrieved")
+	return reservations, total, nil
+}
+
+func (s *ReservationsUsecase) ReleaseReservation(ctx context.Context, itemID, id int64, username string) error {
+	if itemID <= 0 || id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Releasing reservation")
+	if err := s.repo.ReleaseReservation(ctx, itemID, id); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to release reservation")
+		return s.mapError(err)
+	}
+	s.watcher.NotifyStockChanged(itemID)
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Reservation released")
+	return nil
+}
+
+// RunSweeper expires overdue reservations every interval until ctx is done.
+func (s *ReservationsUsecase) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.ReleaseExpired(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Error().Err(err).Msg("Failed to release expired reservations")
+				}
+				continue
+			}
+			if n > 0 {
+				s.logger.Info().Int64("count", n).Msg("Expired reservations released")
+			}
+		}
+	}
+}
+
+func (s *ReservationsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrReservationNotFound):
+		return customErr.ErrReservationNotFound
+	case errors.Is(err, customErr.ErrReservationClosed):
+		return customErr.ErrReservationClosed
+	case errors.Is(err, customErr.ErrInsufficientStock):
+		return customErr.ErrInsufficientStock
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
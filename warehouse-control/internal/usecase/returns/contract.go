@@ -0,0 +1,21 @@
+package returns_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type returnsRepository interface {
+	CreateReturn(ctx context.Context, ret *domain.Return) (int64, error)
+	GetReturns(ctx context.Context, filter domain.ReturnFilter) ([]*domain.Return, int, error)
+	GetReturnByID(ctx context.Context, id int64) (*domain.Return, error)
+	ReceiveReturn(ctx context.Context, id int64, receipts []*domain.ReturnReceipt, username string) ([]int64, error)
+	DispositionReturn(ctx context.Context, id int64, dispositions []*domain.ReturnDisposition, username string) ([]int64, error)
+	CancelReturn(ctx context.Context, id int64) error
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
@@ -0,0 +1,214 @@
+package returns_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ReturnsUsecase struct {
+	repo    returnsRepository
+	watcher stockWatcher
+	logger  *zlog.Zerolog
+}
+
+func NewService(repo returnsRepository, watcher stockWatcher, logger *zlog.Zerolog) *ReturnsUsecase {
+	return &ReturnsUsecase{
+		repo:    repo,
+		watcher: watcher,
+		logger:  logger,
+	}
+}
+
+// CreateReturn authorises a return. With an order the customer defaults to
+// the order's customer; without one it is required.
+func (s *ReturnsUsecase) CreateReturn(ctx context.Context, ret *domain.Return, username string) (int64, error) {
+	ret.Number = strings.TrimSpace(ret.Number)
+	ret.Customer = strings.TrimSpace(ret.Customer)
+	ret.Reason = strings.TrimSpace(ret.Reason)
+	if ret.Number == "" {
+		return 0, fmt.Errorf("%w: return number is required", customErr.ErrInvalidInput)
+	}
+	if ret.OrderID != nil && *ret.OrderID <= 0 {
+		return 0, fmt.Errorf("%w: invalid order id", customErr.ErrInvalidInput)
+	}
+	if ret.OrderID == nil && ret.Customer == "" {
+		return 0, fmt.Errorf("%w: customer is required for a return without an order", customErr.ErrInvalidInput)
+	}
+	if len(ret.Lines) == 0 {
+		return 0, fmt.Errorf("%w: a return needs at least one line", customErr.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(ret.Lines))
+	for _, line := range ret.Lines {
+		line.SKU = strings.TrimSpace(line.SKU)
+		if line.SKU == "" || line.Quantity <= 0 {
+			return 0, fmt.Errorf("%w: each line needs a SKU and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if seen[line.SKU] {
+			return 0, fmt.Errorf("%w: SKU %s appears on more than one line", customErr.ErrInvalidInput, line.SKU)
+		}
+		seen[line.SKU] = true
+	}
+	ret.CreatedBy = username
+
+	s.logger.Info().Str("number", ret.Number).Str("user", username).Msg("Creating return")
+	id, err := s.repo.CreateReturn(ctx, ret)
+	if err != nil {
+		s.logger.Error().Err(err).Str("number", ret.Number).Msg("Failed to create return")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("number", ret.Number).Msg("Return created")
+	return id, nil
+}
+
+func (s *ReturnsUsecase) GetReturns(ctx context.Context, filter domain.ReturnFilter) ([]*domain.Return, int, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting returns")
+	returns, total, err := s.repo.GetReturns(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get returns")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(returns)).Msg("Returns retrieved")
+	return returns, total, nil
+}
+
+func (s *ReturnsUsecase) GetReturnByID(ctx context.Context, id int64) (*domain.Return, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	ret, err := s.repo.GetReturnByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get return")
+		return nil, s.mapError(err)
+	}
+	return ret, nil
+}
+
+// ReceiveReturn books the returned goods into quarantine.
+func (s *ReturnsUsecase) ReceiveReturn(ctx context.Context, id int64, receipts []*domain.ReturnReceipt, username string) error {
+	if id <= 0 || len(receipts) == 0 {
+		return customErr.ErrInvalidInput
+	}
+	seen := make(map[int64]bool, len(receipts))
+	for _, rc := range receipts {
+		if rc.LineID <= 0 || rc.Quantity <= 0 {
+			return fmt.Errorf("%w: each receipt needs a line id and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if seen[rc.LineID] {
+			return fmt.Errorf("%w: line %d is received more than once", customErr.ErrInvalidInput, rc.LineID)
+		}
+		seen[rc.LineID] = true
+		if rc.LocationID != nil && *rc.LocationID <= 0 {
+			return fmt.Errorf("%w: invalid location id", customErr.ErrInvalidInput)
+		}
+		if err := trimSerials(rc.Serials); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info().Int64("id", id).Int("receipts", len(receipts)).Str("user", username).Msg("Receiving return")
+	received, err := s.repo.ReceiveReturn(ctx, id, receipts, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to receive return")
+		return s.mapError(err)
+	}
+	for _, itemID := range received {
+		s.watcher.NotifyStockChanged(itemID)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Return received")
+	return nil
+}
+
+// DispositionReturn decides what happens to quarantined goods: restock,
+// scrap or return to the vendor.
+func (s *ReturnsUsecase) DispositionReturn(ctx context.Context, id int64, dispositions []*domain.ReturnDisposition, username string) error {
+	if id <= 0 || len(dispositions) == 0 {
+		return customErr.ErrInvalidInput
+	}
+	for _, d := range dispositions {
+		if d.LineID <= 0 || d.Quantity <= 0 {
+			return fmt.Errorf("%w: each disposition needs a line id and a positive quantity", customErr.ErrInvalidInput)
+		}
+		if !d.Disposition.IsValid() {
+			return fmt.Errorf("%w: unknown disposition %q", customErr.ErrInvalidInput, d.Disposition)
+		}
+		if d.SupplierID != nil && (*d.SupplierID <= 0 || d.Disposition != domain.DispositionReturnToVendor) {
+			return fmt.Errorf("%w: supplier_id is only set when returning to a vendor", customErr.ErrInvalidInput)
+		}
+		if err := trimSerials(d.Serials); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info().Int64("id", id).Int("dispositions", len(dispositions)).Str("user", username).Msg("Dispositioning return")
+	affected, err := s.repo.DispositionReturn(ctx, id, dispositions, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to disposition return")
+		return s.mapError(err)
+	}
+	for _, itemID := range affected {
+		s.watcher.NotifyStockChanged(itemID)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Return dispositioned")
+	return nil
+}
+
+func (s *ReturnsUsecase) CancelReturn(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Cancelling return")
+	if err := s.repo.CancelReturn(ctx, id); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to cancel return")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Return cancelled")
+	return nil
+}
+
+func trimSerials(serials []string) error {
+	for i, sn := range serials {
+		serials[i] = strings.TrimSpace(sn)
+		if serials[i] == "" {
+			return fmt.Errorf("%w: empty serial number", customErr.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+func (s *ReturnsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrReturnNotFound):
+		return customErr.ErrReturnNotFound
+	case errors.Is(err, customErr.ErrReturnExists):
+		return customErr.ErrReturnExists
+	case errors.Is(err, customErr.ErrReturnClosed):
+		return customErr.ErrReturnClosed
+	case errors.Is(err, customErr.ErrOrderNotFound):
+		return customErr.ErrOrderNotFound
+	case errors.Is(err, customErr.ErrSupplierNotFound):
+		return customErr.ErrSupplierNotFound
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrInsufficientStock), errors.Is(err, customErr.ErrItemNotFound),
+		errors.Is(err, customErr.ErrInvalidInput), errors.Is(err, customErr.ErrSerialNotFound),
+		errors.Is(err, customErr.ErrSerialConflict):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,16 @@
+package serials_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type serialsRepository interface {
+	GetSerialByNumber(ctx context.Context, serialNumber string) (*domain.Serial, error)
+	GetSerialMovements(ctx context.Context, serialID int64) ([]*domain.StockMovement, error)
+}
+
+type itemsRepository interface {
+	GetItemByID(ctx context.Context, id int64) (*domain.Item, error)
+}
@@ -0,0 +1,71 @@
+package serials_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type SerialsUsecase struct {
+	repo      serialsRepository
+	itemsRepo itemsRepository
+	logger    *zlog.Zerolog
+}
+
+func NewService(repo serialsRepository, itemsRepo itemsRepository, logger *zlog.Zerolog) *SerialsUsecase {
+	return &SerialsUsecase{
+		repo:      repo,
+		itemsRepo: itemsRepo,
+		logger:    logger,
+	}
+}
+
+func (s *SerialsUsecase) GetSerialTrail(ctx context.Context, serialNumber string) (*domain.SerialTrail, error) {
+	serialNumber = strings.TrimSpace(serialNumber)
+	if serialNumber == "" {
+		return nil, customErr.ErrInvalidInput
+	}
+	s.logger.Info().Str("serial", serialNumber).Msg("Getting serial trail")
+
+	serial, err := s.repo.GetSerialByNumber(ctx, serialNumber)
+	if err != nil {
+		s.logger.Error().Err(err).Str("serial", serialNumber).Msg("Failed to get serial")
+		return nil, s.mapError(err)
+	}
+	item, err := s.itemsRepo.GetItemByID(ctx, serial.ItemID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", serial.ItemID).Msg("Failed to get serial item")
+		return nil, s.mapError(err)
+	}
+	movements, err := s.repo.GetSerialMovements(ctx, serial.ID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("serial", serialNumber).Msg("Failed to get serial movements")
+		return nil, s.mapError(err)
+	}
+
+	s.logger.Info().Str("serial", serialNumber).Int("movements", len(movements)).Msg("Serial trail retrieved")
+	return &domain.SerialTrail{
+		Serial:    serial,
+		Item:      item,
+		Movements: movements,
+	}, nil
+}
+
+func (s *SerialsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrSerialNotFound):
+		return customErr.ErrSerialNotFound
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,21 @@
+package stocktakes_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type stocktakesRepository interface {
+	CreateStocktake(ctx context.Context, st *domain.Stocktake) (int64, error)
+	GetStocktakes(ctx context.Context, filter domain.StocktakeFilter) ([]*domain.Stocktake, int, error)
+	GetStocktakeByID(ctx context.Context, id int64) (*domain.Stocktake, error)
+	SubmitCounts(ctx context.Context, id int64, counts []*domain.StocktakeCount, username string) error
+	SetStocktakeStatus(ctx context.Context, id int64, from []domain.StocktakeStatus, status domain.StocktakeStatus) error
+	PostStocktake(ctx context.Context, id int64, username string) ([]int64, error)
+}
+
+// stockWatcher is told about every change to an item's on-hand quantity.
+type stockWatcher interface {
+	NotifyStockChanged(itemID int64)
+}
@@ -0,0 +1,192 @@
+package stocktakes_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type StocktakesUsecase struct {
+	repo           stocktakesRepository
+	watcher        stockWatcher
+	blindByDefault bool
+	logger         *zlog.Zerolog
+}
+
+func NewService(repo stocktakesRepository, watcher stockWatcher, blindByDefault bool, logger *zlog.Zerolog) *StocktakesUsecase {
+	return &StocktakesUsecase{
+		repo:           repo,
+		watcher:        watcher,
+		blindByDefault: blindByDefault,
+		logger:         logger,
+	}
+}
+
+// CreateStocktake opens a count session. When blind is nil the configured
+// default applies.
+func (s *StocktakesUsecase) CreateStocktake(ctx context.Context, st *domain.Stocktake, blind *bool, username string) (int64, error) {
+	if len(st.LocationIDs) == 0 && len(st.Categories) == 0 {
+		return 0, fmt.Errorf("%w: a stocktake needs locations or categories", customErr.ErrInvalidInput)
+	}
+	for _, id := range st.LocationIDs {
+		if id <= 0 {
+			return 0, fmt.Errorf("%w: invalid location id %d", customErr.ErrInvalidInput, id)
+		}
+	}
+	for i, category := range st.Categories {
+		st.Categories[i] = strings.TrimSpace(category)
+		if st.Categories[i] == "" {
+			return 0, fmt.Errorf("%w: empty category", customErr.ErrInvalidInput)
+		}
+	}
+	st.Blind = s.blindByDefault
+	if blind != nil {
+		st.Blind = *blind
+	}
+	st.CreatedBy = username
+
+	s.logger.Info().Str("user", username).Bool("blind", st.Blind).Msg("Creating stocktake")
+	id, err := s.repo.CreateStocktake(ctx, st)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to create stocktake")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Stocktake created")
+	return id, nil
+}
+
+func (s *StocktakesUsecase) GetStocktakes(ctx context.Context, filter domain.StocktakeFilter) ([]*domain.Stocktake, int, error) {
+	if filter.Status != nil && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: unknown stocktake status %q", customErr.ErrInvalidInput, *filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting stocktakes")
+	stocktakes, total, err := s.repo.GetStocktakes(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get stocktakes")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(stocktakes)).Msg("Stocktakes retrieved")
+	return stocktakes, total, nil
+}
+
+func (s *StocktakesUsecase) GetStocktakeByID(ctx context.Context, id int64) (*domain.Stocktake, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	st, err := s.repo.GetStocktakeByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get stocktake")
+		return nil, s.mapError(err)
+	}
+	return st, nil
+}
+
+// GetVariances returns the counted lines whose count differs from the system
+// quantity. Blind sessions only reveal them once counting has been closed.
+func (s *StocktakesUsecase) GetVariances(ctx context.Context, id int64) (*domain.Stocktake, error) {
+	st, err := s.GetStocktakeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.VariancesHidden() {
+		return nil, fmt.Errorf("%w: variances of a blind stocktake are shown after counting is closed", customErr.ErrForbidden)
+	}
+	lines := make([]*domain.StocktakeLine, 0, len(st.Lines))
+	for _, line := range st.Lines {
+		if variance, ok := line.Variance(); ok && variance != 0 {
+			lines = append(lines, line)
+		}
+	}
+	st.Lines = lines
+	return st, nil
+}
+
+func (s *StocktakesUsecase) SubmitCounts(ctx context.Context, id int64, counts []*domain.StocktakeCount, username string) error {
+	if id <= 0 || len(counts) == 0 {
+		return customErr.ErrInvalidInput
+	}
+	for _, cnt := range counts {
+		if cnt.ItemID <= 0 || cnt.Quantity < 0 {
+			return fmt.Errorf("%w: counts need an item and a non-negative quantity", customErr.ErrInvalidInput)
+		}
+	}
+	s.logger.Info().Int64("id", id).Int("count", len(counts)).Str("user", username).Msg("Submitting stocktake counts")
+	if err := s.repo.SubmitCounts(ctx, id, counts, username); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to submit stocktake counts")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Stocktake counts submitted")
+	return nil
+}
+
+// CloseCounting moves the session to review: no more counts are accepted
+// and variances become visible.
+func (s *StocktakesUsecase) CloseCounting(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Closing stocktake counting")
+	err := s.repo.SetStocktakeStatus(ctx, id, []domain.StocktakeStatus{domain.StocktakeCounting}, domain.StocktakeReview)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to close stocktake counting")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *StocktakesUsecase) CancelStocktake(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Cancelling stocktake")
+	err := s.repo.SetStocktakeStatus(ctx, id,
+		[]domain.StocktakeStatus{domain.StocktakeCounting, domain.StocktakeReview}, domain.StocktakeCancelled)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to cancel stocktake")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *StocktakesUsecase) PostStocktake(ctx context.Context, id int64, username string) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	s.logger.Info().Int64("id", id).Str("user", username).Msg("Posting stocktake")
+	adjusted, err := s.repo.PostStocktake(ctx, id, username)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to post stocktake")
+		return s.mapError(err)
+	}
+	for _, itemID := range adjusted {
+		s.watcher.NotifyStockChanged(itemID)
+	}
+	s.logger.Info().Int64("id", id).Int("adjusted", len(adjusted)).Str("user", username).Msg("Stocktake posted")
+	return nil
+}
+
+func (s *StocktakesUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrStocktakeNotFound):
+		return customErr.ErrStocktakeNotFound
+	case errors.Is(err, customErr.ErrStocktakeClosed):
+		return customErr.ErrStocktakeClosed
+	case errors.Is(err, customErr.ErrLocationNotFound):
+		return customErr.ErrLocationNotFound
+	case errors.Is(err, customErr.ErrInvalidInput), errors.Is(err, customErr.ErrInsufficientStock):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,14 @@
+package suppliers_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type suppliersRepository interface {
+	CreateSupplier(ctx context.Context, s *domain.Supplier) (int64, error)
+	GetSuppliers(ctx context.Context, filter domain.SupplierFilter) ([]*domain.Supplier, int, error)
+	GetSupplierByID(ctx context.Context, id int64) (*domain.Supplier, error)
+	UpdateSupplier(ctx context.Context, id int64, s *domain.Supplier) error
+}
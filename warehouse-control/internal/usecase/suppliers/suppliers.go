@@ -0,0 +1,97 @@
+package suppliers_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type SuppliersUsecase struct {
+	repo   suppliersRepository
+	logger *zlog.Zerolog
+}
+
+func NewService(repo suppliersRepository, logger *zlog.Zerolog) *SuppliersUsecase {
+	return &SuppliersUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *SuppliersUsecase) CreateSupplier(ctx context.Context, supplier *domain.Supplier) (int64, error) {
+	supplier.Code = strings.TrimSpace(supplier.Code)
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	if supplier.Code == "" || supplier.Name == "" {
+		return 0, fmt.Errorf("%w: supplier code and name are required", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Str("code", supplier.Code).Msg("Creating supplier")
+	id, err := s.repo.CreateSupplier(ctx, supplier)
+	if err != nil {
+		s.logger.Error().Err(err).Str("code", supplier.Code).Msg("Failed to create supplier")
+		return 0, s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Str("code", supplier.Code).Msg("Supplier created")
+	return id, nil
+}
+
+func (s *SuppliersUsecase) GetSuppliers(ctx context.Context, filter domain.SupplierFilter) ([]*domain.Supplier, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	s.logger.Info().Msg("Getting suppliers")
+	suppliers, total, err := s.repo.GetSuppliers(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get suppliers")
+		return nil, 0, s.mapError(err)
+	}
+	s.logger.Info().Int("count", len(suppliers)).Msg("Suppliers retrieved")
+	return suppliers, total, nil
+}
+
+func (s *SuppliersUsecase) GetSupplierByID(ctx context.Context, id int64) (*domain.Supplier, error) {
+	if id <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	supplier, err := s.repo.GetSupplierByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to get supplier")
+		return nil, s.mapError(err)
+	}
+	return supplier, nil
+}
+
+func (s *SuppliersUsecase) UpdateSupplier(ctx context.Context, id int64, supplier *domain.Supplier) error {
+	if id <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	if supplier.Name == "" {
+		return fmt.Errorf("%w: supplier name is required", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Int64("id", id).Msg("Updating supplier")
+	if err := s.repo.UpdateSupplier(ctx, id, supplier); err != nil {
+		s.logger.Error().Err(err).Int64("id", id).Msg("Failed to update supplier")
+		return s.mapError(err)
+	}
+	s.logger.Info().Int64("id", id).Msg("Supplier updated")
+	return nil
+}
+
+func (s *SuppliersUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrSupplierNotFound):
+		return customErr.ErrSupplierNotFound
+	case errors.Is(err, customErr.ErrSupplierExists):
+		return customErr.ErrSupplierExists
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,17 @@
+package units_usecase
+
+import (
+	"context"
+
+	"warehouse-control/internal/domain"
+)
+
+type unitsRepository interface {
+	CreateUnit(ctx context.Context, u *domain.Unit) error
+	GetUnits(ctx context.Context) ([]*domain.Unit, error)
+	GetItemUnits(ctx context.Context, itemID int64) (*domain.ItemUnits, error)
+	GetItemUnit(ctx context.Context, itemID int64, unit string) (*domain.ItemUnit, error)
+	GetItemUnitBySKU(ctx context.Context, sku, unit string) (*domain.ItemUnit, error)
+	SetItemUnit(ctx context.Context, c *domain.ItemUnit) error
+	DeleteItemUnit(ctx context.Context, itemID int64, unit string) error
+}
@@ -0,0 +1,144 @@
+package units_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type UnitsUsecase struct {
+	repo   unitsRepository
+	logger *zlog.Zerolog
+}
+
+func NewService(repo unitsRepository, logger *zlog.Zerolog) *UnitsUsecase {
+	return &UnitsUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *UnitsUsecase) CreateUnit(ctx context.Context, u *domain.Unit) error {
+	u.Code = strings.ToLower(strings.TrimSpace(u.Code))
+	u.Name = strings.TrimSpace(u.Name)
+	if u.Code == "" || u.Name == "" {
+		return fmt.Errorf("%w: unit code and name are required", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Str("code", u.Code).Msg("Creating unit")
+	if err := s.repo.CreateUnit(ctx, u); err != nil {
+		s.logger.Error().Err(err).Str("code", u.Code).Msg("Failed to create unit")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *UnitsUsecase) GetUnits(ctx context.Context) ([]*domain.Unit, error) {
+	units, err := s.repo.GetUnits(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get units")
+		return nil, s.mapError(err)
+	}
+	return units, nil
+}
+
+func (s *UnitsUsecase) GetItemUnits(ctx context.Context, itemID int64) (*domain.ItemUnits, error) {
+	if itemID <= 0 {
+		return nil, customErr.ErrInvalidInput
+	}
+	units, err := s.repo.GetItemUnits(ctx, itemID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Msg("Failed to get item units")
+		return nil, s.mapError(err)
+	}
+	return units, nil
+}
+
+// SetItemUnit configures how many base units of the item one unit holds.
+func (s *UnitsUsecase) SetItemUnit(ctx context.Context, c *domain.ItemUnit) error {
+	c.Unit = strings.ToLower(strings.TrimSpace(c.Unit))
+	if c.ItemID <= 0 || c.Unit == "" {
+		return customErr.ErrInvalidInput
+	}
+	c.Factor = c.Factor.Round(domain.FactorPlaces)
+	if c.Factor.Sign() <= 0 {
+		return fmt.Errorf("%w: conversion factor must be positive", customErr.ErrInvalidInput)
+	}
+	s.logger.Info().Int64("item_id", c.ItemID).Str("unit", c.Unit).Str("factor", c.Factor.String()).Msg("Setting item unit")
+	if err := s.repo.SetItemUnit(ctx, c); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", c.ItemID).Str("unit", c.Unit).Msg("Failed to set item unit")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+func (s *UnitsUsecase) DeleteItemUnit(ctx context.Context, itemID int64, unit string) error {
+	if itemID <= 0 {
+		return customErr.ErrInvalidInput
+	}
+	if err := s.repo.DeleteItemUnit(ctx, itemID, strings.ToLower(unit)); err != nil {
+		s.logger.Error().Err(err).Int64("item_id", itemID).Str("unit", unit).Msg("Failed to delete item unit")
+		return s.mapError(err)
+	}
+	return nil
+}
+
+// ToBase converts a quantity entered in any unit of the item into its base
+// unit.
+func (s *UnitsUsecase) ToBase(ctx context.Context, itemID int64, q domain.UnitQuantity) (int, error) {
+	return s.toBase(q, func(unit string) (*domain.ItemUnit, error) {
+		return s.repo.GetItemUnit(ctx, itemID, unit)
+	})
+}
+
+// ToBaseBySKU converts a quantity of the item with the given SKU into its
+// base unit.
+func (s *UnitsUsecase) ToBaseBySKU(ctx context.Context, sku string, q domain.UnitQuantity) (int, error) {
+	return s.toBase(q, func(unit string) (*domain.ItemUnit, error) {
+		return s.repo.GetItemUnitBySKU(ctx, sku, unit)
+	})
+}
+
+// toBase only looks the unit up when one is named: a quantity without a
+// unit is already in base units and merely has to be whole.
+func (s *UnitsUsecase) toBase(q domain.UnitQuantity, lookup func(unit string) (*domain.ItemUnit, error)) (int, error) {
+	unit := strings.ToLower(strings.TrimSpace(q.Unit))
+	if unit == "" {
+		n, ok := q.Quantity.Int64()
+		if !ok {
+			return 0, fmt.Errorf("%w: quantity %s is not a whole number of base units", customErr.ErrInvalidInput, q.Quantity)
+		}
+		return int(n), nil
+	}
+	c, err := lookup(unit)
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+	n, err := c.ToBase(q.Quantity)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", customErr.ErrInvalidInput, err)
+	}
+	return n, nil
+}
+
+func (s *UnitsUsecase) mapError(err error) error {
+	switch {
+	case errors.Is(err, customErr.ErrUnitNotFound):
+		return customErr.ErrUnitNotFound
+	case errors.Is(err, customErr.ErrUnitExists):
+		return customErr.ErrUnitExists
+	case errors.Is(err, customErr.ErrItemNotFound):
+		return customErr.ErrItemNotFound
+	case errors.Is(err, customErr.ErrInvalidInput):
+		return err
+	case errors.Is(err, customErr.ErrDatabase):
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
@@ -0,0 +1,12 @@
+package valuation_usecase
+
+import (
+	"context"
+	"time"
+
+	"warehouse-control/internal/domain"
+)
+
+type valuationRepository interface {
+	GetValuation(ctx context.Context, asOf time.Time) (*domain.Valuation, error)
+}
@@ -0,0 +1,48 @@
+package valuation_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"warehouse-control/internal/domain"
+
+	customErr "warehouse-control/internal/domain/errors"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type ValuationUsecase struct {
+	repo   valuationRepository
+	logger *zlog.Zerolog
+}
+
+func NewService(repo valuationRepository, logger *zlog.Zerolog) *ValuationUsecase {
+	return &ValuationUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetValuation values the inventory as of the given time; a zero time means
+// now.
+func (s *ValuationUsecase) GetValuation(ctx context.Context, asOf time.Time) (*domain.Valuation, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	s.logger.Info().Time("as_of", asOf).Msg("Getting inventory valuation")
+	valuation, err := s.repo.GetValuation(ctx, asOf)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to get inventory valuation")
+		return nil, s.mapError(err)
+	}
+	return valuation, nil
+}
+
+func (s *ValuationUsecase) mapError(err error) error {
+	if errors.Is(err, customErr.ErrDatabase) {
+		return customErr.ErrDatabase
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrInternal, err)
+}
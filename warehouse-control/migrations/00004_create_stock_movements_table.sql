@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    movement_type TEXT NOT NULL CHECK (movement_type IN ('receipt', 'issue', 'adjustment', 'transfer')),
+    quantity INT NOT NULL,
+    reason_code TEXT NOT NULL DEFAULT '',
+    reference TEXT NOT NULL DEFAULT '',
+    from_location TEXT NOT NULL DEFAULT '',
+    to_location TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_item_id ON stock_movements(item_id, created_at DESC);
+
+INSERT INTO stock_movements (item_id, movement_type, quantity, reason_code, created_by)
+SELECT id, 'adjustment', quantity, 'opening_balance', 'system'
+FROM items
+WHERE quantity <> 0;
+
+-- +goose Down
+DROP TABLE IF EXISTS stock_movements;
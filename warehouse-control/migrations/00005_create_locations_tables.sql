@@ -0,0 +1,90 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS locations (
+    id SERIAL PRIMARY KEY,
+    parent_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    warehouse_id INT REFERENCES locations(id),
+    location_type TEXT NOT NULL CHECK (location_type IN ('warehouse', 'zone', 'aisle', 'bin')),
+    code TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    path TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK ((location_type = 'warehouse') = (parent_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_locations_parent_id ON locations(parent_id);
+CREATE INDEX IF NOT EXISTS idx_locations_warehouse_id ON locations(warehouse_id);
+
+CREATE TABLE IF NOT EXISTS item_stock (
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id INT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    PRIMARY KEY (item_id, location_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_stock_location_id ON item_stock(location_id);
+
+ALTER TABLE stock_movements
+    ADD COLUMN from_location_id INT REFERENCES locations(id) ON DELETE SET NULL,
+    ADD COLUMN to_location_id INT REFERENCES locations(id) ON DELETE SET NULL;
+
+-- Free-text locations become bins under a MAIN/LEGACY/LEGACY branch so that
+-- nothing is lost; they can be moved into a proper layout afterwards.
+SELECT set_config('warehouse_control.changed_by', 'migration', true);
+
+INSERT INTO locations (location_type, code, name, path)
+VALUES ('warehouse', 'MAIN', 'Main warehouse', 'MAIN');
+UPDATE locations SET warehouse_id = id WHERE path = 'MAIN';
+
+INSERT INTO locations (parent_id, warehouse_id, location_type, code, name, path)
+SELECT id, id, 'zone', 'LEGACY', 'Migrated locations', 'MAIN/LEGACY' FROM locations WHERE path = 'MAIN';
+
+INSERT INTO locations (parent_id, warehouse_id, location_type, code, name, path)
+SELECT id, warehouse_id, 'aisle', 'LEGACY', 'Migrated locations', 'MAIN/LEGACY/LEGACY' FROM locations WHERE path = 'MAIN/LEGACY';
+
+INSERT INTO locations (parent_id, warehouse_id, location_type, code, name, path)
+SELECT a.id, a.warehouse_id, 'bin', l.code, l.code, 'MAIN/LEGACY/LEGACY/' || l.code
+FROM locations a
+CROSS JOIN (SELECT DISTINCT TRIM(location) AS code FROM items WHERE TRIM(COALESCE(location, '')) <> '') l
+WHERE a.path = 'MAIN/LEGACY/LEGACY';
+
+INSERT INTO item_stock (item_id, location_id, quantity)
+SELECT i.id, l.id, i.quantity
+FROM items i
+JOIN locations l ON l.path = 'MAIN/LEGACY/LEGACY/' || TRIM(i.location)
+WHERE i.quantity > 0;
+
+INSERT INTO stock_movements (item_id, movement_type, quantity, reason_code, to_location, to_location_id, created_by)
+SELECT s.item_id, 'transfer', s.quantity, 'location_migration', l.path, l.id, 'migration'
+FROM item_stock s
+JOIN locations l ON l.id = s.location_id;
+
+-- The free-text values are kept for a rollback to restore.
+CREATE TABLE IF NOT EXISTS items_legacy_location (
+    item_id INT PRIMARY KEY REFERENCES items(id) ON DELETE CASCADE,
+    location TEXT NOT NULL
+);
+
+INSERT INTO items_legacy_location (item_id, location)
+SELECT id, location FROM items WHERE TRIM(COALESCE(location, '')) <> '';
+
+UPDATE items i SET location = l.path
+FROM locations l
+WHERE l.path = 'MAIN/LEGACY/LEGACY/' || TRIM(i.location);
+
+-- +goose Down
+-- Items still in the bin their free-text location became get it back; the
+-- others keep the path of the bin they were moved to.
+SELECT set_config('warehouse_control.changed_by', 'migration', true);
+
+UPDATE items i SET location = ll.location
+FROM items_legacy_location ll
+WHERE ll.item_id = i.id AND i.location = 'MAIN/LEGACY/LEGACY/' || TRIM(ll.location);
+
+DROP TABLE IF EXISTS items_legacy_location;
+
+ALTER TABLE stock_movements
+    DROP COLUMN IF EXISTS from_location_id,
+    DROP COLUMN IF EXISTS to_location_id;
+DROP TABLE IF EXISTS item_stock;
+DROP TABLE IF EXISTS locations;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reservations (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    fulfilled_quantity INT NOT NULL DEFAULT 0 CHECK (fulfilled_quantity >= 0 AND fulfilled_quantity <= quantity),
+    reference TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'released', 'expired', 'fulfilled')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_item_id ON reservations(item_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reservations_active ON reservations(item_id, expires_at) WHERE status = 'active';
+
+ALTER TABLE stock_movements
+    ADD COLUMN reservation_id INT REFERENCES reservations(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS reservation_id;
+DROP TABLE IF EXISTS reservations;
@@ -0,0 +1,65 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS lots (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    lot_number TEXT NOT NULL,
+    manufactured_at DATE,
+    expires_at DATE,
+    quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (item_id, lot_number),
+    CHECK (manufactured_at IS NULL OR expires_at IS NULL OR manufactured_at <= expires_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lots_expires_at ON lots(expires_at) WHERE quantity > 0;
+
+CREATE TABLE IF NOT EXISTS movement_lots (
+    movement_id INT NOT NULL REFERENCES stock_movements(id) ON DELETE CASCADE,
+    lot_id INT NOT NULL REFERENCES lots(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    PRIMARY KEY (movement_id, lot_id)
+);
+
+CREATE TABLE IF NOT EXISTS lots_history (
+    id SERIAL PRIMARY KEY,
+    lot_id INT NOT NULL,
+    item_id INT NOT NULL,
+    action TEXT NOT NULL,
+    old_data JSONB,
+    new_data JSONB,
+    changed_by TEXT NOT NULL,
+    changed_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lots_history_lot_id ON lots_history(lot_id, changed_at DESC);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION log_lot_changes() RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO lots_history (lot_id, item_id, action, old_data, new_data, changed_by)
+        VALUES (NEW.id, NEW.item_id, 'INSERT', NULL, row_to_json(NEW)::jsonb, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+    ELSIF (TG_OP = 'UPDATE') THEN
+        INSERT INTO lots_history (lot_id, item_id, action, old_data, new_data, changed_by)
+        VALUES (NEW.id, NEW.item_id, 'UPDATE', row_to_json(OLD)::jsonb, row_to_json(NEW)::jsonb, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+    ELSIF (TG_OP = 'DELETE') THEN
+        INSERT INTO lots_history (lot_id, item_id, action, old_data, new_data, changed_by)
+        VALUES (OLD.id, OLD.item_id, 'DELETE', row_to_json(OLD)::jsonb, NULL, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER lots_history_trigger
+AFTER INSERT OR UPDATE OR DELETE ON lots
+FOR EACH ROW EXECUTE PROCEDURE log_lot_changes();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS lots_history_trigger ON lots;
+DROP FUNCTION IF EXISTS log_lot_changes();
+DROP TABLE IF EXISTS lots_history;
+DROP TABLE IF EXISTS movement_lots;
+DROP TABLE IF EXISTS lots;
@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN IF NOT EXISTS serialized BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS serials (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    serial_number TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL CHECK (status IN ('in_stock', 'issued')),
+    location_id INT REFERENCES locations(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_serials_item_id ON serials(item_id, status);
+
+CREATE TABLE IF NOT EXISTS movement_serials (
+    movement_id INT NOT NULL REFERENCES stock_movements(id) ON DELETE CASCADE,
+    serial_id INT NOT NULL REFERENCES serials(id) ON DELETE CASCADE,
+    PRIMARY KEY (movement_id, serial_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movement_serials_serial_id ON movement_serials(serial_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS movement_serials;
+DROP TABLE IF EXISTS serials;
+ALTER TABLE items DROP COLUMN IF EXISTS serialized;
@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN IF NOT EXISTS reorder_level INT NOT NULL DEFAULT 0 CHECK (reorder_level >= 0);
+ALTER TABLE items ADD COLUMN IF NOT EXISTS reorder_quantity INT NOT NULL DEFAULT 0 CHECK (reorder_quantity >= 0);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    reorder_level INT NOT NULL,
+    reorder_quantity INT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'acknowledged', 'resolved')),
+    acknowledged_by TEXT,
+    acknowledged_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- At most one unresolved alert per item: an item that stays low does not
+-- raise a new alert until the previous one is resolved by a restock.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_item_unresolved ON alerts(item_id) WHERE status <> 'resolved';
+CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS alerts;
+ALTER TABLE items DROP COLUMN IF EXISTS reorder_quantity;
+ALTER TABLE items DROP COLUMN IF EXISTS reorder_level;
@@ -0,0 +1,73 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS stocktakes (
+    id SERIAL PRIMARY KEY,
+    status TEXT NOT NULL DEFAULT 'counting' CHECK (status IN ('counting', 'review', 'posted', 'cancelled')),
+    blind BOOLEAN NOT NULL DEFAULT TRUE,
+    location_ids INT[] NOT NULL DEFAULT '{}',
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    posted_by TEXT,
+    posted_at TIMESTAMPTZ
+);
+
+-- A line is one item in one bin, or the whole item when location_id is NULL.
+CREATE TABLE IF NOT EXISTS stocktake_lines (
+    id SERIAL PRIMARY KEY,
+    stocktake_id INT NOT NULL REFERENCES stocktakes(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id INT REFERENCES locations(id) ON DELETE CASCADE,
+    counted_quantity INT CHECK (counted_quantity >= 0),
+    counted_by TEXT,
+    counted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stocktake_lines_unique ON stocktake_lines(stocktake_id, item_id, COALESCE(location_id, 0));
+
+ALTER TABLE items_history ADD COLUMN IF NOT EXISTS stocktake_id INT;
+CREATE INDEX IF NOT EXISTS idx_items_history_stocktake_id ON items_history(stocktake_id) WHERE stocktake_id IS NOT NULL;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION log_item_changes() RETURNS TRIGGER AS $$
+DECLARE
+    session_id INT := NULLIF(current_setting('warehouse_control.stocktake_id', TRUE), '')::INT;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO items_history (item_id, action, old_data, new_data, changed_by, stocktake_id)
+        VALUES (NEW.id, 'INSERT', NULL, row_to_json(NEW)::jsonb, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'), session_id);
+    ELSIF (TG_OP = 'UPDATE') THEN
+        INSERT INTO items_history (item_id, action, old_data, new_data, changed_by, stocktake_id)
+        VALUES (NEW.id, 'UPDATE', row_to_json(OLD)::jsonb, row_to_json(NEW)::jsonb, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'), session_id);
+    ELSIF (TG_OP = 'DELETE') THEN
+        INSERT INTO items_history (item_id, action, old_data, new_data, changed_by, stocktake_id)
+        VALUES (OLD.id, 'DELETE', row_to_json(OLD)::jsonb, NULL, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'), session_id);
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION log_item_changes() RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO items_history (item_id, action, old_data, new_data, changed_by)
+        VALUES (NEW.id, 'INSERT', NULL, row_to_json(NEW)::jsonb, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+    ELSIF (TG_OP = 'UPDATE') THEN
+        INSERT INTO items_history (item_id, action, old_data, new_data, changed_by)
+        VALUES (NEW.id, 'UPDATE', row_to_json(OLD)::jsonb, row_to_json(NEW)::jsonb, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+    ELSIF (TG_OP = 'DELETE') THEN
+        INSERT INTO items_history (item_id, action, old_data, new_data, changed_by)
+        VALUES (OLD.id, 'DELETE', row_to_json(OLD)::jsonb, NULL, COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE items_history DROP COLUMN IF EXISTS stocktake_id;
+DROP TABLE IF EXISTS stocktake_lines;
+DROP TABLE IF EXISTS stocktakes;
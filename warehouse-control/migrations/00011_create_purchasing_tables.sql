@@ -0,0 +1,45 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+    number TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'partially_received', 'received', 'cancelled')),
+    expected_at DATE,
+    notes TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    closed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders(supplier_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    sku TEXT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    received_quantity INT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_price FLOAT NOT NULL DEFAULT 0 CHECK (unit_price >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_order_id ON purchase_order_lines(order_id);
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS purchase_order_line_id INT REFERENCES purchase_order_lines(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS purchase_order_line_id;
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS suppliers;
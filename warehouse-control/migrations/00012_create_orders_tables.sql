@@ -0,0 +1,54 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    number TEXT NOT NULL UNIQUE,
+    customer TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'allocated', 'picked', 'shipped', 'cancelled')),
+    notes TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    shipped_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS order_lines (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    sku TEXT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    reservation_id INT REFERENCES reservations(id) ON DELETE SET NULL,
+    UNIQUE (order_id, item_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_picks (
+    id SERIAL PRIMARY KEY,
+    line_id INT NOT NULL REFERENCES order_lines(id) ON DELETE CASCADE,
+    location_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    serials TEXT[] NOT NULL DEFAULT '{}',
+    picked_by TEXT NOT NULL,
+    picked_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_picks_line_id ON order_picks(line_id);
+
+CREATE TABLE IF NOT EXISTS order_history (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    changed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_history_order_id ON order_history(order_id, changed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS order_history;
+DROP TABLE IF EXISTS order_picks;
+DROP TABLE IF EXISTS order_lines;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,55 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN IF NOT EXISTS quarantine_quantity INT NOT NULL DEFAULT 0;
+ALTER TABLE items ADD CONSTRAINT items_quarantine_quantity_check
+    CHECK (quarantine_quantity >= 0 AND quarantine_quantity <= quantity);
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS quarantine BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS returns (
+    id SERIAL PRIMARY KEY,
+    number TEXT NOT NULL UNIQUE,
+    order_id INT REFERENCES orders(id) ON DELETE RESTRICT,
+    customer TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'received', 'closed', 'cancelled')),
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    received_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_returns_order_id ON returns(order_id);
+
+CREATE TABLE IF NOT EXISTS return_lines (
+    id SERIAL PRIMARY KEY,
+    return_id INT NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    sku TEXT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    received_quantity INT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0 AND received_quantity <= quantity),
+    location_id INT REFERENCES locations(id) ON DELETE RESTRICT,
+    serials TEXT[] NOT NULL DEFAULT '{}',
+    UNIQUE (return_id, item_id)
+);
+
+CREATE TABLE IF NOT EXISTS return_dispositions (
+    id SERIAL PRIMARY KEY,
+    line_id INT NOT NULL REFERENCES return_lines(id) ON DELETE CASCADE,
+    disposition TEXT NOT NULL CHECK (disposition IN ('restock', 'scrap', 'return_to_vendor')),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    serials TEXT[] NOT NULL DEFAULT '{}',
+    supplier_id INT REFERENCES suppliers(id) ON DELETE RESTRICT,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_dispositions_line_id ON return_dispositions(line_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS return_dispositions;
+DROP TABLE IF EXISTS return_lines;
+DROP TABLE IF EXISTS returns;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS quarantine;
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_quarantine_quantity_check;
+ALTER TABLE items DROP COLUMN IF EXISTS quarantine_quantity;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN IF NOT EXISTS damaged_quantity INT NOT NULL DEFAULT 0;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS blocked_quantity INT NOT NULL DEFAULT 0;
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_quarantine_quantity_check;
+ALTER TABLE items ADD CONSTRAINT items_stock_status_check
+    CHECK (quarantine_quantity >= 0 AND damaged_quantity >= 0 AND blocked_quantity >= 0
+           AND quarantine_quantity + damaged_quantity + blocked_quantity <= quantity);
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS stock_status TEXT NOT NULL DEFAULT 'available'
+    CHECK (stock_status IN ('available', 'quarantine', 'damaged', 'blocked'));
+UPDATE stock_movements SET stock_status = 'quarantine' WHERE quarantine;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS quarantine;
+
+-- +goose Down
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS quarantine BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE stock_movements SET quarantine = TRUE WHERE stock_status = 'quarantine';
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS stock_status;
+
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_stock_status_check;
+ALTER TABLE items ADD CONSTRAINT items_quarantine_quantity_check
+    CHECK (quarantine_quantity >= 0 AND quarantine_quantity <= quantity);
+ALTER TABLE items DROP COLUMN IF EXISTS blocked_quantity;
+ALTER TABLE items DROP COLUMN IF EXISTS damaged_quantity;
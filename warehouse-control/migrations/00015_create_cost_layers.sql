@@ -0,0 +1,41 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN IF NOT EXISTS cost_method TEXT CHECK (cost_method IN ('fifo', 'average'));
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(18, 4) NOT NULL DEFAULT 0;
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS total_cost NUMERIC(18, 4) NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_stock_movements_created_at ON stock_movements(created_at);
+
+CREATE TABLE IF NOT EXISTS cost_layers (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    movement_id INT REFERENCES stock_movements(id) ON DELETE SET NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    remaining INT NOT NULL CHECK (remaining >= 0 AND remaining <= quantity),
+    unit_cost NUMERIC(18, 4) NOT NULL CHECK (unit_cost >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cost_layers_open ON cost_layers(item_id, created_at, id) WHERE remaining > 0;
+
+-- Stock on hand has no cost basis yet; the item price is the best estimate.
+INSERT INTO cost_layers (item_id, quantity, remaining, unit_cost)
+SELECT id, quantity, quantity, GREATEST(price, 0)::NUMERIC(18, 4) FROM items WHERE quantity > 0;
+
+UPDATE stock_movements m
+SET unit_cost = GREATEST(i.price, 0)::NUMERIC(18, 4),
+    total_cost = ABS(m.quantity) * GREATEST(i.price, 0)::NUMERIC(18, 4)
+FROM items i
+WHERE i.id = m.item_id;
+
+ALTER TABLE purchase_order_lines ALTER COLUMN unit_price TYPE NUMERIC(18, 4);
+
+-- +goose Down
+ALTER TABLE purchase_order_lines ALTER COLUMN unit_price TYPE FLOAT;
+
+DROP TABLE IF EXISTS cost_layers;
+
+DROP INDEX IF EXISTS idx_stock_movements_created_at;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS total_cost;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS unit_cost;
+
+ALTER TABLE items DROP COLUMN IF EXISTS cost_method;
@@ -0,0 +1,36 @@
+-- +goose Up
+ALTER TABLE items ALTER COLUMN price TYPE NUMERIC(18, 4) USING ROUND(price::NUMERIC, 4);
+
+-- Prices stored so far are in the base currency the application is
+-- configured with, so goose has to run with MONEY_BASE_CURRENCY set and
+-- refuses to apply the migration without it. The default only fills
+-- existing rows: new items always name their currency.
+-- +goose ENVSUB ON
+ALTER TABLE items ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT '${MONEY_BASE_CURRENCY:?}' CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE items ALTER COLUMN currency DROP DEFAULT;
+
+-- Snapshots logged before the switch carry binary floats; store them as the
+-- exact amounts the items now hold.
+UPDATE items_history
+SET old_data = jsonb_set(old_data, '{price}', to_jsonb(ROUND((old_data->>'price')::NUMERIC, 4))) || jsonb_build_object('currency', '${MONEY_BASE_CURRENCY:?}')
+WHERE old_data ? 'price' AND NOT old_data ? 'currency';
+UPDATE items_history
+SET new_data = jsonb_set(new_data, '{price}', to_jsonb(ROUND((new_data->>'price')::NUMERIC, 4))) || jsonb_build_object('currency', '${MONEY_BASE_CURRENCY:?}')
+WHERE new_data ? 'price' AND NOT new_data ? 'currency';
+-- +goose ENVSUB OFF
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency CHAR(3) PRIMARY KEY CHECK (currency ~ '^[A-Z]{3}$'),
+    rate NUMERIC(24, 10) NOT NULL CHECK (rate > 0),
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS exchange_rates;
+
+UPDATE items_history SET old_data = old_data - 'currency' WHERE old_data ? 'currency';
+UPDATE items_history SET new_data = new_data - 'currency' WHERE new_data ? 'currency';
+
+ALTER TABLE items DROP COLUMN IF EXISTS currency;
+ALTER TABLE items ALTER COLUMN price TYPE FLOAT USING price::FLOAT;
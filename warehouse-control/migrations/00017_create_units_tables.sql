@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS units (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    discrete BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO units (code, name, discrete) VALUES ('pcs', 'Piece', TRUE) ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS base_unit TEXT NOT NULL DEFAULT 'pcs' REFERENCES units(code);
+
+CREATE TABLE IF NOT EXISTS item_units (
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    unit_code TEXT NOT NULL REFERENCES units(code),
+    factor NUMERIC(18, 6) NOT NULL CHECK (factor > 0),
+    PRIMARY KEY (item_id, unit_code)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS item_units;
+ALTER TABLE items DROP COLUMN IF EXISTS base_unit;
+DROP TABLE IF EXISTS units;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS kit_components (
+    kit_item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    component_item_id INT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (kit_item_id, component_item_id),
+    CHECK (kit_item_id <> component_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kit_components_component ON kit_components(component_item_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS kit_components;
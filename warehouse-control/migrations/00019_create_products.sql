@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    sku TEXT NOT NULL UNIQUE,
+    category TEXT NOT NULL DEFAULT '',
+    price NUMERIC(18, 4) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    currency CHAR(3) NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    attributes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS product_id INT REFERENCES products(id) ON DELETE RESTRICT;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS variant JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_items_product ON items(product_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_product_variant ON items(product_id, variant) WHERE product_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_product_variant;
+DROP INDEX IF EXISTS idx_items_product;
+ALTER TABLE items DROP COLUMN IF EXISTS variant;
+ALTER TABLE items DROP COLUMN IF EXISTS product_id;
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,62 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL CHECK (name <> '' AND strpos(name, '/') = 0),
+    parent_id INT REFERENCES categories(id) ON DELETE RESTRICT,
+    path TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent_id);
+
+ALTER TABLE products ALTER COLUMN category DROP NOT NULL, ALTER COLUMN category DROP DEFAULT;
+
+SELECT set_config('warehouse_control.changed_by', 'migration', true);
+
+-- Existing free-text categories become nodes of the tree, "/" separating
+-- the levels. Blank parts are dropped and blank categories become NULL.
+UPDATE items SET category = normalized
+FROM (
+    SELECT i.id, (SELECT string_agg(trim(part), '/' ORDER BY n)
+                  FROM unnest(string_to_array(i.category, '/')) WITH ORDINALITY AS u(part, n)
+                  WHERE trim(part) <> '') AS normalized
+    FROM items i
+) c
+WHERE c.id = items.id AND items.category IS DISTINCT FROM c.normalized;
+
+UPDATE products SET category = normalized
+FROM (
+    SELECT p.id, (SELECT string_agg(trim(part), '/' ORDER BY n)
+                  FROM unnest(string_to_array(p.category, '/')) WITH ORDINALITY AS u(part, n)
+                  WHERE trim(part) <> '') AS normalized
+    FROM products p
+) c
+WHERE c.id = products.id AND products.category IS DISTINCT FROM c.normalized;
+
+INSERT INTO categories (name, path)
+SELECT DISTINCT c.parts[n], array_to_string(c.parts[1:n], '/')
+FROM (
+    SELECT string_to_array(category, '/') AS parts FROM items WHERE category IS NOT NULL
+    UNION
+    SELECT string_to_array(category, '/') FROM products WHERE category IS NOT NULL
+) c, generate_series(1, array_length(c.parts, 1)) AS n
+ON CONFLICT (path) DO NOTHING;
+
+UPDATE categories c SET parent_id = p.id
+FROM categories p
+WHERE p.path = left(c.path, length(c.path) - length(c.name) - 1);
+
+-- Items and products name their category by path; renaming or moving a
+-- category rewrites the paths, which cascade to them.
+ALTER TABLE items ADD CONSTRAINT items_category_fkey
+    FOREIGN KEY (category) REFERENCES categories(path) ON UPDATE CASCADE ON DELETE RESTRICT;
+ALTER TABLE products ADD CONSTRAINT products_category_fkey
+    FOREIGN KEY (category) REFERENCES categories(path) ON UPDATE CASCADE ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_category_fkey;
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_category_fkey;
+UPDATE products SET category = '' WHERE category IS NULL;
+ALTER TABLE products ALTER COLUMN category SET DEFAULT '', ALTER COLUMN category SET NOT NULL;
+DROP TABLE IF EXISTS categories;
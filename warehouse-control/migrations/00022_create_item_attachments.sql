@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS item_attachments (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    thumbnail_key TEXT,
+    uploaded_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_attachments_item ON item_attachments(item_id);
+
+-- Adding and removing an attachment is part of the item's history; the
+-- record carries the attachment instead of item snapshots.
+ALTER TABLE items_history ADD COLUMN IF NOT EXISTS attachment JSONB;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION log_attachment_changes() RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO items_history (item_id, action, attachment, changed_by)
+        VALUES (NEW.item_id, 'ATTACHMENT_ADDED',
+                jsonb_build_object('id', NEW.id, 'file_name', NEW.file_name, 'content_type', NEW.content_type, 'size', NEW.size),
+                COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+        RETURN NEW;
+    END IF;
+    INSERT INTO items_history (item_id, action, attachment, changed_by)
+    VALUES (OLD.item_id, 'ATTACHMENT_REMOVED',
+            jsonb_build_object('id', OLD.id, 'file_name', OLD.file_name, 'content_type', OLD.content_type, 'size', OLD.size),
+            COALESCE(current_setting('warehouse_control.changed_by', TRUE), 'unknown'));
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER item_attachments_history_trigger
+AFTER INSERT OR DELETE ON item_attachments
+FOR EACH ROW EXECUTE PROCEDURE log_attachment_changes();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS item_attachments_history_trigger ON item_attachments;
+DROP FUNCTION IF EXISTS log_attachment_changes();
+DELETE FROM items_history WHERE action IN ('ATTACHMENT_ADDED', 'ATTACHMENT_REMOVED');
+ALTER TABLE items_history DROP COLUMN IF EXISTS attachment;
+DROP TABLE IF EXISTS item_attachments;
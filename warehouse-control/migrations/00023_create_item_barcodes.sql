@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS item_barcodes (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    barcode_type TEXT NOT NULL CHECK (barcode_type IN ('gtin', 'supplier', 'internal')),
+    supplier_id INT REFERENCES suppliers(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT item_barcodes_code_key UNIQUE (code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_barcodes_item ON item_barcodes(item_id);
+
+-- GTINs are compared padded to 14 digits, so a UPC-A and the same number as
+-- an EAN-13 are one code.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_item_barcodes_gtin
+    ON item_barcodes (lpad(code, 14, '0')) WHERE barcode_type = 'gtin';
+
+-- Scanning resolves lot numbers without knowing the item.
+CREATE INDEX IF NOT EXISTS idx_lots_lot_number ON lots(lot_number);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_lots_lot_number;
+DROP TABLE IF EXISTS item_barcodes;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- History is listed and exported newest first; the index lets the export
+-- cursor stream rows in that order instead of sorting the whole table.
+CREATE INDEX IF NOT EXISTS idx_items_history_changed_at ON items_history(changed_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_history_changed_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Valuation as of a past date looks up the item snapshots around that date;
+-- the index finds them per item in time order.
+CREATE INDEX IF NOT EXISTS idx_items_history_item_changed_at ON items_history(item_id, changed_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_history_item_changed_at;